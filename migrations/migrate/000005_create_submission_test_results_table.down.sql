@@ -0,0 +1 @@
+DROP TABLE IF EXISTS submission_test_results;
\ No newline at end of file
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS submission_test_results (
+    submission_id UUID NOT NULL REFERENCES submissions(id) ON DELETE CASCADE,
+    test_index INTEGER NOT NULL,
+    status VARCHAR(50) NOT NULL,
+    time_ms BIGINT NOT NULL DEFAULT 0,
+    exit_code INTEGER NOT NULL DEFAULT 0,
+    stderr TEXT NOT NULL DEFAULT '',
+    PRIMARY KEY (submission_id, test_index)
+);
\ No newline at end of file
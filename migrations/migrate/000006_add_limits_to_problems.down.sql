@@ -0,0 +1 @@
+ALTER TABLE problems DROP COLUMN IF EXISTS time_limit_ms, DROP COLUMN IF EXISTS memory_limit_mb;
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE problems
+    ADD COLUMN IF NOT EXISTS time_limit_ms INTEGER NOT NULL DEFAULT 2000,
+    ADD COLUMN IF NOT EXISTS memory_limit_mb INTEGER NOT NULL DEFAULT 128;
\ No newline at end of file
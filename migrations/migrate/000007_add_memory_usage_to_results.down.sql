@@ -0,0 +1 @@
+ALTER TABLE submission_test_results DROP COLUMN IF EXISTS memory_kb; ALTER TABLE submissions DROP COLUMN IF EXISTS max_memory_kb;
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE submissions ADD COLUMN IF NOT EXISTS max_memory_kb BIGINT NOT NULL DEFAULT 0;
+
+ALTER TABLE submission_test_results ADD COLUMN IF NOT EXISTS memory_kb BIGINT NOT NULL DEFAULT 0;
\ No newline at end of file
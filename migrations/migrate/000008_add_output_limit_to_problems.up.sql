@@ -0,0 +1 @@
+ALTER TABLE problems ADD COLUMN IF NOT EXISTS output_limit_kb INTEGER NOT NULL DEFAULT 16384;
\ No newline at end of file
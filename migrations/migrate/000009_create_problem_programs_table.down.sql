@@ -0,0 +1 @@
+DROP TABLE IF EXISTS problem_programs;
\ No newline at end of file
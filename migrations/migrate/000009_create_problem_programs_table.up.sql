@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS problem_programs (
+    problem_id UUID NOT NULL REFERENCES problems(id) ON DELETE CASCADE,
+    kind VARCHAR(20) NOT NULL,
+    language VARCHAR(50) NOT NULL,
+    source_code TEXT NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (problem_id, kind)
+);
\ No newline at end of file
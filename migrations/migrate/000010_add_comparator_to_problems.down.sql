@@ -0,0 +1 @@
+ALTER TABLE problems DROP COLUMN IF EXISTS comparator, DROP COLUMN IF EXISTS float_abs_eps, DROP COLUMN IF EXISTS float_rel_eps;
\ No newline at end of file
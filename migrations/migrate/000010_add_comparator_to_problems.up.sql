@@ -0,0 +1,4 @@
+ALTER TABLE problems
+    ADD COLUMN IF NOT EXISTS comparator VARCHAR(32) NOT NULL DEFAULT 'trim',
+    ADD COLUMN IF NOT EXISTS float_abs_eps DOUBLE PRECISION NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS float_rel_eps DOUBLE PRECISION NOT NULL DEFAULT 0;
\ No newline at end of file
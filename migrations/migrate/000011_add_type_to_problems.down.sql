@@ -0,0 +1 @@
+ALTER TABLE problems DROP COLUMN IF EXISTS type;
\ No newline at end of file
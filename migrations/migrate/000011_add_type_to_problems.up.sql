@@ -0,0 +1,2 @@
+ALTER TABLE problems
+    ADD COLUMN IF NOT EXISTS type VARCHAR(20) NOT NULL DEFAULT 'standard';
\ No newline at end of file
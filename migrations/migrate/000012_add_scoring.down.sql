@@ -0,0 +1 @@
+ALTER TABLE submissions DROP COLUMN IF EXISTS score, DROP COLUMN IF EXISTS max_score; ALTER TABLE test_cases DROP COLUMN IF EXISTS points; ALTER TABLE problems DROP COLUMN IF EXISTS scoring_mode;
\ No newline at end of file
@@ -0,0 +1,7 @@
+ALTER TABLE problems ADD COLUMN IF NOT EXISTS scoring_mode VARCHAR(20) NOT NULL DEFAULT 'icpc';
+
+ALTER TABLE test_cases ADD COLUMN IF NOT EXISTS points INTEGER NOT NULL DEFAULT 1;
+
+ALTER TABLE submissions
+    ADD COLUMN IF NOT EXISTS score DOUBLE PRECISION NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS max_score DOUBLE PRECISION NOT NULL DEFAULT 0;
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE test_cases DROP COLUMN IF EXISTS group_id; DROP TABLE IF EXISTS test_group_dependencies; DROP TABLE IF EXISTS test_groups;
\ No newline at end of file
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS test_groups (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    problem_id UUID NOT NULL REFERENCES problems(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    score INTEGER NOT NULL DEFAULT 0,
+    aggregation VARCHAR(20) NOT NULL DEFAULT 'all_or_nothing',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS test_group_dependencies (
+    group_id UUID NOT NULL REFERENCES test_groups(id) ON DELETE CASCADE,
+    depends_on_id UUID NOT NULL REFERENCES test_groups(id) ON DELETE CASCADE,
+    PRIMARY KEY (group_id, depends_on_id)
+);
+
+ALTER TABLE test_cases ADD COLUMN IF NOT EXISTS group_id UUID REFERENCES test_groups(id) ON DELETE SET NULL;
\ No newline at end of file
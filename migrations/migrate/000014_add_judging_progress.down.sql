@@ -0,0 +1 @@
+ALTER TABLE submissions DROP COLUMN IF EXISTS stage, DROP COLUMN IF EXISTS current_test, DROP COLUMN IF EXISTS total_tests;
\ No newline at end of file
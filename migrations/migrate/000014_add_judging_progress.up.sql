@@ -0,0 +1,4 @@
+ALTER TABLE submissions
+    ADD COLUMN IF NOT EXISTS stage VARCHAR(20) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS current_test INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS total_tests INTEGER NOT NULL DEFAULT 0;
\ No newline at end of file
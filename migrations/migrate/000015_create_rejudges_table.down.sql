@@ -0,0 +1 @@
+DROP TABLE IF EXISTS rejudge_submissions; DROP TABLE IF EXISTS rejudges;
\ No newline at end of file
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS rejudges (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    requested_by UUID NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS rejudge_submissions (
+    rejudge_id UUID NOT NULL REFERENCES rejudges(id) ON DELETE CASCADE,
+    submission_id UUID NOT NULL REFERENCES submissions(id) ON DELETE CASCADE,
+    previous_status VARCHAR(50) NOT NULL,
+    previous_score DOUBLE PRECISION NOT NULL DEFAULT 0,
+    previous_max_memory_kb BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (rejudge_id, submission_id)
+);
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE problems DROP COLUMN IF EXISTS tests_version;
\ No newline at end of file
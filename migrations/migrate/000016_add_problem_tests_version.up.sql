@@ -0,0 +1 @@
+ALTER TABLE problems ADD COLUMN IF NOT EXISTS tests_version BIGINT NOT NULL DEFAULT 0;
\ No newline at end of file
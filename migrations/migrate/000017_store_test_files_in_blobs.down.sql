@@ -0,0 +1 @@
+ALTER TABLE test_cases DROP COLUMN IF EXISTS input_sha256, DROP COLUMN IF EXISTS input_size, DROP COLUMN IF EXISTS output_sha256, DROP COLUMN IF EXISTS output_size;
\ No newline at end of file
@@ -0,0 +1,5 @@
+ALTER TABLE test_cases
+    ADD COLUMN IF NOT EXISTS input_sha256 VARCHAR(64) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS input_size BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS output_sha256 VARCHAR(64) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS output_size BIGINT NOT NULL DEFAULT 0;
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE submissions ADD COLUMN IF NOT EXISTS files JSONB NOT NULL DEFAULT '[]';
\ No newline at end of file
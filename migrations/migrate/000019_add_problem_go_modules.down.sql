@@ -0,0 +1 @@
+ALTER TABLE problems DROP COLUMN IF EXISTS go_modules;
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE problems ADD COLUMN IF NOT EXISTS go_modules TEXT[] NOT NULL DEFAULT '{}';
\ No newline at end of file
@@ -25,6 +25,15 @@ type CreateProblemRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Title         string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
 	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	TimeLimitMs   int32                  `protobuf:"varint,3,opt,name=time_limit_ms,json=timeLimitMs,proto3" json:"time_limit_ms,omitempty"`
+	MemoryLimitMb int32                  `protobuf:"varint,4,opt,name=memory_limit_mb,json=memoryLimitMb,proto3" json:"memory_limit_mb,omitempty"`
+	OutputLimitKb int32                  `protobuf:"varint,5,opt,name=output_limit_kb,json=outputLimitKb,proto3" json:"output_limit_kb,omitempty"`
+	Comparator    string                 `protobuf:"bytes,6,opt,name=comparator,proto3" json:"comparator,omitempty"`
+	FloatAbsEps   float64                `protobuf:"fixed64,7,opt,name=float_abs_eps,json=floatAbsEps,proto3" json:"float_abs_eps,omitempty"`
+	FloatRelEps   float64                `protobuf:"fixed64,8,opt,name=float_rel_eps,json=floatRelEps,proto3" json:"float_rel_eps,omitempty"`
+	Type          string                 `protobuf:"bytes,9,opt,name=type,proto3" json:"type,omitempty"`
+	ScoringMode   string                 `protobuf:"bytes,10,opt,name=scoring_mode,json=scoringMode,proto3" json:"scoring_mode,omitempty"`
+	GoModules     []string               `protobuf:"bytes,11,rep,name=go_modules,json=goModules,proto3" json:"go_modules,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -73,6 +82,69 @@ func (x *CreateProblemRequest) GetDescription() string {
 	return ""
 }
 
+func (x *CreateProblemRequest) GetTimeLimitMs() int32 {
+	if x != nil {
+		return x.TimeLimitMs
+	}
+	return 0
+}
+
+func (x *CreateProblemRequest) GetMemoryLimitMb() int32 {
+	if x != nil {
+		return x.MemoryLimitMb
+	}
+	return 0
+}
+
+func (x *CreateProblemRequest) GetOutputLimitKb() int32 {
+	if x != nil {
+		return x.OutputLimitKb
+	}
+	return 0
+}
+
+func (x *CreateProblemRequest) GetComparator() string {
+	if x != nil {
+		return x.Comparator
+	}
+	return ""
+}
+
+func (x *CreateProblemRequest) GetFloatAbsEps() float64 {
+	if x != nil {
+		return x.FloatAbsEps
+	}
+	return 0
+}
+
+func (x *CreateProblemRequest) GetFloatRelEps() float64 {
+	if x != nil {
+		return x.FloatRelEps
+	}
+	return 0
+}
+
+func (x *CreateProblemRequest) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *CreateProblemRequest) GetScoringMode() string {
+	if x != nil {
+		return x.ScoringMode
+	}
+	return ""
+}
+
+func (x *CreateProblemRequest) GetGoModules() []string {
+	if x != nil {
+		return x.GoModules
+	}
+	return nil
+}
+
 type GetProblemRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -159,6 +231,18 @@ type Problem struct {
 	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
 	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
 	CreatedAt     string                 `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	TimeLimitMs   int32                  `protobuf:"varint,5,opt,name=time_limit_ms,json=timeLimitMs,proto3" json:"time_limit_ms,omitempty"`
+	MemoryLimitMb int32                  `protobuf:"varint,6,opt,name=memory_limit_mb,json=memoryLimitMb,proto3" json:"memory_limit_mb,omitempty"`
+	OutputLimitKb int32                  `protobuf:"varint,7,opt,name=output_limit_kb,json=outputLimitKb,proto3" json:"output_limit_kb,omitempty"`
+	Comparator    string                 `protobuf:"bytes,8,opt,name=comparator,proto3" json:"comparator,omitempty"`
+	FloatAbsEps   float64                `protobuf:"fixed64,9,opt,name=float_abs_eps,json=floatAbsEps,proto3" json:"float_abs_eps,omitempty"`
+	FloatRelEps   float64                `protobuf:"fixed64,10,opt,name=float_rel_eps,json=floatRelEps,proto3" json:"float_rel_eps,omitempty"`
+	Type          string                 `protobuf:"bytes,11,opt,name=type,proto3" json:"type,omitempty"`
+	ScoringMode   string                 `protobuf:"bytes,12,opt,name=scoring_mode,json=scoringMode,proto3" json:"scoring_mode,omitempty"`
+	TestsVersion  int64                  `protobuf:"varint,13,opt,name=tests_version,json=testsVersion,proto3" json:"tests_version,omitempty"`
+	// Third-party Go modules submissions may import. Workers have no network,
+	// so they have to be in the judge runtime's module cache as well.
+	GoModules     []string `protobuf:"bytes,14,rep,name=go_modules,json=goModules,proto3" json:"go_modules,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -221,6 +305,76 @@ func (x *Problem) GetCreatedAt() string {
 	return ""
 }
 
+func (x *Problem) GetTimeLimitMs() int32 {
+	if x != nil {
+		return x.TimeLimitMs
+	}
+	return 0
+}
+
+func (x *Problem) GetMemoryLimitMb() int32 {
+	if x != nil {
+		return x.MemoryLimitMb
+	}
+	return 0
+}
+
+func (x *Problem) GetOutputLimitKb() int32 {
+	if x != nil {
+		return x.OutputLimitKb
+	}
+	return 0
+}
+
+func (x *Problem) GetComparator() string {
+	if x != nil {
+		return x.Comparator
+	}
+	return ""
+}
+
+func (x *Problem) GetFloatAbsEps() float64 {
+	if x != nil {
+		return x.FloatAbsEps
+	}
+	return 0
+}
+
+func (x *Problem) GetFloatRelEps() float64 {
+	if x != nil {
+		return x.FloatRelEps
+	}
+	return 0
+}
+
+func (x *Problem) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Problem) GetScoringMode() string {
+	if x != nil {
+		return x.ScoringMode
+	}
+	return ""
+}
+
+func (x *Problem) GetTestsVersion() int64 {
+	if x != nil {
+		return x.TestsVersion
+	}
+	return 0
+}
+
+func (x *Problem) GetGoModules() []string {
+	if x != nil {
+		return x.GoModules
+	}
+	return nil
+}
+
 type ListProblemsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Problems      []*Problem             `protobuf:"bytes,1,rep,name=problems,proto3" json:"problems,omitempty"`
@@ -266,11 +420,20 @@ func (x *ListProblemsResponse) GetProblems() []*Problem {
 }
 
 type TestCase struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	ProblemId     string                 `protobuf:"bytes,2,opt,name=problem_id,json=problemId,proto3" json:"problem_id,omitempty"`
-	InputData     string                 `protobuf:"bytes,3,opt,name=input_data,json=inputData,proto3" json:"input_data,omitempty"`
-	OutputData    string                 `protobuf:"bytes,4,opt,name=output_data,json=outputData,proto3" json:"output_data,omitempty"`
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Id         string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ProblemId  string                 `protobuf:"bytes,2,opt,name=problem_id,json=problemId,proto3" json:"problem_id,omitempty"`
+	InputData  string                 `protobuf:"bytes,3,opt,name=input_data,json=inputData,proto3" json:"input_data,omitempty"`
+	OutputData string                 `protobuf:"bytes,4,opt,name=output_data,json=outputData,proto3" json:"output_data,omitempty"`
+	Points     int32                  `protobuf:"varint,5,opt,name=points,proto3" json:"points,omitempty"`
+	GroupId    string                 `protobuf:"bytes,6,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"`
+	// Test files live in blob storage: input_data and output_data are empty
+	// and have to be read with ReadTestFile. Only test cases created before
+	// blob storage carry their data inline and have no checksums.
+	InputSize     int64  `protobuf:"varint,7,opt,name=input_size,json=inputSize,proto3" json:"input_size,omitempty"`
+	InputSha256   string `protobuf:"bytes,8,opt,name=input_sha256,json=inputSha256,proto3" json:"input_sha256,omitempty"`
+	OutputSize    int64  `protobuf:"varint,9,opt,name=output_size,json=outputSize,proto3" json:"output_size,omitempty"`
+	OutputSha256  string `protobuf:"bytes,10,opt,name=output_sha256,json=outputSha256,proto3" json:"output_sha256,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -333,11 +496,59 @@ func (x *TestCase) GetOutputData() string {
 	return ""
 }
 
+func (x *TestCase) GetPoints() int32 {
+	if x != nil {
+		return x.Points
+	}
+	return 0
+}
+
+func (x *TestCase) GetGroupId() string {
+	if x != nil {
+		return x.GroupId
+	}
+	return ""
+}
+
+func (x *TestCase) GetInputSize() int64 {
+	if x != nil {
+		return x.InputSize
+	}
+	return 0
+}
+
+func (x *TestCase) GetInputSha256() string {
+	if x != nil {
+		return x.InputSha256
+	}
+	return ""
+}
+
+func (x *TestCase) GetOutputSize() int64 {
+	if x != nil {
+		return x.OutputSize
+	}
+	return 0
+}
+
+func (x *TestCase) GetOutputSha256() string {
+	if x != nil {
+		return x.OutputSha256
+	}
+	return ""
+}
+
+// Either the data or the checksum of a file uploaded with UploadTestFile is
+// set for input and output.
 type CreateTestCaseRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	ProblemId     string                 `protobuf:"bytes,1,opt,name=problem_id,json=problemId,proto3" json:"problem_id,omitempty"`
 	InputData     string                 `protobuf:"bytes,2,opt,name=input_data,json=inputData,proto3" json:"input_data,omitempty"`
 	OutputData    string                 `protobuf:"bytes,3,opt,name=output_data,json=outputData,proto3" json:"output_data,omitempty"`
+	Points        int32                  `protobuf:"varint,4,opt,name=points,proto3" json:"points,omitempty"`
+	GroupId       string                 `protobuf:"bytes,5,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"`
+	InputSha256   string                 `protobuf:"bytes,6,opt,name=input_sha256,json=inputSha256,proto3" json:"input_sha256,omitempty"`
+	OutputSha256  string                 `protobuf:"bytes,7,opt,name=output_sha256,json=outputSha256,proto3" json:"output_sha256,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -393,6 +604,174 @@ func (x *CreateTestCaseRequest) GetOutputData() string {
 	return ""
 }
 
+func (x *CreateTestCaseRequest) GetPoints() int32 {
+	if x != nil {
+		return x.Points
+	}
+	return 0
+}
+
+func (x *CreateTestCaseRequest) GetGroupId() string {
+	if x != nil {
+		return x.GroupId
+	}
+	return ""
+}
+
+func (x *CreateTestCaseRequest) GetInputSha256() string {
+	if x != nil {
+		return x.InputSha256
+	}
+	return ""
+}
+
+func (x *CreateTestCaseRequest) GetOutputSha256() string {
+	if x != nil {
+		return x.OutputSha256
+	}
+	return ""
+}
+
+type TestFileChunk struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Data          []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TestFileChunk) Reset() {
+	*x = TestFileChunk{}
+	mi := &file_problem_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TestFileChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TestFileChunk) ProtoMessage() {}
+
+func (x *TestFileChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_problem_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TestFileChunk.ProtoReflect.Descriptor instead.
+func (*TestFileChunk) Descriptor() ([]byte, []int) {
+	return file_problem_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *TestFileChunk) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type TestFile struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sha256        string                 `protobuf:"bytes,1,opt,name=sha256,proto3" json:"sha256,omitempty"`
+	Size          int64                  `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TestFile) Reset() {
+	*x = TestFile{}
+	mi := &file_problem_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TestFile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TestFile) ProtoMessage() {}
+
+func (x *TestFile) ProtoReflect() protoreflect.Message {
+	mi := &file_problem_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TestFile.ProtoReflect.Descriptor instead.
+func (*TestFile) Descriptor() ([]byte, []int) {
+	return file_problem_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *TestFile) GetSha256() string {
+	if x != nil {
+		return x.Sha256
+	}
+	return ""
+}
+
+func (x *TestFile) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+type ReadTestFileRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sha256        string                 `protobuf:"bytes,1,opt,name=sha256,proto3" json:"sha256,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReadTestFileRequest) Reset() {
+	*x = ReadTestFileRequest{}
+	mi := &file_problem_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReadTestFileRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadTestFileRequest) ProtoMessage() {}
+
+func (x *ReadTestFileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_problem_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadTestFileRequest.ProtoReflect.Descriptor instead.
+func (*ReadTestFileRequest) Descriptor() ([]byte, []int) {
+	return file_problem_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ReadTestFileRequest) GetSha256() string {
+	if x != nil {
+		return x.Sha256
+	}
+	return ""
+}
+
 type GetTestCasesRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	ProblemId     string                 `protobuf:"bytes,1,opt,name=problem_id,json=problemId,proto3" json:"problem_id,omitempty"`
@@ -402,7 +781,7 @@ type GetTestCasesRequest struct {
 
 func (x *GetTestCasesRequest) Reset() {
 	*x = GetTestCasesRequest{}
-	mi := &file_problem_proto_msgTypes[7]
+	mi := &file_problem_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -414,7 +793,7 @@ func (x *GetTestCasesRequest) String() string {
 func (*GetTestCasesRequest) ProtoMessage() {}
 
 func (x *GetTestCasesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_problem_proto_msgTypes[7]
+	mi := &file_problem_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -427,7 +806,7 @@ func (x *GetTestCasesRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetTestCasesRequest.ProtoReflect.Descriptor instead.
 func (*GetTestCasesRequest) Descriptor() ([]byte, []int) {
-	return file_problem_proto_rawDescGZIP(), []int{7}
+	return file_problem_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *GetTestCasesRequest) GetProblemId() string {
@@ -440,13 +819,14 @@ func (x *GetTestCasesRequest) GetProblemId() string {
 type GetTestCasesResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	TestCases     []*TestCase            `protobuf:"bytes,1,rep,name=test_cases,json=testCases,proto3" json:"test_cases,omitempty"`
+	Groups        []*TestGroup           `protobuf:"bytes,2,rep,name=groups,proto3" json:"groups,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *GetTestCasesResponse) Reset() {
 	*x = GetTestCasesResponse{}
-	mi := &file_problem_proto_msgTypes[8]
+	mi := &file_problem_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -458,7 +838,7 @@ func (x *GetTestCasesResponse) String() string {
 func (*GetTestCasesResponse) ProtoMessage() {}
 
 func (x *GetTestCasesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_problem_proto_msgTypes[8]
+	mi := &file_problem_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -471,7 +851,7 @@ func (x *GetTestCasesResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetTestCasesResponse.ProtoReflect.Descriptor instead.
 func (*GetTestCasesResponse) Descriptor() ([]byte, []int) {
-	return file_problem_proto_rawDescGZIP(), []int{8}
+	return file_problem_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *GetTestCasesResponse) GetTestCases() []*TestCase {
@@ -481,25 +861,479 @@ func (x *GetTestCasesResponse) GetTestCases() []*TestCase {
 	return nil
 }
 
-var File_problem_proto protoreflect.FileDescriptor
+func (x *GetTestCasesResponse) GetGroups() []*TestGroup {
+	if x != nil {
+		return x.Groups
+	}
+	return nil
+}
 
-const file_problem_proto_rawDesc = "" +
-	"\n" +
-	"\rproblem.proto\x12\aproblem\"N\n" +
-	"\x14CreateProblemRequest\x12\x14\n" +
-	"\x05title\x18\x01 \x01(\tR\x05title\x12 \n" +
-	"\vdescription\x18\x02 \x01(\tR\vdescription\"#\n" +
-	"\x11GetProblemRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\"\x15\n" +
-	"\x13ListProblemsRequest\"p\n" +
-	"\aProblem\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
-	"\x05title\x18\x02 \x01(\tR\x05title\x12 \n" +
-	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x1d\n" +
-	"\n" +
-	"created_at\x18\x04 \x01(\tR\tcreatedAt\"D\n" +
-	"\x14ListProblemsResponse\x12,\n" +
-	"\bproblems\x18\x01 \x03(\v2\x10.problem.ProblemR\bproblems\"y\n" +
+type TestGroup struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ProblemId     string                 `protobuf:"bytes,2,opt,name=problem_id,json=problemId,proto3" json:"problem_id,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Score         int32                  `protobuf:"varint,4,opt,name=score,proto3" json:"score,omitempty"`
+	Aggregation   string                 `protobuf:"bytes,5,opt,name=aggregation,proto3" json:"aggregation,omitempty"`
+	DependsOn     []string               `protobuf:"bytes,6,rep,name=depends_on,json=dependsOn,proto3" json:"depends_on,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TestGroup) Reset() {
+	*x = TestGroup{}
+	mi := &file_problem_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TestGroup) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TestGroup) ProtoMessage() {}
+
+func (x *TestGroup) ProtoReflect() protoreflect.Message {
+	mi := &file_problem_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TestGroup.ProtoReflect.Descriptor instead.
+func (*TestGroup) Descriptor() ([]byte, []int) {
+	return file_problem_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *TestGroup) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *TestGroup) GetProblemId() string {
+	if x != nil {
+		return x.ProblemId
+	}
+	return ""
+}
+
+func (x *TestGroup) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *TestGroup) GetScore() int32 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *TestGroup) GetAggregation() string {
+	if x != nil {
+		return x.Aggregation
+	}
+	return ""
+}
+
+func (x *TestGroup) GetDependsOn() []string {
+	if x != nil {
+		return x.DependsOn
+	}
+	return nil
+}
+
+type CreateTestGroupRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProblemId     string                 `protobuf:"bytes,1,opt,name=problem_id,json=problemId,proto3" json:"problem_id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Score         int32                  `protobuf:"varint,3,opt,name=score,proto3" json:"score,omitempty"`
+	Aggregation   string                 `protobuf:"bytes,4,opt,name=aggregation,proto3" json:"aggregation,omitempty"`
+	DependsOn     []string               `protobuf:"bytes,5,rep,name=depends_on,json=dependsOn,proto3" json:"depends_on,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateTestGroupRequest) Reset() {
+	*x = CreateTestGroupRequest{}
+	mi := &file_problem_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateTestGroupRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateTestGroupRequest) ProtoMessage() {}
+
+func (x *CreateTestGroupRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_problem_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateTestGroupRequest.ProtoReflect.Descriptor instead.
+func (*CreateTestGroupRequest) Descriptor() ([]byte, []int) {
+	return file_problem_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *CreateTestGroupRequest) GetProblemId() string {
+	if x != nil {
+		return x.ProblemId
+	}
+	return ""
+}
+
+func (x *CreateTestGroupRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateTestGroupRequest) GetScore() int32 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *CreateTestGroupRequest) GetAggregation() string {
+	if x != nil {
+		return x.Aggregation
+	}
+	return ""
+}
+
+func (x *CreateTestGroupRequest) GetDependsOn() []string {
+	if x != nil {
+		return x.DependsOn
+	}
+	return nil
+}
+
+type ListTestGroupsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProblemId     string                 `protobuf:"bytes,1,opt,name=problem_id,json=problemId,proto3" json:"problem_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTestGroupsRequest) Reset() {
+	*x = ListTestGroupsRequest{}
+	mi := &file_problem_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTestGroupsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTestGroupsRequest) ProtoMessage() {}
+
+func (x *ListTestGroupsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_problem_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTestGroupsRequest.ProtoReflect.Descriptor instead.
+func (*ListTestGroupsRequest) Descriptor() ([]byte, []int) {
+	return file_problem_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ListTestGroupsRequest) GetProblemId() string {
+	if x != nil {
+		return x.ProblemId
+	}
+	return ""
+}
+
+type ListTestGroupsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Groups        []*TestGroup           `protobuf:"bytes,1,rep,name=groups,proto3" json:"groups,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTestGroupsResponse) Reset() {
+	*x = ListTestGroupsResponse{}
+	mi := &file_problem_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTestGroupsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTestGroupsResponse) ProtoMessage() {}
+
+func (x *ListTestGroupsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_problem_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTestGroupsResponse.ProtoReflect.Descriptor instead.
+func (*ListTestGroupsResponse) Descriptor() ([]byte, []int) {
+	return file_problem_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ListTestGroupsResponse) GetGroups() []*TestGroup {
+	if x != nil {
+		return x.Groups
+	}
+	return nil
+}
+
+type Program struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProblemId     string                 `protobuf:"bytes,1,opt,name=problem_id,json=problemId,proto3" json:"problem_id,omitempty"`
+	Language      string                 `protobuf:"bytes,2,opt,name=language,proto3" json:"language,omitempty"`
+	SourceCode    string                 `protobuf:"bytes,3,opt,name=source_code,json=sourceCode,proto3" json:"source_code,omitempty"`
+	UpdatedAt     string                 `protobuf:"bytes,4,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Program) Reset() {
+	*x = Program{}
+	mi := &file_problem_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Program) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Program) ProtoMessage() {}
+
+func (x *Program) ProtoReflect() protoreflect.Message {
+	mi := &file_problem_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Program.ProtoReflect.Descriptor instead.
+func (*Program) Descriptor() ([]byte, []int) {
+	return file_problem_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *Program) GetProblemId() string {
+	if x != nil {
+		return x.ProblemId
+	}
+	return ""
+}
+
+func (x *Program) GetLanguage() string {
+	if x != nil {
+		return x.Language
+	}
+	return ""
+}
+
+func (x *Program) GetSourceCode() string {
+	if x != nil {
+		return x.SourceCode
+	}
+	return ""
+}
+
+func (x *Program) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+type SetProgramRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProblemId     string                 `protobuf:"bytes,1,opt,name=problem_id,json=problemId,proto3" json:"problem_id,omitempty"`
+	Language      string                 `protobuf:"bytes,2,opt,name=language,proto3" json:"language,omitempty"`
+	SourceCode    string                 `protobuf:"bytes,3,opt,name=source_code,json=sourceCode,proto3" json:"source_code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetProgramRequest) Reset() {
+	*x = SetProgramRequest{}
+	mi := &file_problem_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetProgramRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetProgramRequest) ProtoMessage() {}
+
+func (x *SetProgramRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_problem_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetProgramRequest.ProtoReflect.Descriptor instead.
+func (*SetProgramRequest) Descriptor() ([]byte, []int) {
+	return file_problem_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *SetProgramRequest) GetProblemId() string {
+	if x != nil {
+		return x.ProblemId
+	}
+	return ""
+}
+
+func (x *SetProgramRequest) GetLanguage() string {
+	if x != nil {
+		return x.Language
+	}
+	return ""
+}
+
+func (x *SetProgramRequest) GetSourceCode() string {
+	if x != nil {
+		return x.SourceCode
+	}
+	return ""
+}
+
+type GetProgramRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProblemId     string                 `protobuf:"bytes,1,opt,name=problem_id,json=problemId,proto3" json:"problem_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetProgramRequest) Reset() {
+	*x = GetProgramRequest{}
+	mi := &file_problem_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProgramRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProgramRequest) ProtoMessage() {}
+
+func (x *GetProgramRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_problem_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProgramRequest.ProtoReflect.Descriptor instead.
+func (*GetProgramRequest) Descriptor() ([]byte, []int) {
+	return file_problem_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *GetProgramRequest) GetProblemId() string {
+	if x != nil {
+		return x.ProblemId
+	}
+	return ""
+}
+
+var File_problem_proto protoreflect.FileDescriptor
+
+const file_problem_proto_rawDesc = "" +
+	"\n" +
+	"\rproblem.proto\x12\aproblem\"\x80\x03\n" +
+	"\x14CreateProblemRequest\x12\x14\n" +
+	"\x05title\x18\x01 \x01(\tR\x05title\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\"\n" +
+	"\rtime_limit_ms\x18\x03 \x01(\x05R\vtimeLimitMs\x12&\n" +
+	"\x0fmemory_limit_mb\x18\x04 \x01(\x05R\rmemoryLimitMb\x12&\n" +
+	"\x0foutput_limit_kb\x18\x05 \x01(\x05R\routputLimitKb\x12\x1e\n" +
+	"\n" +
+	"comparator\x18\x06 \x01(\tR\n" +
+	"comparator\x12\"\n" +
+	"\rfloat_abs_eps\x18\a \x01(\x01R\vfloatAbsEps\x12\"\n" +
+	"\rfloat_rel_eps\x18\b \x01(\x01R\vfloatRelEps\x12\x12\n" +
+	"\x04type\x18\t \x01(\tR\x04type\x12!\n" +
+	"\fscoring_mode\x18\n" +
+	" \x01(\tR\vscoringMode\x12\x1d\n" +
+	"\n" +
+	"go_modules\x18\v \x03(\tR\tgoModules\"#\n" +
+	"\x11GetProblemRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x15\n" +
+	"\x13ListProblemsRequest\"\xc7\x03\n" +
+	"\aProblem\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\tR\tcreatedAt\x12\"\n" +
+	"\rtime_limit_ms\x18\x05 \x01(\x05R\vtimeLimitMs\x12&\n" +
+	"\x0fmemory_limit_mb\x18\x06 \x01(\x05R\rmemoryLimitMb\x12&\n" +
+	"\x0foutput_limit_kb\x18\a \x01(\x05R\routputLimitKb\x12\x1e\n" +
+	"\n" +
+	"comparator\x18\b \x01(\tR\n" +
+	"comparator\x12\"\n" +
+	"\rfloat_abs_eps\x18\t \x01(\x01R\vfloatAbsEps\x12\"\n" +
+	"\rfloat_rel_eps\x18\n" +
+	" \x01(\x01R\vfloatRelEps\x12\x12\n" +
+	"\x04type\x18\v \x01(\tR\x04type\x12!\n" +
+	"\fscoring_mode\x18\f \x01(\tR\vscoringMode\x12#\n" +
+	"\rtests_version\x18\r \x01(\x03R\ftestsVersion\x12\x1d\n" +
+	"\n" +
+	"go_modules\x18\x0e \x03(\tR\tgoModules\"D\n" +
+	"\x14ListProblemsResponse\x12,\n" +
+	"\bproblems\x18\x01 \x03(\v2\x10.problem.ProblemR\bproblems\"\xb4\x02\n" +
 	"\bTestCase\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
 	"\n" +
@@ -507,27 +1341,97 @@ const file_problem_proto_rawDesc = "" +
 	"\n" +
 	"input_data\x18\x03 \x01(\tR\tinputData\x12\x1f\n" +
 	"\voutput_data\x18\x04 \x01(\tR\n" +
-	"outputData\"v\n" +
+	"outputData\x12\x16\n" +
+	"\x06points\x18\x05 \x01(\x05R\x06points\x12\x19\n" +
+	"\bgroup_id\x18\x06 \x01(\tR\agroupId\x12\x1d\n" +
+	"\n" +
+	"input_size\x18\a \x01(\x03R\tinputSize\x12!\n" +
+	"\finput_sha256\x18\b \x01(\tR\vinputSha256\x12\x1f\n" +
+	"\voutput_size\x18\t \x01(\x03R\n" +
+	"outputSize\x12#\n" +
+	"\routput_sha256\x18\n" +
+	" \x01(\tR\foutputSha256\"\xf1\x01\n" +
 	"\x15CreateTestCaseRequest\x12\x1d\n" +
 	"\n" +
 	"problem_id\x18\x01 \x01(\tR\tproblemId\x12\x1d\n" +
 	"\n" +
 	"input_data\x18\x02 \x01(\tR\tinputData\x12\x1f\n" +
 	"\voutput_data\x18\x03 \x01(\tR\n" +
-	"outputData\"4\n" +
+	"outputData\x12\x16\n" +
+	"\x06points\x18\x04 \x01(\x05R\x06points\x12\x19\n" +
+	"\bgroup_id\x18\x05 \x01(\tR\agroupId\x12!\n" +
+	"\finput_sha256\x18\x06 \x01(\tR\vinputSha256\x12#\n" +
+	"\routput_sha256\x18\a \x01(\tR\foutputSha256\"#\n" +
+	"\rTestFileChunk\x12\x12\n" +
+	"\x04data\x18\x01 \x01(\fR\x04data\"6\n" +
+	"\bTestFile\x12\x16\n" +
+	"\x06sha256\x18\x01 \x01(\tR\x06sha256\x12\x12\n" +
+	"\x04size\x18\x02 \x01(\x03R\x04size\"-\n" +
+	"\x13ReadTestFileRequest\x12\x16\n" +
+	"\x06sha256\x18\x01 \x01(\tR\x06sha256\"4\n" +
 	"\x13GetTestCasesRequest\x12\x1d\n" +
 	"\n" +
-	"problem_id\x18\x01 \x01(\tR\tproblemId\"H\n" +
+	"problem_id\x18\x01 \x01(\tR\tproblemId\"t\n" +
 	"\x14GetTestCasesResponse\x120\n" +
 	"\n" +
-	"test_cases\x18\x01 \x03(\v2\x11.problem.TestCaseR\ttestCases2\xed\x02\n" +
+	"test_cases\x18\x01 \x03(\v2\x11.problem.TestCaseR\ttestCases\x12*\n" +
+	"\x06groups\x18\x02 \x03(\v2\x12.problem.TestGroupR\x06groups\"\xa5\x01\n" +
+	"\tTestGroup\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\n" +
+	"problem_id\x18\x02 \x01(\tR\tproblemId\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x14\n" +
+	"\x05score\x18\x04 \x01(\x05R\x05score\x12 \n" +
+	"\vaggregation\x18\x05 \x01(\tR\vaggregation\x12\x1d\n" +
+	"\n" +
+	"depends_on\x18\x06 \x03(\tR\tdependsOn\"\xa2\x01\n" +
+	"\x16CreateTestGroupRequest\x12\x1d\n" +
+	"\n" +
+	"problem_id\x18\x01 \x01(\tR\tproblemId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
+	"\x05score\x18\x03 \x01(\x05R\x05score\x12 \n" +
+	"\vaggregation\x18\x04 \x01(\tR\vaggregation\x12\x1d\n" +
+	"\n" +
+	"depends_on\x18\x05 \x03(\tR\tdependsOn\"6\n" +
+	"\x15ListTestGroupsRequest\x12\x1d\n" +
+	"\n" +
+	"problem_id\x18\x01 \x01(\tR\tproblemId\"D\n" +
+	"\x16ListTestGroupsResponse\x12*\n" +
+	"\x06groups\x18\x01 \x03(\v2\x12.problem.TestGroupR\x06groups\"\x84\x01\n" +
+	"\aProgram\x12\x1d\n" +
+	"\n" +
+	"problem_id\x18\x01 \x01(\tR\tproblemId\x12\x1a\n" +
+	"\blanguage\x18\x02 \x01(\tR\blanguage\x12\x1f\n" +
+	"\vsource_code\x18\x03 \x01(\tR\n" +
+	"sourceCode\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\x04 \x01(\tR\tupdatedAt\"o\n" +
+	"\x11SetProgramRequest\x12\x1d\n" +
+	"\n" +
+	"problem_id\x18\x01 \x01(\tR\tproblemId\x12\x1a\n" +
+	"\blanguage\x18\x02 \x01(\tR\blanguage\x12\x1f\n" +
+	"\vsource_code\x18\x03 \x01(\tR\n" +
+	"sourceCode\"2\n" +
+	"\x11GetProgramRequest\x12\x1d\n" +
+	"\n" +
+	"problem_id\x18\x01 \x01(\tR\tproblemId2\x85\a\n" +
 	"\x0eProblemService\x12@\n" +
 	"\rCreateProblem\x12\x1d.problem.CreateProblemRequest\x1a\x10.problem.Problem\x12:\n" +
 	"\n" +
 	"GetProblem\x12\x1a.problem.GetProblemRequest\x1a\x10.problem.Problem\x12K\n" +
 	"\fListProblems\x12\x1c.problem.ListProblemsRequest\x1a\x1d.problem.ListProblemsResponse\x12C\n" +
 	"\x0eCreateTestCase\x12\x1e.problem.CreateTestCaseRequest\x1a\x11.problem.TestCase\x12K\n" +
-	"\fGetTestCases\x12\x1c.problem.GetTestCasesRequest\x1a\x1d.problem.GetTestCasesResponseBBZ@github.com/DeadlyParkour777/code-checker/pkg/problempb;problempbb\x06proto3"
+	"\fGetTestCases\x12\x1c.problem.GetTestCasesRequest\x1a\x1d.problem.GetTestCasesResponse\x12=\n" +
+	"\x0eUploadTestFile\x12\x16.problem.TestFileChunk\x1a\x11.problem.TestFile(\x01\x12F\n" +
+	"\fReadTestFile\x12\x1c.problem.ReadTestFileRequest\x1a\x16.problem.TestFileChunk0\x01\x12F\n" +
+	"\x0fCreateTestGroup\x12\x1f.problem.CreateTestGroupRequest\x1a\x12.problem.TestGroup\x12Q\n" +
+	"\x0eListTestGroups\x12\x1e.problem.ListTestGroupsRequest\x1a\x1f.problem.ListTestGroupsResponse\x12:\n" +
+	"\n" +
+	"SetChecker\x12\x1a.problem.SetProgramRequest\x1a\x10.problem.Program\x12:\n" +
+	"\n" +
+	"GetChecker\x12\x1a.problem.GetProgramRequest\x1a\x10.problem.Program\x12=\n" +
+	"\rSetInteractor\x12\x1a.problem.SetProgramRequest\x1a\x10.problem.Program\x12=\n" +
+	"\rGetInteractor\x12\x1a.problem.GetProgramRequest\x1a\x10.problem.ProgramBBZ@github.com/DeadlyParkour777/code-checker/pkg/problempb;problempbb\x06proto3"
 
 var (
 	file_problem_proto_rawDescOnce sync.Once
@@ -541,36 +1445,64 @@ func file_problem_proto_rawDescGZIP() []byte {
 	return file_problem_proto_rawDescData
 }
 
-var file_problem_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_problem_proto_msgTypes = make([]protoimpl.MessageInfo, 19)
 var file_problem_proto_goTypes = []any{
-	(*CreateProblemRequest)(nil),  // 0: problem.CreateProblemRequest
-	(*GetProblemRequest)(nil),     // 1: problem.GetProblemRequest
-	(*ListProblemsRequest)(nil),   // 2: problem.ListProblemsRequest
-	(*Problem)(nil),               // 3: problem.Problem
-	(*ListProblemsResponse)(nil),  // 4: problem.ListProblemsResponse
-	(*TestCase)(nil),              // 5: problem.TestCase
-	(*CreateTestCaseRequest)(nil), // 6: problem.CreateTestCaseRequest
-	(*GetTestCasesRequest)(nil),   // 7: problem.GetTestCasesRequest
-	(*GetTestCasesResponse)(nil),  // 8: problem.GetTestCasesResponse
+	(*CreateProblemRequest)(nil),   // 0: problem.CreateProblemRequest
+	(*GetProblemRequest)(nil),      // 1: problem.GetProblemRequest
+	(*ListProblemsRequest)(nil),    // 2: problem.ListProblemsRequest
+	(*Problem)(nil),                // 3: problem.Problem
+	(*ListProblemsResponse)(nil),   // 4: problem.ListProblemsResponse
+	(*TestCase)(nil),               // 5: problem.TestCase
+	(*CreateTestCaseRequest)(nil),  // 6: problem.CreateTestCaseRequest
+	(*TestFileChunk)(nil),          // 7: problem.TestFileChunk
+	(*TestFile)(nil),               // 8: problem.TestFile
+	(*ReadTestFileRequest)(nil),    // 9: problem.ReadTestFileRequest
+	(*GetTestCasesRequest)(nil),    // 10: problem.GetTestCasesRequest
+	(*GetTestCasesResponse)(nil),   // 11: problem.GetTestCasesResponse
+	(*TestGroup)(nil),              // 12: problem.TestGroup
+	(*CreateTestGroupRequest)(nil), // 13: problem.CreateTestGroupRequest
+	(*ListTestGroupsRequest)(nil),  // 14: problem.ListTestGroupsRequest
+	(*ListTestGroupsResponse)(nil), // 15: problem.ListTestGroupsResponse
+	(*Program)(nil),                // 16: problem.Program
+	(*SetProgramRequest)(nil),      // 17: problem.SetProgramRequest
+	(*GetProgramRequest)(nil),      // 18: problem.GetProgramRequest
 }
 var file_problem_proto_depIdxs = []int32{
-	3, // 0: problem.ListProblemsResponse.problems:type_name -> problem.Problem
-	5, // 1: problem.GetTestCasesResponse.test_cases:type_name -> problem.TestCase
-	0, // 2: problem.ProblemService.CreateProblem:input_type -> problem.CreateProblemRequest
-	1, // 3: problem.ProblemService.GetProblem:input_type -> problem.GetProblemRequest
-	2, // 4: problem.ProblemService.ListProblems:input_type -> problem.ListProblemsRequest
-	6, // 5: problem.ProblemService.CreateTestCase:input_type -> problem.CreateTestCaseRequest
-	7, // 6: problem.ProblemService.GetTestCases:input_type -> problem.GetTestCasesRequest
-	3, // 7: problem.ProblemService.CreateProblem:output_type -> problem.Problem
-	3, // 8: problem.ProblemService.GetProblem:output_type -> problem.Problem
-	4, // 9: problem.ProblemService.ListProblems:output_type -> problem.ListProblemsResponse
-	5, // 10: problem.ProblemService.CreateTestCase:output_type -> problem.TestCase
-	8, // 11: problem.ProblemService.GetTestCases:output_type -> problem.GetTestCasesResponse
-	7, // [7:12] is the sub-list for method output_type
-	2, // [2:7] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	3,  // 0: problem.ListProblemsResponse.problems:type_name -> problem.Problem
+	5,  // 1: problem.GetTestCasesResponse.test_cases:type_name -> problem.TestCase
+	12, // 2: problem.GetTestCasesResponse.groups:type_name -> problem.TestGroup
+	12, // 3: problem.ListTestGroupsResponse.groups:type_name -> problem.TestGroup
+	0,  // 4: problem.ProblemService.CreateProblem:input_type -> problem.CreateProblemRequest
+	1,  // 5: problem.ProblemService.GetProblem:input_type -> problem.GetProblemRequest
+	2,  // 6: problem.ProblemService.ListProblems:input_type -> problem.ListProblemsRequest
+	6,  // 7: problem.ProblemService.CreateTestCase:input_type -> problem.CreateTestCaseRequest
+	10, // 8: problem.ProblemService.GetTestCases:input_type -> problem.GetTestCasesRequest
+	7,  // 9: problem.ProblemService.UploadTestFile:input_type -> problem.TestFileChunk
+	9,  // 10: problem.ProblemService.ReadTestFile:input_type -> problem.ReadTestFileRequest
+	13, // 11: problem.ProblemService.CreateTestGroup:input_type -> problem.CreateTestGroupRequest
+	14, // 12: problem.ProblemService.ListTestGroups:input_type -> problem.ListTestGroupsRequest
+	17, // 13: problem.ProblemService.SetChecker:input_type -> problem.SetProgramRequest
+	18, // 14: problem.ProblemService.GetChecker:input_type -> problem.GetProgramRequest
+	17, // 15: problem.ProblemService.SetInteractor:input_type -> problem.SetProgramRequest
+	18, // 16: problem.ProblemService.GetInteractor:input_type -> problem.GetProgramRequest
+	3,  // 17: problem.ProblemService.CreateProblem:output_type -> problem.Problem
+	3,  // 18: problem.ProblemService.GetProblem:output_type -> problem.Problem
+	4,  // 19: problem.ProblemService.ListProblems:output_type -> problem.ListProblemsResponse
+	5,  // 20: problem.ProblemService.CreateTestCase:output_type -> problem.TestCase
+	11, // 21: problem.ProblemService.GetTestCases:output_type -> problem.GetTestCasesResponse
+	8,  // 22: problem.ProblemService.UploadTestFile:output_type -> problem.TestFile
+	7,  // 23: problem.ProblemService.ReadTestFile:output_type -> problem.TestFileChunk
+	12, // 24: problem.ProblemService.CreateTestGroup:output_type -> problem.TestGroup
+	15, // 25: problem.ProblemService.ListTestGroups:output_type -> problem.ListTestGroupsResponse
+	16, // 26: problem.ProblemService.SetChecker:output_type -> problem.Program
+	16, // 27: problem.ProblemService.GetChecker:output_type -> problem.Program
+	16, // 28: problem.ProblemService.SetInteractor:output_type -> problem.Program
+	16, // 29: problem.ProblemService.GetInteractor:output_type -> problem.Program
+	17, // [17:30] is the sub-list for method output_type
+	4,  // [4:17] is the sub-list for method input_type
+	4,  // [4:4] is the sub-list for extension type_name
+	4,  // [4:4] is the sub-list for extension extendee
+	0,  // [0:4] is the sub-list for field type_name
 }
 
 func init() { file_problem_proto_init() }
@@ -584,7 +1516,7 @@ func file_problem_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_problem_proto_rawDesc), len(file_problem_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   9,
+			NumMessages:   19,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
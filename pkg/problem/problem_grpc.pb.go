@@ -19,11 +19,19 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	ProblemService_CreateProblem_FullMethodName  = "/problem.ProblemService/CreateProblem"
-	ProblemService_GetProblem_FullMethodName     = "/problem.ProblemService/GetProblem"
-	ProblemService_ListProblems_FullMethodName   = "/problem.ProblemService/ListProblems"
-	ProblemService_CreateTestCase_FullMethodName = "/problem.ProblemService/CreateTestCase"
-	ProblemService_GetTestCases_FullMethodName   = "/problem.ProblemService/GetTestCases"
+	ProblemService_CreateProblem_FullMethodName   = "/problem.ProblemService/CreateProblem"
+	ProblemService_GetProblem_FullMethodName      = "/problem.ProblemService/GetProblem"
+	ProblemService_ListProblems_FullMethodName    = "/problem.ProblemService/ListProblems"
+	ProblemService_CreateTestCase_FullMethodName  = "/problem.ProblemService/CreateTestCase"
+	ProblemService_GetTestCases_FullMethodName    = "/problem.ProblemService/GetTestCases"
+	ProblemService_UploadTestFile_FullMethodName  = "/problem.ProblemService/UploadTestFile"
+	ProblemService_ReadTestFile_FullMethodName    = "/problem.ProblemService/ReadTestFile"
+	ProblemService_CreateTestGroup_FullMethodName = "/problem.ProblemService/CreateTestGroup"
+	ProblemService_ListTestGroups_FullMethodName  = "/problem.ProblemService/ListTestGroups"
+	ProblemService_SetChecker_FullMethodName      = "/problem.ProblemService/SetChecker"
+	ProblemService_GetChecker_FullMethodName      = "/problem.ProblemService/GetChecker"
+	ProblemService_SetInteractor_FullMethodName   = "/problem.ProblemService/SetInteractor"
+	ProblemService_GetInteractor_FullMethodName   = "/problem.ProblemService/GetInteractor"
 )
 
 // ProblemServiceClient is the client API for ProblemService service.
@@ -35,6 +43,14 @@ type ProblemServiceClient interface {
 	ListProblems(ctx context.Context, in *ListProblemsRequest, opts ...grpc.CallOption) (*ListProblemsResponse, error)
 	CreateTestCase(ctx context.Context, in *CreateTestCaseRequest, opts ...grpc.CallOption) (*TestCase, error)
 	GetTestCases(ctx context.Context, in *GetTestCasesRequest, opts ...grpc.CallOption) (*GetTestCasesResponse, error)
+	UploadTestFile(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[TestFileChunk, TestFile], error)
+	ReadTestFile(ctx context.Context, in *ReadTestFileRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[TestFileChunk], error)
+	CreateTestGroup(ctx context.Context, in *CreateTestGroupRequest, opts ...grpc.CallOption) (*TestGroup, error)
+	ListTestGroups(ctx context.Context, in *ListTestGroupsRequest, opts ...grpc.CallOption) (*ListTestGroupsResponse, error)
+	SetChecker(ctx context.Context, in *SetProgramRequest, opts ...grpc.CallOption) (*Program, error)
+	GetChecker(ctx context.Context, in *GetProgramRequest, opts ...grpc.CallOption) (*Program, error)
+	SetInteractor(ctx context.Context, in *SetProgramRequest, opts ...grpc.CallOption) (*Program, error)
+	GetInteractor(ctx context.Context, in *GetProgramRequest, opts ...grpc.CallOption) (*Program, error)
 }
 
 type problemServiceClient struct {
@@ -95,6 +111,98 @@ func (c *problemServiceClient) GetTestCases(ctx context.Context, in *GetTestCase
 	return out, nil
 }
 
+func (c *problemServiceClient) UploadTestFile(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[TestFileChunk, TestFile], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ProblemService_ServiceDesc.Streams[0], ProblemService_UploadTestFile_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[TestFileChunk, TestFile]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ProblemService_UploadTestFileClient = grpc.ClientStreamingClient[TestFileChunk, TestFile]
+
+func (c *problemServiceClient) ReadTestFile(ctx context.Context, in *ReadTestFileRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[TestFileChunk], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ProblemService_ServiceDesc.Streams[1], ProblemService_ReadTestFile_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ReadTestFileRequest, TestFileChunk]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ProblemService_ReadTestFileClient = grpc.ServerStreamingClient[TestFileChunk]
+
+func (c *problemServiceClient) CreateTestGroup(ctx context.Context, in *CreateTestGroupRequest, opts ...grpc.CallOption) (*TestGroup, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TestGroup)
+	err := c.cc.Invoke(ctx, ProblemService_CreateTestGroup_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *problemServiceClient) ListTestGroups(ctx context.Context, in *ListTestGroupsRequest, opts ...grpc.CallOption) (*ListTestGroupsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListTestGroupsResponse)
+	err := c.cc.Invoke(ctx, ProblemService_ListTestGroups_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *problemServiceClient) SetChecker(ctx context.Context, in *SetProgramRequest, opts ...grpc.CallOption) (*Program, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Program)
+	err := c.cc.Invoke(ctx, ProblemService_SetChecker_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *problemServiceClient) GetChecker(ctx context.Context, in *GetProgramRequest, opts ...grpc.CallOption) (*Program, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Program)
+	err := c.cc.Invoke(ctx, ProblemService_GetChecker_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *problemServiceClient) SetInteractor(ctx context.Context, in *SetProgramRequest, opts ...grpc.CallOption) (*Program, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Program)
+	err := c.cc.Invoke(ctx, ProblemService_SetInteractor_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *problemServiceClient) GetInteractor(ctx context.Context, in *GetProgramRequest, opts ...grpc.CallOption) (*Program, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Program)
+	err := c.cc.Invoke(ctx, ProblemService_GetInteractor_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ProblemServiceServer is the server API for ProblemService service.
 // All implementations must embed UnimplementedProblemServiceServer
 // for forward compatibility.
@@ -104,6 +212,14 @@ type ProblemServiceServer interface {
 	ListProblems(context.Context, *ListProblemsRequest) (*ListProblemsResponse, error)
 	CreateTestCase(context.Context, *CreateTestCaseRequest) (*TestCase, error)
 	GetTestCases(context.Context, *GetTestCasesRequest) (*GetTestCasesResponse, error)
+	UploadTestFile(grpc.ClientStreamingServer[TestFileChunk, TestFile]) error
+	ReadTestFile(*ReadTestFileRequest, grpc.ServerStreamingServer[TestFileChunk]) error
+	CreateTestGroup(context.Context, *CreateTestGroupRequest) (*TestGroup, error)
+	ListTestGroups(context.Context, *ListTestGroupsRequest) (*ListTestGroupsResponse, error)
+	SetChecker(context.Context, *SetProgramRequest) (*Program, error)
+	GetChecker(context.Context, *GetProgramRequest) (*Program, error)
+	SetInteractor(context.Context, *SetProgramRequest) (*Program, error)
+	GetInteractor(context.Context, *GetProgramRequest) (*Program, error)
 	mustEmbedUnimplementedProblemServiceServer()
 }
 
@@ -129,6 +245,30 @@ func (UnimplementedProblemServiceServer) CreateTestCase(context.Context, *Create
 func (UnimplementedProblemServiceServer) GetTestCases(context.Context, *GetTestCasesRequest) (*GetTestCasesResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetTestCases not implemented")
 }
+func (UnimplementedProblemServiceServer) UploadTestFile(grpc.ClientStreamingServer[TestFileChunk, TestFile]) error {
+	return status.Errorf(codes.Unimplemented, "method UploadTestFile not implemented")
+}
+func (UnimplementedProblemServiceServer) ReadTestFile(*ReadTestFileRequest, grpc.ServerStreamingServer[TestFileChunk]) error {
+	return status.Errorf(codes.Unimplemented, "method ReadTestFile not implemented")
+}
+func (UnimplementedProblemServiceServer) CreateTestGroup(context.Context, *CreateTestGroupRequest) (*TestGroup, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateTestGroup not implemented")
+}
+func (UnimplementedProblemServiceServer) ListTestGroups(context.Context, *ListTestGroupsRequest) (*ListTestGroupsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTestGroups not implemented")
+}
+func (UnimplementedProblemServiceServer) SetChecker(context.Context, *SetProgramRequest) (*Program, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetChecker not implemented")
+}
+func (UnimplementedProblemServiceServer) GetChecker(context.Context, *GetProgramRequest) (*Program, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetChecker not implemented")
+}
+func (UnimplementedProblemServiceServer) SetInteractor(context.Context, *SetProgramRequest) (*Program, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetInteractor not implemented")
+}
+func (UnimplementedProblemServiceServer) GetInteractor(context.Context, *GetProgramRequest) (*Program, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetInteractor not implemented")
+}
 func (UnimplementedProblemServiceServer) mustEmbedUnimplementedProblemServiceServer() {}
 func (UnimplementedProblemServiceServer) testEmbeddedByValue()                        {}
 
@@ -240,6 +380,132 @@ func _ProblemService_GetTestCases_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ProblemService_UploadTestFile_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(ProblemServiceServer).UploadTestFile(&grpc.GenericServerStream[TestFileChunk, TestFile]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ProblemService_UploadTestFileServer = grpc.ClientStreamingServer[TestFileChunk, TestFile]
+
+func _ProblemService_ReadTestFile_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ReadTestFileRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ProblemServiceServer).ReadTestFile(m, &grpc.GenericServerStream[ReadTestFileRequest, TestFileChunk]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ProblemService_ReadTestFileServer = grpc.ServerStreamingServer[TestFileChunk]
+
+func _ProblemService_CreateTestGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateTestGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProblemServiceServer).CreateTestGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProblemService_CreateTestGroup_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProblemServiceServer).CreateTestGroup(ctx, req.(*CreateTestGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProblemService_ListTestGroups_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTestGroupsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProblemServiceServer).ListTestGroups(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProblemService_ListTestGroups_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProblemServiceServer).ListTestGroups(ctx, req.(*ListTestGroupsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProblemService_SetChecker_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetProgramRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProblemServiceServer).SetChecker(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProblemService_SetChecker_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProblemServiceServer).SetChecker(ctx, req.(*SetProgramRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProblemService_GetChecker_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetProgramRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProblemServiceServer).GetChecker(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProblemService_GetChecker_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProblemServiceServer).GetChecker(ctx, req.(*GetProgramRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProblemService_SetInteractor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetProgramRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProblemServiceServer).SetInteractor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProblemService_SetInteractor_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProblemServiceServer).SetInteractor(ctx, req.(*SetProgramRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProblemService_GetInteractor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetProgramRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProblemServiceServer).GetInteractor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProblemService_GetInteractor_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProblemServiceServer).GetInteractor(ctx, req.(*GetProgramRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // ProblemService_ServiceDesc is the grpc.ServiceDesc for ProblemService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -267,7 +533,42 @@ var ProblemService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetTestCases",
 			Handler:    _ProblemService_GetTestCases_Handler,
 		},
+		{
+			MethodName: "CreateTestGroup",
+			Handler:    _ProblemService_CreateTestGroup_Handler,
+		},
+		{
+			MethodName: "ListTestGroups",
+			Handler:    _ProblemService_ListTestGroups_Handler,
+		},
+		{
+			MethodName: "SetChecker",
+			Handler:    _ProblemService_SetChecker_Handler,
+		},
+		{
+			MethodName: "GetChecker",
+			Handler:    _ProblemService_GetChecker_Handler,
+		},
+		{
+			MethodName: "SetInteractor",
+			Handler:    _ProblemService_SetInteractor_Handler,
+		},
+		{
+			MethodName: "GetInteractor",
+			Handler:    _ProblemService_GetInteractor_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "UploadTestFile",
+			Handler:       _ProblemService_UploadTestFile_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "ReadTestFile",
+			Handler:       _ProblemService_ReadTestFile_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "problem.proto",
 }
@@ -22,14 +22,23 @@ const (
 )
 
 type Submission struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	ProblemId     string                 `protobuf:"bytes,2,opt,name=problem_id,json=problemId,proto3" json:"problem_id,omitempty"`
-	UserId        string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	Language      string                 `protobuf:"bytes,4,opt,name=language,proto3" json:"language,omitempty"`
-	Status        string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
-	CreatedAt     string                 `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	UpdatedAt     string                 `protobuf:"bytes,8,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ProblemId   string                 `protobuf:"bytes,2,opt,name=problem_id,json=problemId,proto3" json:"problem_id,omitempty"`
+	UserId      string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Language    string                 `protobuf:"bytes,4,opt,name=language,proto3" json:"language,omitempty"`
+	Status      string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
+	CreatedAt   string                 `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt   string                 `protobuf:"bytes,8,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Tests       []*TestResult          `protobuf:"bytes,9,rep,name=tests,proto3" json:"tests,omitempty"`
+	MaxMemoryKb int64                  `protobuf:"varint,10,opt,name=max_memory_kb,json=maxMemoryKb,proto3" json:"max_memory_kb,omitempty"`
+	Score       float64                `protobuf:"fixed64,11,opt,name=score,proto3" json:"score,omitempty"`
+	MaxScore    float64                `protobuf:"fixed64,12,opt,name=max_score,json=maxScore,proto3" json:"max_score,omitempty"`
+	// Progress while status is "Judging": stage is "Compiling" or "Running",
+	// current_test the 1-based number of the running test.
+	Stage         string `protobuf:"bytes,13,opt,name=stage,proto3" json:"stage,omitempty"`
+	CurrentTest   int32  `protobuf:"varint,14,opt,name=current_test,json=currentTest,proto3" json:"current_test,omitempty"`
+	TotalTests    int32  `protobuf:"varint,15,opt,name=total_tests,json=totalTests,proto3" json:"total_tests,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -113,6 +122,139 @@ func (x *Submission) GetUpdatedAt() string {
 	return ""
 }
 
+func (x *Submission) GetTests() []*TestResult {
+	if x != nil {
+		return x.Tests
+	}
+	return nil
+}
+
+func (x *Submission) GetMaxMemoryKb() int64 {
+	if x != nil {
+		return x.MaxMemoryKb
+	}
+	return 0
+}
+
+func (x *Submission) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *Submission) GetMaxScore() float64 {
+	if x != nil {
+		return x.MaxScore
+	}
+	return 0
+}
+
+func (x *Submission) GetStage() string {
+	if x != nil {
+		return x.Stage
+	}
+	return ""
+}
+
+func (x *Submission) GetCurrentTest() int32 {
+	if x != nil {
+		return x.CurrentTest
+	}
+	return 0
+}
+
+func (x *Submission) GetTotalTests() int32 {
+	if x != nil {
+		return x.TotalTests
+	}
+	return 0
+}
+
+type TestResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Index         int32                  `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	TimeMs        int64                  `protobuf:"varint,3,opt,name=time_ms,json=timeMs,proto3" json:"time_ms,omitempty"`
+	ExitCode      int32                  `protobuf:"varint,4,opt,name=exit_code,json=exitCode,proto3" json:"exit_code,omitempty"`
+	Stderr        string                 `protobuf:"bytes,5,opt,name=stderr,proto3" json:"stderr,omitempty"`
+	MemoryKb      int64                  `protobuf:"varint,6,opt,name=memory_kb,json=memoryKb,proto3" json:"memory_kb,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TestResult) Reset() {
+	*x = TestResult{}
+	mi := &file_result_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TestResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TestResult) ProtoMessage() {}
+
+func (x *TestResult) ProtoReflect() protoreflect.Message {
+	mi := &file_result_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TestResult.ProtoReflect.Descriptor instead.
+func (*TestResult) Descriptor() ([]byte, []int) {
+	return file_result_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *TestResult) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *TestResult) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *TestResult) GetTimeMs() int64 {
+	if x != nil {
+		return x.TimeMs
+	}
+	return 0
+}
+
+func (x *TestResult) GetExitCode() int32 {
+	if x != nil {
+		return x.ExitCode
+	}
+	return 0
+}
+
+func (x *TestResult) GetStderr() string {
+	if x != nil {
+		return x.Stderr
+	}
+	return ""
+}
+
+func (x *TestResult) GetMemoryKb() int64 {
+	if x != nil {
+		return x.MemoryKb
+	}
+	return 0
+}
+
 type GetUserSubmissionsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
@@ -122,7 +264,7 @@ type GetUserSubmissionsRequest struct {
 
 func (x *GetUserSubmissionsRequest) Reset() {
 	*x = GetUserSubmissionsRequest{}
-	mi := &file_result_proto_msgTypes[1]
+	mi := &file_result_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -134,7 +276,7 @@ func (x *GetUserSubmissionsRequest) String() string {
 func (*GetUserSubmissionsRequest) ProtoMessage() {}
 
 func (x *GetUserSubmissionsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_result_proto_msgTypes[1]
+	mi := &file_result_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -147,7 +289,7 @@ func (x *GetUserSubmissionsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetUserSubmissionsRequest.ProtoReflect.Descriptor instead.
 func (*GetUserSubmissionsRequest) Descriptor() ([]byte, []int) {
-	return file_result_proto_rawDescGZIP(), []int{1}
+	return file_result_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *GetUserSubmissionsRequest) GetUserId() string {
@@ -166,7 +308,7 @@ type GetUserSubmissionsResponse struct {
 
 func (x *GetUserSubmissionsResponse) Reset() {
 	*x = GetUserSubmissionsResponse{}
-	mi := &file_result_proto_msgTypes[2]
+	mi := &file_result_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -178,7 +320,7 @@ func (x *GetUserSubmissionsResponse) String() string {
 func (*GetUserSubmissionsResponse) ProtoMessage() {}
 
 func (x *GetUserSubmissionsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_result_proto_msgTypes[2]
+	mi := &file_result_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -191,7 +333,7 @@ func (x *GetUserSubmissionsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetUserSubmissionsResponse.ProtoReflect.Descriptor instead.
 func (*GetUserSubmissionsResponse) Descriptor() ([]byte, []int) {
-	return file_result_proto_rawDescGZIP(), []int{2}
+	return file_result_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *GetUserSubmissionsResponse) GetSubmissions() []*Submission {
@@ -201,11 +343,55 @@ func (x *GetUserSubmissionsResponse) GetSubmissions() []*Submission {
 	return nil
 }
 
+type GetSubmissionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSubmissionRequest) Reset() {
+	*x = GetSubmissionRequest{}
+	mi := &file_result_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSubmissionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSubmissionRequest) ProtoMessage() {}
+
+func (x *GetSubmissionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_result_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSubmissionRequest.ProtoReflect.Descriptor instead.
+func (*GetSubmissionRequest) Descriptor() ([]byte, []int) {
+	return file_result_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetSubmissionRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
 var File_result_proto protoreflect.FileDescriptor
 
 const file_result_proto_rawDesc = "" +
 	"\n" +
-	"\fresult.proto\x12\x06result\"\xc6\x01\n" +
+	"\fresult.proto\x12\x06result\"\xa1\x03\n" +
 	"\n" +
 	"Submission\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
@@ -217,13 +403,33 @@ const file_result_proto_rawDesc = "" +
 	"\n" +
 	"created_at\x18\a \x01(\tR\tcreatedAt\x12\x1d\n" +
 	"\n" +
-	"updated_at\x18\b \x01(\tR\tupdatedAt\"4\n" +
+	"updated_at\x18\b \x01(\tR\tupdatedAt\x12(\n" +
+	"\x05tests\x18\t \x03(\v2\x12.result.TestResultR\x05tests\x12\"\n" +
+	"\rmax_memory_kb\x18\n" +
+	" \x01(\x03R\vmaxMemoryKb\x12\x14\n" +
+	"\x05score\x18\v \x01(\x01R\x05score\x12\x1b\n" +
+	"\tmax_score\x18\f \x01(\x01R\bmaxScore\x12\x14\n" +
+	"\x05stage\x18\r \x01(\tR\x05stage\x12!\n" +
+	"\fcurrent_test\x18\x0e \x01(\x05R\vcurrentTest\x12\x1f\n" +
+	"\vtotal_tests\x18\x0f \x01(\x05R\n" +
+	"totalTests\"\xa5\x01\n" +
+	"\n" +
+	"TestResult\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\x05R\x05index\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12\x17\n" +
+	"\atime_ms\x18\x03 \x01(\x03R\x06timeMs\x12\x1b\n" +
+	"\texit_code\x18\x04 \x01(\x05R\bexitCode\x12\x16\n" +
+	"\x06stderr\x18\x05 \x01(\tR\x06stderr\x12\x1b\n" +
+	"\tmemory_kb\x18\x06 \x01(\x03R\bmemoryKb\"4\n" +
 	"\x19GetUserSubmissionsRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\"R\n" +
 	"\x1aGetUserSubmissionsResponse\x124\n" +
-	"\vsubmissions\x18\x01 \x03(\v2\x12.result.SubmissionR\vsubmissions2l\n" +
+	"\vsubmissions\x18\x01 \x03(\v2\x12.result.SubmissionR\vsubmissions\"&\n" +
+	"\x14GetSubmissionRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id2\xaf\x01\n" +
 	"\rResultService\x12[\n" +
-	"\x12GetUserSubmissions\x12!.result.GetUserSubmissionsRequest\x1a\".result.GetUserSubmissionsResponseB@Z>github.com/DeadlyParkour777/code-checker/pkg/resultpb;resultpbb\x06proto3"
+	"\x12GetUserSubmissions\x12!.result.GetUserSubmissionsRequest\x1a\".result.GetUserSubmissionsResponse\x12A\n" +
+	"\rGetSubmission\x12\x1c.result.GetSubmissionRequest\x1a\x12.result.SubmissionB@Z>github.com/DeadlyParkour777/code-checker/pkg/resultpb;resultpbb\x06proto3"
 
 var (
 	file_result_proto_rawDescOnce sync.Once
@@ -237,21 +443,26 @@ func file_result_proto_rawDescGZIP() []byte {
 	return file_result_proto_rawDescData
 }
 
-var file_result_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_result_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
 var file_result_proto_goTypes = []any{
 	(*Submission)(nil),                 // 0: result.Submission
-	(*GetUserSubmissionsRequest)(nil),  // 1: result.GetUserSubmissionsRequest
-	(*GetUserSubmissionsResponse)(nil), // 2: result.GetUserSubmissionsResponse
+	(*TestResult)(nil),                 // 1: result.TestResult
+	(*GetUserSubmissionsRequest)(nil),  // 2: result.GetUserSubmissionsRequest
+	(*GetUserSubmissionsResponse)(nil), // 3: result.GetUserSubmissionsResponse
+	(*GetSubmissionRequest)(nil),       // 4: result.GetSubmissionRequest
 }
 var file_result_proto_depIdxs = []int32{
-	0, // 0: result.GetUserSubmissionsResponse.submissions:type_name -> result.Submission
-	1, // 1: result.ResultService.GetUserSubmissions:input_type -> result.GetUserSubmissionsRequest
-	2, // 2: result.ResultService.GetUserSubmissions:output_type -> result.GetUserSubmissionsResponse
-	2, // [2:3] is the sub-list for method output_type
-	1, // [1:2] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	1, // 0: result.Submission.tests:type_name -> result.TestResult
+	0, // 1: result.GetUserSubmissionsResponse.submissions:type_name -> result.Submission
+	2, // 2: result.ResultService.GetUserSubmissions:input_type -> result.GetUserSubmissionsRequest
+	4, // 3: result.ResultService.GetSubmission:input_type -> result.GetSubmissionRequest
+	3, // 4: result.ResultService.GetUserSubmissions:output_type -> result.GetUserSubmissionsResponse
+	0, // 5: result.ResultService.GetSubmission:output_type -> result.Submission
+	4, // [4:6] is the sub-list for method output_type
+	2, // [2:4] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
 }
 
 func init() { file_result_proto_init() }
@@ -265,7 +476,7 @@ func file_result_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_result_proto_rawDesc), len(file_result_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   3,
+			NumMessages:   5,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
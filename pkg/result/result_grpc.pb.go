@@ -20,6 +20,7 @@ const _ = grpc.SupportPackageIsVersion9
 
 const (
 	ResultService_GetUserSubmissions_FullMethodName = "/result.ResultService/GetUserSubmissions"
+	ResultService_GetSubmission_FullMethodName      = "/result.ResultService/GetSubmission"
 )
 
 // ResultServiceClient is the client API for ResultService service.
@@ -27,6 +28,7 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type ResultServiceClient interface {
 	GetUserSubmissions(ctx context.Context, in *GetUserSubmissionsRequest, opts ...grpc.CallOption) (*GetUserSubmissionsResponse, error)
+	GetSubmission(ctx context.Context, in *GetSubmissionRequest, opts ...grpc.CallOption) (*Submission, error)
 }
 
 type resultServiceClient struct {
@@ -47,11 +49,22 @@ func (c *resultServiceClient) GetUserSubmissions(ctx context.Context, in *GetUse
 	return out, nil
 }
 
+func (c *resultServiceClient) GetSubmission(ctx context.Context, in *GetSubmissionRequest, opts ...grpc.CallOption) (*Submission, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Submission)
+	err := c.cc.Invoke(ctx, ResultService_GetSubmission_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ResultServiceServer is the server API for ResultService service.
 // All implementations must embed UnimplementedResultServiceServer
 // for forward compatibility.
 type ResultServiceServer interface {
 	GetUserSubmissions(context.Context, *GetUserSubmissionsRequest) (*GetUserSubmissionsResponse, error)
+	GetSubmission(context.Context, *GetSubmissionRequest) (*Submission, error)
 	mustEmbedUnimplementedResultServiceServer()
 }
 
@@ -65,6 +78,9 @@ type UnimplementedResultServiceServer struct{}
 func (UnimplementedResultServiceServer) GetUserSubmissions(context.Context, *GetUserSubmissionsRequest) (*GetUserSubmissionsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetUserSubmissions not implemented")
 }
+func (UnimplementedResultServiceServer) GetSubmission(context.Context, *GetSubmissionRequest) (*Submission, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSubmission not implemented")
+}
 func (UnimplementedResultServiceServer) mustEmbedUnimplementedResultServiceServer() {}
 func (UnimplementedResultServiceServer) testEmbeddedByValue()                       {}
 
@@ -104,6 +120,24 @@ func _ResultService_GetUserSubmissions_Handler(srv interface{}, ctx context.Cont
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ResultService_GetSubmission_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSubmissionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ResultServiceServer).GetSubmission(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ResultService_GetSubmission_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ResultServiceServer).GetSubmission(ctx, req.(*GetSubmissionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // ResultService_ServiceDesc is the grpc.ServiceDesc for ResultService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -115,6 +149,10 @@ var ResultService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetUserSubmissions",
 			Handler:    _ResultService_GetUserSubmissions_Handler,
 		},
+		{
+			MethodName: "GetSubmission",
+			Handler:    _ResultService_GetSubmission_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "result.proto",
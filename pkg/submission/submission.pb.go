@@ -21,12 +21,17 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// The first message is the info. A single-file submission follows it with
+// chunk_data only; a multi-file one sends file_path before the chunks of
+// each file; an archive (zip, tar or tar.gz) is sent as chunk_data with
+// info.archive set.
 type CreateSubmissionRequest struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// Types that are valid to be assigned to Data:
 	//
 	//	*CreateSubmissionRequest_Info
 	//	*CreateSubmissionRequest_ChunkData
+	//	*CreateSubmissionRequest_FilePath
 	Data          isCreateSubmissionRequest_Data `protobuf_oneof:"data"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
@@ -87,6 +92,15 @@ func (x *CreateSubmissionRequest) GetChunkData() []byte {
 	return nil
 }
 
+func (x *CreateSubmissionRequest) GetFilePath() string {
+	if x != nil {
+		if x, ok := x.Data.(*CreateSubmissionRequest_FilePath); ok {
+			return x.FilePath
+		}
+	}
+	return ""
+}
+
 type isCreateSubmissionRequest_Data interface {
 	isCreateSubmissionRequest_Data()
 }
@@ -99,15 +113,22 @@ type CreateSubmissionRequest_ChunkData struct {
 	ChunkData []byte `protobuf:"bytes,2,opt,name=chunk_data,json=chunkData,proto3,oneof"`
 }
 
+type CreateSubmissionRequest_FilePath struct {
+	FilePath string `protobuf:"bytes,3,opt,name=file_path,json=filePath,proto3,oneof"`
+}
+
 func (*CreateSubmissionRequest_Info) isCreateSubmissionRequest_Data() {}
 
 func (*CreateSubmissionRequest_ChunkData) isCreateSubmissionRequest_Data() {}
 
+func (*CreateSubmissionRequest_FilePath) isCreateSubmissionRequest_Data() {}
+
 type SubmissionInfo struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
 	ProblemId     string                 `protobuf:"bytes,2,opt,name=problem_id,json=problemId,proto3" json:"problem_id,omitempty"`
 	Language      string                 `protobuf:"bytes,3,opt,name=language,proto3" json:"language,omitempty"`
+	Archive       bool                   `protobuf:"varint,4,opt,name=archive,proto3" json:"archive,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -163,6 +184,65 @@ func (x *SubmissionInfo) GetLanguage() string {
 	return ""
 }
 
+func (x *SubmissionInfo) GetArchive() bool {
+	if x != nil {
+		return x.Archive
+	}
+	return false
+}
+
+type SourceFile struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Content       string                 `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SourceFile) Reset() {
+	*x = SourceFile{}
+	mi := &file_submission_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SourceFile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SourceFile) ProtoMessage() {}
+
+func (x *SourceFile) ProtoReflect() protoreflect.Message {
+	mi := &file_submission_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SourceFile.ProtoReflect.Descriptor instead.
+func (*SourceFile) Descriptor() ([]byte, []int) {
+	return file_submission_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SourceFile) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *SourceFile) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
 type Submission struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -170,16 +250,17 @@ type Submission struct {
 	UserId        string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
 	Code          string                 `protobuf:"bytes,4,opt,name=code,proto3" json:"code,omitempty"`
 	Language      string                 `protobuf:"bytes,5,opt,name=language,proto3" json:"language,omitempty"`
-	Status        string                 `protobuf:"bytes,6,opt,name=status,proto3" json:"status,omitempty"` // "Pending", "Processing", "AC", "WA", "TLE", "CE", "RE"
+	Status        string                 `protobuf:"bytes,6,opt,name=status,proto3" json:"status,omitempty"` // "Pending", "AC", "WA", "TLE", "MLE", "OLE", "CE", "RE"
 	CreatedAt     string                 `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 	UpdatedAt     string                 `protobuf:"bytes,8,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Files         []*SourceFile          `protobuf:"bytes,9,rep,name=files,proto3" json:"files,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Submission) Reset() {
 	*x = Submission{}
-	mi := &file_submission_proto_msgTypes[2]
+	mi := &file_submission_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -191,7 +272,7 @@ func (x *Submission) String() string {
 func (*Submission) ProtoMessage() {}
 
 func (x *Submission) ProtoReflect() protoreflect.Message {
-	mi := &file_submission_proto_msgTypes[2]
+	mi := &file_submission_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -204,7 +285,7 @@ func (x *Submission) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Submission.ProtoReflect.Descriptor instead.
 func (*Submission) Descriptor() ([]byte, []int) {
-	return file_submission_proto_rawDescGZIP(), []int{2}
+	return file_submission_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *Submission) GetId() string {
@@ -263,22 +344,341 @@ func (x *Submission) GetUpdatedAt() string {
 	return ""
 }
 
+func (x *Submission) GetFiles() []*SourceFile {
+	if x != nil {
+		return x.Files
+	}
+	return nil
+}
+
+// Selects the finished submissions to judge again; at least one filter is
+// required. Times are RFC 3339.
+type RejudgeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RequestedBy   string                 `protobuf:"bytes,1,opt,name=requested_by,json=requestedBy,proto3" json:"requested_by,omitempty"`
+	SubmissionId  string                 `protobuf:"bytes,2,opt,name=submission_id,json=submissionId,proto3" json:"submission_id,omitempty"`
+	ProblemId     string                 `protobuf:"bytes,3,opt,name=problem_id,json=problemId,proto3" json:"problem_id,omitempty"`
+	Statuses      []string               `protobuf:"bytes,4,rep,name=statuses,proto3" json:"statuses,omitempty"`
+	CreatedAfter  string                 `protobuf:"bytes,5,opt,name=created_after,json=createdAfter,proto3" json:"created_after,omitempty"`
+	CreatedBefore string                 `protobuf:"bytes,6,opt,name=created_before,json=createdBefore,proto3" json:"created_before,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RejudgeRequest) Reset() {
+	*x = RejudgeRequest{}
+	mi := &file_submission_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RejudgeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RejudgeRequest) ProtoMessage() {}
+
+func (x *RejudgeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_submission_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RejudgeRequest.ProtoReflect.Descriptor instead.
+func (*RejudgeRequest) Descriptor() ([]byte, []int) {
+	return file_submission_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *RejudgeRequest) GetRequestedBy() string {
+	if x != nil {
+		return x.RequestedBy
+	}
+	return ""
+}
+
+func (x *RejudgeRequest) GetSubmissionId() string {
+	if x != nil {
+		return x.SubmissionId
+	}
+	return ""
+}
+
+func (x *RejudgeRequest) GetProblemId() string {
+	if x != nil {
+		return x.ProblemId
+	}
+	return ""
+}
+
+func (x *RejudgeRequest) GetStatuses() []string {
+	if x != nil {
+		return x.Statuses
+	}
+	return nil
+}
+
+func (x *RejudgeRequest) GetCreatedAfter() string {
+	if x != nil {
+		return x.CreatedAfter
+	}
+	return ""
+}
+
+func (x *RejudgeRequest) GetCreatedBefore() string {
+	if x != nil {
+		return x.CreatedBefore
+	}
+	return ""
+}
+
+type GetRejudgeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRejudgeRequest) Reset() {
+	*x = GetRejudgeRequest{}
+	mi := &file_submission_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRejudgeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRejudgeRequest) ProtoMessage() {}
+
+func (x *GetRejudgeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_submission_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRejudgeRequest.ProtoReflect.Descriptor instead.
+func (*GetRejudgeRequest) Descriptor() ([]byte, []int) {
+	return file_submission_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetRejudgeRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type RejudgeEntry struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	SubmissionId   string                 `protobuf:"bytes,1,opt,name=submission_id,json=submissionId,proto3" json:"submission_id,omitempty"`
+	PreviousStatus string                 `protobuf:"bytes,2,opt,name=previous_status,json=previousStatus,proto3" json:"previous_status,omitempty"`
+	PreviousScore  float64                `protobuf:"fixed64,3,opt,name=previous_score,json=previousScore,proto3" json:"previous_score,omitempty"`
+	Status         string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	Score          float64                `protobuf:"fixed64,5,opt,name=score,proto3" json:"score,omitempty"`
+	Changed        bool                   `protobuf:"varint,6,opt,name=changed,proto3" json:"changed,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *RejudgeEntry) Reset() {
+	*x = RejudgeEntry{}
+	mi := &file_submission_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RejudgeEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RejudgeEntry) ProtoMessage() {}
+
+func (x *RejudgeEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_submission_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RejudgeEntry.ProtoReflect.Descriptor instead.
+func (*RejudgeEntry) Descriptor() ([]byte, []int) {
+	return file_submission_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *RejudgeEntry) GetSubmissionId() string {
+	if x != nil {
+		return x.SubmissionId
+	}
+	return ""
+}
+
+func (x *RejudgeEntry) GetPreviousStatus() string {
+	if x != nil {
+		return x.PreviousStatus
+	}
+	return ""
+}
+
+func (x *RejudgeEntry) GetPreviousScore() float64 {
+	if x != nil {
+		return x.PreviousScore
+	}
+	return 0
+}
+
+func (x *RejudgeEntry) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *RejudgeEntry) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *RejudgeEntry) GetChanged() bool {
+	if x != nil {
+		return x.Changed
+	}
+	return false
+}
+
+type RejudgeReport struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	RequestedBy   string                 `protobuf:"bytes,2,opt,name=requested_by,json=requestedBy,proto3" json:"requested_by,omitempty"`
+	CreatedAt     string                 `protobuf:"bytes,3,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	Total         int32                  `protobuf:"varint,4,opt,name=total,proto3" json:"total,omitempty"`
+	Pending       int32                  `protobuf:"varint,5,opt,name=pending,proto3" json:"pending,omitempty"`
+	Changed       int32                  `protobuf:"varint,6,opt,name=changed,proto3" json:"changed,omitempty"`
+	Submissions   []*RejudgeEntry        `protobuf:"bytes,7,rep,name=submissions,proto3" json:"submissions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RejudgeReport) Reset() {
+	*x = RejudgeReport{}
+	mi := &file_submission_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RejudgeReport) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RejudgeReport) ProtoMessage() {}
+
+func (x *RejudgeReport) ProtoReflect() protoreflect.Message {
+	mi := &file_submission_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RejudgeReport.ProtoReflect.Descriptor instead.
+func (*RejudgeReport) Descriptor() ([]byte, []int) {
+	return file_submission_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *RejudgeReport) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *RejudgeReport) GetRequestedBy() string {
+	if x != nil {
+		return x.RequestedBy
+	}
+	return ""
+}
+
+func (x *RejudgeReport) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *RejudgeReport) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *RejudgeReport) GetPending() int32 {
+	if x != nil {
+		return x.Pending
+	}
+	return 0
+}
+
+func (x *RejudgeReport) GetChanged() int32 {
+	if x != nil {
+		return x.Changed
+	}
+	return 0
+}
+
+func (x *RejudgeReport) GetSubmissions() []*RejudgeEntry {
+	if x != nil {
+		return x.Submissions
+	}
+	return nil
+}
+
 var File_submission_proto protoreflect.FileDescriptor
 
 const file_submission_proto_rawDesc = "" +
 	"\n" +
 	"\x10submission.proto\x12\n" +
-	"submission\"t\n" +
+	"submission\"\x93\x01\n" +
 	"\x17CreateSubmissionRequest\x120\n" +
 	"\x04info\x18\x01 \x01(\v2\x1a.submission.SubmissionInfoH\x00R\x04info\x12\x1f\n" +
 	"\n" +
-	"chunk_data\x18\x02 \x01(\fH\x00R\tchunkDataB\x06\n" +
-	"\x04data\"d\n" +
+	"chunk_data\x18\x02 \x01(\fH\x00R\tchunkData\x12\x1d\n" +
+	"\tfile_path\x18\x03 \x01(\tH\x00R\bfilePathB\x06\n" +
+	"\x04data\"~\n" +
 	"\x0eSubmissionInfo\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1d\n" +
 	"\n" +
 	"problem_id\x18\x02 \x01(\tR\tproblemId\x12\x1a\n" +
-	"\blanguage\x18\x03 \x01(\tR\blanguage\"\xda\x01\n" +
+	"\blanguage\x18\x03 \x01(\tR\blanguage\x12\x18\n" +
+	"\aarchive\x18\x04 \x01(\bR\aarchive\":\n" +
+	"\n" +
+	"SourceFile\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x18\n" +
+	"\acontent\x18\x02 \x01(\tR\acontent\"\x88\x02\n" +
 	"\n" +
 	"Submission\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
@@ -291,9 +691,39 @@ const file_submission_proto_rawDesc = "" +
 	"\n" +
 	"created_at\x18\a \x01(\tR\tcreatedAt\x12\x1d\n" +
 	"\n" +
-	"updated_at\x18\b \x01(\tR\tupdatedAt2f\n" +
+	"updated_at\x18\b \x01(\tR\tupdatedAt\x12,\n" +
+	"\x05files\x18\t \x03(\v2\x16.submission.SourceFileR\x05files\"\xdf\x01\n" +
+	"\x0eRejudgeRequest\x12!\n" +
+	"\frequested_by\x18\x01 \x01(\tR\vrequestedBy\x12#\n" +
+	"\rsubmission_id\x18\x02 \x01(\tR\fsubmissionId\x12\x1d\n" +
+	"\n" +
+	"problem_id\x18\x03 \x01(\tR\tproblemId\x12\x1a\n" +
+	"\bstatuses\x18\x04 \x03(\tR\bstatuses\x12#\n" +
+	"\rcreated_after\x18\x05 \x01(\tR\fcreatedAfter\x12%\n" +
+	"\x0ecreated_before\x18\x06 \x01(\tR\rcreatedBefore\"#\n" +
+	"\x11GetRejudgeRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\xcb\x01\n" +
+	"\fRejudgeEntry\x12#\n" +
+	"\rsubmission_id\x18\x01 \x01(\tR\fsubmissionId\x12'\n" +
+	"\x0fprevious_status\x18\x02 \x01(\tR\x0epreviousStatus\x12%\n" +
+	"\x0eprevious_score\x18\x03 \x01(\x01R\rpreviousScore\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12\x14\n" +
+	"\x05score\x18\x05 \x01(\x01R\x05score\x12\x18\n" +
+	"\achanged\x18\x06 \x01(\bR\achanged\"\xe7\x01\n" +
+	"\rRejudgeReport\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12!\n" +
+	"\frequested_by\x18\x02 \x01(\tR\vrequestedBy\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x03 \x01(\tR\tcreatedAt\x12\x14\n" +
+	"\x05total\x18\x04 \x01(\x05R\x05total\x12\x18\n" +
+	"\apending\x18\x05 \x01(\x05R\apending\x12\x18\n" +
+	"\achanged\x18\x06 \x01(\x05R\achanged\x12:\n" +
+	"\vsubmissions\x18\a \x03(\v2\x18.submission.RejudgeEntryR\vsubmissions2\xf0\x01\n" +
 	"\x11SubmissionService\x12Q\n" +
-	"\x10CreateSubmission\x12#.submission.CreateSubmissionRequest\x1a\x16.submission.Submission(\x01B(Z&code-checker/pkg/submission;submissionb\x06proto3"
+	"\x10CreateSubmission\x12#.submission.CreateSubmissionRequest\x1a\x16.submission.Submission(\x01\x12@\n" +
+	"\aRejudge\x12\x1a.submission.RejudgeRequest\x1a\x19.submission.RejudgeReport\x12F\n" +
+	"\n" +
+	"GetRejudge\x12\x1d.submission.GetRejudgeRequest\x1a\x19.submission.RejudgeReportB(Z&code-checker/pkg/submission;submissionb\x06proto3"
 
 var (
 	file_submission_proto_rawDescOnce sync.Once
@@ -307,21 +737,32 @@ func file_submission_proto_rawDescGZIP() []byte {
 	return file_submission_proto_rawDescData
 }
 
-var file_submission_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_submission_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
 var file_submission_proto_goTypes = []any{
 	(*CreateSubmissionRequest)(nil), // 0: submission.CreateSubmissionRequest
 	(*SubmissionInfo)(nil),          // 1: submission.SubmissionInfo
-	(*Submission)(nil),              // 2: submission.Submission
+	(*SourceFile)(nil),              // 2: submission.SourceFile
+	(*Submission)(nil),              // 3: submission.Submission
+	(*RejudgeRequest)(nil),          // 4: submission.RejudgeRequest
+	(*GetRejudgeRequest)(nil),       // 5: submission.GetRejudgeRequest
+	(*RejudgeEntry)(nil),            // 6: submission.RejudgeEntry
+	(*RejudgeReport)(nil),           // 7: submission.RejudgeReport
 }
 var file_submission_proto_depIdxs = []int32{
 	1, // 0: submission.CreateSubmissionRequest.info:type_name -> submission.SubmissionInfo
-	0, // 1: submission.SubmissionService.CreateSubmission:input_type -> submission.CreateSubmissionRequest
-	2, // 2: submission.SubmissionService.CreateSubmission:output_type -> submission.Submission
-	2, // [2:3] is the sub-list for method output_type
-	1, // [1:2] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	2, // 1: submission.Submission.files:type_name -> submission.SourceFile
+	6, // 2: submission.RejudgeReport.submissions:type_name -> submission.RejudgeEntry
+	0, // 3: submission.SubmissionService.CreateSubmission:input_type -> submission.CreateSubmissionRequest
+	4, // 4: submission.SubmissionService.Rejudge:input_type -> submission.RejudgeRequest
+	5, // 5: submission.SubmissionService.GetRejudge:input_type -> submission.GetRejudgeRequest
+	3, // 6: submission.SubmissionService.CreateSubmission:output_type -> submission.Submission
+	7, // 7: submission.SubmissionService.Rejudge:output_type -> submission.RejudgeReport
+	7, // 8: submission.SubmissionService.GetRejudge:output_type -> submission.RejudgeReport
+	6, // [6:9] is the sub-list for method output_type
+	3, // [3:6] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
 }
 
 func init() { file_submission_proto_init() }
@@ -332,6 +773,7 @@ func file_submission_proto_init() {
 	file_submission_proto_msgTypes[0].OneofWrappers = []any{
 		(*CreateSubmissionRequest_Info)(nil),
 		(*CreateSubmissionRequest_ChunkData)(nil),
+		(*CreateSubmissionRequest_FilePath)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -339,7 +781,7 @@ func file_submission_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_submission_proto_rawDesc), len(file_submission_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   3,
+			NumMessages:   8,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
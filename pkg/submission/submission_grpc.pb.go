@@ -20,6 +20,8 @@ const _ = grpc.SupportPackageIsVersion9
 
 const (
 	SubmissionService_CreateSubmission_FullMethodName = "/submission.SubmissionService/CreateSubmission"
+	SubmissionService_Rejudge_FullMethodName          = "/submission.SubmissionService/Rejudge"
+	SubmissionService_GetRejudge_FullMethodName       = "/submission.SubmissionService/GetRejudge"
 )
 
 // SubmissionServiceClient is the client API for SubmissionService service.
@@ -27,6 +29,8 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type SubmissionServiceClient interface {
 	CreateSubmission(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[CreateSubmissionRequest, Submission], error)
+	Rejudge(ctx context.Context, in *RejudgeRequest, opts ...grpc.CallOption) (*RejudgeReport, error)
+	GetRejudge(ctx context.Context, in *GetRejudgeRequest, opts ...grpc.CallOption) (*RejudgeReport, error)
 }
 
 type submissionServiceClient struct {
@@ -50,11 +54,33 @@ func (c *submissionServiceClient) CreateSubmission(ctx context.Context, opts ...
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type SubmissionService_CreateSubmissionClient = grpc.ClientStreamingClient[CreateSubmissionRequest, Submission]
 
+func (c *submissionServiceClient) Rejudge(ctx context.Context, in *RejudgeRequest, opts ...grpc.CallOption) (*RejudgeReport, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RejudgeReport)
+	err := c.cc.Invoke(ctx, SubmissionService_Rejudge_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *submissionServiceClient) GetRejudge(ctx context.Context, in *GetRejudgeRequest, opts ...grpc.CallOption) (*RejudgeReport, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RejudgeReport)
+	err := c.cc.Invoke(ctx, SubmissionService_GetRejudge_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // SubmissionServiceServer is the server API for SubmissionService service.
 // All implementations must embed UnimplementedSubmissionServiceServer
 // for forward compatibility.
 type SubmissionServiceServer interface {
 	CreateSubmission(grpc.ClientStreamingServer[CreateSubmissionRequest, Submission]) error
+	Rejudge(context.Context, *RejudgeRequest) (*RejudgeReport, error)
+	GetRejudge(context.Context, *GetRejudgeRequest) (*RejudgeReport, error)
 	mustEmbedUnimplementedSubmissionServiceServer()
 }
 
@@ -68,6 +94,12 @@ type UnimplementedSubmissionServiceServer struct{}
 func (UnimplementedSubmissionServiceServer) CreateSubmission(grpc.ClientStreamingServer[CreateSubmissionRequest, Submission]) error {
 	return status.Errorf(codes.Unimplemented, "method CreateSubmission not implemented")
 }
+func (UnimplementedSubmissionServiceServer) Rejudge(context.Context, *RejudgeRequest) (*RejudgeReport, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Rejudge not implemented")
+}
+func (UnimplementedSubmissionServiceServer) GetRejudge(context.Context, *GetRejudgeRequest) (*RejudgeReport, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRejudge not implemented")
+}
 func (UnimplementedSubmissionServiceServer) mustEmbedUnimplementedSubmissionServiceServer() {}
 func (UnimplementedSubmissionServiceServer) testEmbeddedByValue()                           {}
 
@@ -96,13 +128,58 @@ func _SubmissionService_CreateSubmission_Handler(srv interface{}, stream grpc.Se
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type SubmissionService_CreateSubmissionServer = grpc.ClientStreamingServer[CreateSubmissionRequest, Submission]
 
+func _SubmissionService_Rejudge_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RejudgeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubmissionServiceServer).Rejudge(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SubmissionService_Rejudge_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubmissionServiceServer).Rejudge(ctx, req.(*RejudgeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SubmissionService_GetRejudge_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRejudgeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubmissionServiceServer).GetRejudge(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SubmissionService_GetRejudge_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubmissionServiceServer).GetRejudge(ctx, req.(*GetRejudgeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // SubmissionService_ServiceDesc is the grpc.ServiceDesc for SubmissionService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
 var SubmissionService_ServiceDesc = grpc.ServiceDesc{
 	ServiceName: "submission.SubmissionService",
 	HandlerType: (*SubmissionServiceServer)(nil),
-	Methods:     []grpc.MethodDesc{},
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Rejudge",
+			Handler:    _SubmissionService_Rejudge_Handler,
+		},
+		{
+			MethodName: "GetRejudge",
+			Handler:    _SubmissionService_GetRejudge_Handler,
+		},
+	},
 	Streams: []grpc.StreamDesc{
 		{
 			StreamName:    "CreateSubmission",
@@ -14,4 +14,6 @@ until kafka-topics --bootstrap-server kafka:29092 --list >/dev/null 2>&1; do
  done
 
 kafka-topics --bootstrap-server kafka:29092 --create --if-not-exists --topic submissions --partitions 1 --replication-factor 1
-kafka-topics --bootstrap-server kafka:29092 --create --if-not-exists --topic results --partitions 1 --replication-factor 1
\ No newline at end of file
+kafka-topics --bootstrap-server kafka:29092 --create --if-not-exists --topic results --partitions 1 --replication-factor 1
+kafka-topics --bootstrap-server kafka:29092 --create --if-not-exists --topic submissions_dlq --partitions 1 --replication-factor 1
+kafka-topics --bootstrap-server kafka:29092 --create --if-not-exists --topic problem_events --partitions 1 --replication-factor 1
\ No newline at end of file
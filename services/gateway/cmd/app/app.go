@@ -6,11 +6,13 @@ import (
 	"net/http"
 
 	authpb "github.com/DeadlyParkour777/code-checker/pkg/auth"
+	judgepb "github.com/DeadlyParkour777/code-checker/pkg/judge"
 	problempb "github.com/DeadlyParkour777/code-checker/pkg/problem"
 	resultpb "github.com/DeadlyParkour777/code-checker/pkg/result"
 	submissionpb "github.com/DeadlyParkour777/code-checker/pkg/submission"
 	"github.com/DeadlyParkour777/code-checker/services/gateway/internal/cache"
 	"github.com/DeadlyParkour777/code-checker/services/gateway/internal/config"
+	"github.com/DeadlyParkour777/code-checker/services/gateway/internal/events"
 	"github.com/DeadlyParkour777/code-checker/services/gateway/internal/handler"
 	"github.com/redis/go-redis/v9"
 	"google.golang.org/grpc"
@@ -48,10 +50,17 @@ func New(cfg config.Config) (*App, error) {
 	}
 	resultClient := resultpb.NewResultServiceClient(resultConn)
 
+	judgeConn, err := grpc.NewClient(cfg.JudgeServiceAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		log.Fatalf("Failed to connect to judge service: %v", err)
+	}
+	judgeClient := judgepb.NewJudgeServiceClient(judgeConn)
+
 	log.Println("gRPC clients initialized")
 
 	redisClient := redis.NewClient(&redis.Options{Addr: cfg.RedisAddr, Password: cfg.RedisPassword, DB: cfg.RedisDB})
 	jwtCache := cache.NewRedisJWTCache(redisClient)
+	submissionEvents := events.NewRedisSubmissionEvents(redisClient)
 	log.Println("Redis cache initialized")
 
 	httpHandler := handler.NewHandler(
@@ -59,7 +68,9 @@ func New(cfg config.Config) (*App, error) {
 		problemClient,
 		submissionClient,
 		resultClient,
+		judgeClient,
 		jwtCache,
+		submissionEvents,
 	)
 	log.Println("HTTP handler initialized")
 
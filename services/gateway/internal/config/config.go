@@ -15,6 +15,7 @@ type Config struct {
 	ProblemServiceAddr    string
 	SubmissionServiceAddr string
 	ResultServiceAddr     string
+	JudgeServiceAddr      string
 
 	RedisAddr     string
 	RedisPassword string
@@ -33,6 +34,7 @@ func ConfigInit() Config {
 		ProblemServiceAddr:    getEnv("PROBLEM_SERVICE_ADDR", "problem-service:8002"),
 		SubmissionServiceAddr: getEnv("SUBMISSION_SERVICE_ADDR", "submission-service:8004"),
 		ResultServiceAddr:     getEnv("RESULT_SERVICE_ADDR", "result-service:8003"),
+		JudgeServiceAddr:      getEnv("JUDGE_SERVICE_ADDR", "judge-service:8005"),
 		RedisAddr:             getEnv("REDIS_ADDR", "redis:6379"),
 		RedisPassword:         getEnv("REDIS_PASSWORD", ""),
 		RedisDB:               redisDB,
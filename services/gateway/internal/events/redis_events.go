@@ -0,0 +1,56 @@
+package events
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// SubmissionEvents delivers the state changes result_service publishes for a
+// submission. The returned channel is closed when ctx is done.
+type SubmissionEvents interface {
+	Subscribe(ctx context.Context, submissionID string) (<-chan []byte, error)
+}
+
+type redisSubmissionEvents struct {
+	client *redis.Client
+}
+
+func NewRedisSubmissionEvents(client *redis.Client) SubmissionEvents {
+	return &redisSubmissionEvents{client: client}
+}
+
+func (e *redisSubmissionEvents) Subscribe(ctx context.Context, submissionID string) (<-chan []byte, error) {
+	pubsub := e.client.Subscribe(ctx, fmt.Sprintf("submission-events:%s", submissionID))
+	// Wait for the confirmation so no event published after Subscribe
+	// returns is missed.
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		return nil, fmt.Errorf("failed to subscribe: %w", err)
+	}
+
+	events := make(chan []byte)
+	go func() {
+		defer close(events)
+		defer pubsub.Close()
+
+		messages := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-messages:
+				if !ok {
+					return
+				}
+				select {
+				case events <- []byte(msg.Payload):
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return events, nil
+}
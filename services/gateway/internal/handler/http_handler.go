@@ -1,25 +1,34 @@
 package handler
 
 import (
-	"bytes"
 	"context"
 	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
+	"mime/multipart"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	authpb "github.com/DeadlyParkour777/code-checker/pkg/auth"
+	judgepb "github.com/DeadlyParkour777/code-checker/pkg/judge"
 	problempb "github.com/DeadlyParkour777/code-checker/pkg/problem"
 	resultpb "github.com/DeadlyParkour777/code-checker/pkg/result"
 	submissionpb "github.com/DeadlyParkour777/code-checker/pkg/submission"
 	"github.com/DeadlyParkour777/code-checker/pkg/utils"
 	"github.com/DeadlyParkour777/code-checker/services/gateway/internal/cache"
+	"github.com/DeadlyParkour777/code-checker/services/gateway/internal/events"
 	"github.com/DeadlyParkour777/code-checker/services/gateway/internal/types"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/cors"
 	"github.com/go-playground/validator/v10"
 	httpSwagger "github.com/swaggo/http-swagger"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 //go:embed openapi.yaml
@@ -35,7 +44,9 @@ type Handler struct {
 	problemClient    problempb.ProblemServiceClient
 	submissionClient submissionpb.SubmissionServiceClient
 	resultClient     resultpb.ResultServiceClient
+	judgeClient      judgepb.JudgeServiceClient
 	jwtCache         cache.JWTCache
+	submissionEvents events.SubmissionEvents
 	validator        *validator.Validate
 }
 
@@ -44,14 +55,18 @@ func NewHandler(
 	problemClient problempb.ProblemServiceClient,
 	submissionClient submissionpb.SubmissionServiceClient,
 	resultClient resultpb.ResultServiceClient,
+	judgeClient judgepb.JudgeServiceClient,
 	jwtCache cache.JWTCache,
+	submissionEvents events.SubmissionEvents,
 ) *Handler {
 	return &Handler{
 		authClient:       authClient,
 		problemClient:    problemClient,
 		submissionClient: submissionClient,
 		resultClient:     resultClient,
+		judgeClient:      judgeClient,
 		jwtCache:         jwtCache,
+		submissionEvents: submissionEvents,
 		validator:        validator.New(),
 	}
 }
@@ -98,6 +113,8 @@ func (h *Handler) Routes() http.Handler {
 		r.Post("/login", h.handleLogin)
 	})
 
+	r.Get("/languages", h.handleListLanguages)
+
 	r.Route("/problems", func(r chi.Router) {
 		r.Get("/", h.handleListProblems)
 		r.Get("/{problemID}", h.handleGetProblem)
@@ -110,11 +127,23 @@ func (h *Handler) Routes() http.Handler {
 			r.Use(h.AdminOnlyMiddleware)
 			r.Post("/problems", h.handleCreateProblem)
 			r.Post("/problems/{problemID}/testcases", h.handleCreateTestCase)
+			r.Post("/testfiles", h.handleUploadTestFile)
+			r.Post("/problems/{problemID}/groups", h.handleCreateTestGroup)
+			r.Get("/problems/{problemID}/groups", h.handleListTestGroups)
+			r.Put("/problems/{problemID}/checker", h.handleSetChecker)
+			r.Get("/problems/{problemID}/checker", h.handleGetChecker)
+			r.Put("/problems/{problemID}/interactor", h.handleSetInteractor)
+			r.Get("/problems/{problemID}/interactor", h.handleGetInteractor)
+			r.Get("/admin/dead-letters", h.handleListDeadLetters)
+			r.Post("/admin/dead-letters/{offset}/replay", h.handleReplayDeadLetter)
+			r.Post("/admin/rejudges", h.handleRejudge)
+			r.Get("/admin/rejudges/{rejudgeID}", h.handleGetRejudge)
 		})
 
 		r.Route("/submissions", func(r chi.Router) {
 			r.Post("/", h.handleCreateSubmission)
 			r.Get("/history", h.handleGetUserSubmissions)
+			r.Get("/{submissionID}/events", h.handleSubmissionEvents)
 		})
 	})
 
@@ -224,9 +253,22 @@ func (h *Handler) handleCreateProblem(w http.ResponseWriter, r *http.Request) {
 	}
 
 	resp, err := h.problemClient.CreateProblem(r.Context(), &problempb.CreateProblemRequest{
-		Title:       req.Title,
-		Description: req.Description,
+		Title:         req.Title,
+		Description:   req.Description,
+		TimeLimitMs:   req.TimeLimitMs,
+		MemoryLimitMb: req.MemoryLimitMB,
+		OutputLimitKb: req.OutputLimitKB,
+		Comparator:    req.Comparator,
+		FloatAbsEps:   req.FloatAbsEps,
+		FloatRelEps:   req.FloatRelEps,
+		Type:          req.Type,
+		ScoringMode:   req.ScoringMode,
+		GoModules:     req.GoModules,
 	})
+	if status.Code(err) == codes.InvalidArgument {
+		utils.WriteError(w, http.StatusBadRequest, status.Convert(err).Message())
+		return
+	}
 	if err != nil {
 		utils.WriteError(w, http.StatusInternalServerError, err.Error())
 		return
@@ -245,6 +287,111 @@ func (h *Handler) handleListProblems(w http.ResponseWriter, r *http.Request) {
 	utils.WriteJSON(w, http.StatusOK, resp.Problems)
 }
 
+func (h *Handler) handleListLanguages(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.judgeClient.ListLanguages(r.Context(), &judgepb.ListLanguagesRequest{})
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, resp.Languages)
+}
+
+func (h *Handler) handleListDeadLetters(w http.ResponseWriter, r *http.Request) {
+	var limit int
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		var err error
+		limit, err = strconv.Atoi(raw)
+		if err != nil || limit <= 0 {
+			utils.WriteError(w, http.StatusBadRequest, "limit must be a positive integer")
+			return
+		}
+	}
+
+	resp, err := h.judgeClient.ListDeadLetters(r.Context(), &judgepb.ListDeadLettersRequest{Limit: int32(limit)})
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, resp.DeadLetters)
+}
+
+func (h *Handler) handleReplayDeadLetter(w http.ResponseWriter, r *http.Request) {
+	offset, err := strconv.ParseInt(chi.URLParam(r, "offset"), 10, 64)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, "offset must be an integer")
+		return
+	}
+
+	resp, err := h.judgeClient.ReplayDeadLetter(r.Context(), &judgepb.ReplayDeadLetterRequest{Offset: offset})
+	if status.Code(err) == codes.NotFound {
+		utils.WriteError(w, http.StatusNotFound, "Dead letter not found")
+		return
+	}
+	if status.Code(err) == codes.FailedPrecondition {
+		utils.WriteError(w, http.StatusConflict, "Dead letter already replayed")
+		return
+	}
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusAccepted, resp)
+}
+
+func (h *Handler) handleRejudge(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(userIDKey).(string)
+
+	var req types.RejudgeRequest
+	if err := utils.ParseJSON(r, &req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := h.validator.Struct(req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	resp, err := h.submissionClient.Rejudge(r.Context(), &submissionpb.RejudgeRequest{
+		RequestedBy:   userID,
+		SubmissionId:  req.SubmissionID,
+		ProblemId:     req.ProblemID,
+		Statuses:      req.Statuses,
+		CreatedAfter:  req.CreatedAfter,
+		CreatedBefore: req.CreatedBefore,
+	})
+	if status.Code(err) == codes.InvalidArgument {
+		utils.WriteError(w, http.StatusBadRequest, status.Convert(err).Message())
+		return
+	}
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusAccepted, resp)
+}
+
+func (h *Handler) handleGetRejudge(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.submissionClient.GetRejudge(r.Context(), &submissionpb.GetRejudgeRequest{Id: chi.URLParam(r, "rejudgeID")})
+	if status.Code(err) == codes.NotFound {
+		utils.WriteError(w, http.StatusNotFound, "Rejudge not found")
+		return
+	}
+	if status.Code(err) == codes.InvalidArgument {
+		utils.WriteError(w, http.StatusBadRequest, status.Convert(err).Message())
+		return
+	}
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, resp)
+}
+
 func (h *Handler) handleGetProblem(w http.ResponseWriter, r *http.Request) {
 	problemID := chi.URLParam(r, "problemID")
 	if problemID == "" {
@@ -276,16 +423,22 @@ func (h *Handler) handleCreateSubmission(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	file, _, err := r.FormFile("code_file")
-	if err != nil {
-		utils.WriteError(w, http.StatusBadRequest, "Code file with key 'code_file' is required")
-		return
-	}
-	defer file.Close()
-
-	codeData, err := io.ReadAll(file)
-	if err != nil {
-		utils.WriteError(w, http.StatusInternalServerError, "Failed to read code file")
+	// A submission is a single code_file, an archive (zip, tar or tar.gz) or
+	// several files; multipart drops directories from file names, so nested
+	// trees come as an archive.
+	codeFiles := r.MultipartForm.File["code_file"]
+	archives := r.MultipartForm.File["archive"]
+	files := r.MultipartForm.File["files"]
+	var sources []*multipart.FileHeader
+	switch {
+	case len(codeFiles) == 1 && len(archives) == 0 && len(files) == 0:
+		sources = codeFiles
+	case len(codeFiles) == 0 && len(archives) == 1 && len(files) == 0:
+		sources = archives
+	case len(codeFiles) == 0 && len(archives) == 0 && len(files) > 0:
+		sources = files
+	default:
+		utils.WriteError(w, http.StatusBadRequest, "Exactly one of 'code_file', 'archive' or 'files' is required")
 		return
 	}
 
@@ -299,35 +452,34 @@ func (h *Handler) handleCreateSubmission(w http.ResponseWriter, r *http.Request)
 		UserId:    userID,
 		ProblemId: problemID,
 		Language:  language,
+		Archive:   len(archives) > 0,
 	}
 	if err := stream.Send(&submissionpb.CreateSubmissionRequest{Data: &submissionpb.CreateSubmissionRequest_Info{Info: info}}); err != nil {
 		utils.WriteError(w, http.StatusInternalServerError, "Failed to send submission info: "+err.Error())
 		return
 	}
 
-	reader := bytes.NewReader(codeData)
-	buffer := make([]byte, 1024)
-
-	for {
-		n, err := reader.Read(buffer)
-		if err == io.EOF {
-			break
+	for _, source := range sources {
+		if len(files) > 0 {
+			req := &submissionpb.CreateSubmissionRequest{
+				Data: &submissionpb.CreateSubmissionRequest_FilePath{FilePath: source.Filename},
+			}
+			if err := stream.Send(req); err != nil {
+				utils.WriteError(w, http.StatusInternalServerError, "Failed to send file path: "+err.Error())
+				return
+			}
 		}
-		if err != nil {
-			utils.WriteError(w, http.StatusInternalServerError, "Failed to read code chunk from buffer: "+err.Error())
-			return
-		}
-
-		req := &submissionpb.CreateSubmissionRequest{
-			Data: &submissionpb.CreateSubmissionRequest_ChunkData{ChunkData: buffer[:n]},
-		}
-		if err := stream.Send(req); err != nil {
-			utils.WriteError(w, http.StatusInternalServerError, "Failed to send code chunk: "+err.Error())
+		if err := sendSubmissionFile(stream, source); err != nil {
+			utils.WriteError(w, http.StatusInternalServerError, err.Error())
 			return
 		}
 	}
 
 	resp, err := stream.CloseAndRecv()
+	if status.Code(err) == codes.InvalidArgument {
+		utils.WriteError(w, http.StatusBadRequest, status.Convert(err).Message())
+		return
+	}
 	if err != nil {
 		utils.WriteError(w, http.StatusInternalServerError, "Failed to receive submission response: "+err.Error())
 		return
@@ -336,6 +488,33 @@ func (h *Handler) handleCreateSubmission(w http.ResponseWriter, r *http.Request)
 	utils.WriteJSON(w, http.StatusAccepted, resp)
 }
 
+func sendSubmissionFile(stream submissionpb.SubmissionService_CreateSubmissionClient, fh *multipart.FileHeader) error {
+	file, err := fh.Open()
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", fh.Filename, err)
+	}
+	defer file.Close()
+
+	buffer := make([]byte, 32*1024)
+	for {
+		n, err := file.Read(buffer)
+		if n > 0 {
+			req := &submissionpb.CreateSubmissionRequest{
+				Data: &submissionpb.CreateSubmissionRequest_ChunkData{ChunkData: buffer[:n]},
+			}
+			if err := stream.Send(req); err != nil {
+				return fmt.Errorf("failed to send code chunk: %w", err)
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", fh.Filename, err)
+		}
+	}
+}
+
 func (h *Handler) handleGetUserSubmissions(w http.ResponseWriter, r *http.Request) {
 	userID := r.Context().Value(userIDKey).(string)
 
@@ -348,6 +527,93 @@ func (h *Handler) handleGetUserSubmissions(w http.ResponseWriter, r *http.Reques
 	utils.WriteJSON(w, http.StatusOK, resp.Submissions)
 }
 
+// sseKeepAlive is how often an idle event stream gets a comment, so proxies
+// do not close it while a long submission is judged.
+const sseKeepAlive = 15 * time.Second
+
+func (h *Handler) handleSubmissionEvents(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(userIDKey).(string)
+	submissionID := chi.URLParam(r, "submissionID")
+
+	// Subscribe before reading the current state so that nothing published
+	// in between is lost.
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	updates, err := h.submissionEvents.Subscribe(ctx, submissionID)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	sub, err := h.resultClient.GetSubmission(ctx, &resultpb.GetSubmissionRequest{Id: submissionID})
+	if status.Code(err) == codes.NotFound || (err == nil && sub.GetUserId() != userID) {
+		utils.WriteError(w, http.StatusNotFound, "Submission not found")
+		return
+	}
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	current := &types.SubmissionEvent{
+		SubmissionID: sub.GetId(),
+		Status:       sub.GetStatus(),
+		Stage:        sub.GetStage(),
+		CurrentTest:  int(sub.GetCurrentTest()),
+		TotalTests:   int(sub.GetTotalTests()),
+		MaxMemoryKb:  sub.GetMaxMemoryKb(),
+		Score:        sub.GetScore(),
+		MaxScore:     sub.GetMaxScore(),
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	rc := http.NewResponseController(w)
+
+	send := func(event *types.SubmissionEvent) error {
+		data, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "event: status\ndata: %s\n\n", data); err != nil {
+			return err
+		}
+		return rc.Flush()
+	}
+
+	if err := send(current); err != nil || current.Final() {
+		return
+	}
+
+	keepAlive := time.NewTicker(sseKeepAlive)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-keepAlive.C:
+			if _, err := io.WriteString(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+			if err := rc.Flush(); err != nil {
+				return
+			}
+		case data, ok := <-updates:
+			if !ok {
+				return
+			}
+			var event types.SubmissionEvent
+			if err := json.Unmarshal(data, &event); err != nil {
+				continue
+			}
+			if err := send(&event); err != nil || event.Final() {
+				return
+			}
+		}
+	}
+}
+
 func (h *Handler) handleCreateTestCase(w http.ResponseWriter, r *http.Request) {
 	problemID := chi.URLParam(r, "problemID")
 	if problemID == "" {
@@ -366,12 +632,20 @@ func (h *Handler) handleCreateTestCase(w http.ResponseWriter, r *http.Request) {
 	}
 
 	grpcReq := &problempb.CreateTestCaseRequest{
-		ProblemId:  problemID,
-		InputData:  req.InputData,
-		OutputData: req.OutputData,
+		ProblemId:    problemID,
+		InputData:    req.InputData,
+		OutputData:   req.OutputData,
+		InputSha256:  req.InputSHA256,
+		OutputSha256: req.OutputSHA256,
+		Points:       req.Points,
+		GroupId:      req.GroupID,
 	}
 
 	resp, err := h.problemClient.CreateTestCase(r.Context(), grpcReq)
+	if status.Code(err) == codes.InvalidArgument {
+		utils.WriteError(w, http.StatusBadRequest, status.Convert(err).Message())
+		return
+	}
 	if err != nil {
 		utils.WriteError(w, http.StatusInternalServerError, err.Error())
 		return
@@ -379,3 +653,229 @@ func (h *Handler) handleCreateTestCase(w http.ResponseWriter, r *http.Request) {
 
 	utils.WriteJSON(w, http.StatusCreated, resp)
 }
+
+// maxTestFileBytes bounds a single uploaded test file.
+const maxTestFileBytes = 1 << 30
+
+// errReadTestFile marks a failure to read the upload from the client, as
+// opposed to one of problem_service.
+var errReadTestFile = errors.New("failed to read test file")
+
+// handleUploadTestFile streams the "file" part of a multipart body to
+// problem_service chunk by chunk, without buffering the whole file.
+func (h *Handler) handleUploadTestFile(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxTestFileBytes)
+	reader, err := r.MultipartReader()
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, "Failed to parse multipart form: "+err.Error())
+		return
+	}
+
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			utils.WriteError(w, http.StatusBadRequest, "Missing 'file' in form")
+			return
+		}
+		if err != nil {
+			utils.WriteError(w, http.StatusBadRequest, "Failed to parse multipart form: "+err.Error())
+			return
+		}
+		if part.FormName() != "file" {
+			continue
+		}
+
+		resp, err := h.uploadTestFile(r.Context(), part)
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			utils.WriteError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("Test file exceeds %d bytes", tooLarge.Limit))
+			return
+		}
+		if errors.Is(err, errReadTestFile) {
+			utils.WriteError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if err != nil {
+			utils.WriteError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		utils.WriteJSON(w, http.StatusCreated, resp)
+		return
+	}
+}
+
+func (h *Handler) uploadTestFile(ctx context.Context, r io.Reader) (*problempb.TestFile, error) {
+	stream, err := h.problemClient.UploadTestFile(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, 1024*1024)
+	for {
+		n, err := io.ReadFull(r, buf)
+		if n > 0 {
+			if err := stream.Send(&problempb.TestFileChunk{Data: buf[:n]}); err != nil {
+				return nil, fmt.Errorf("failed to send test file: %w", err)
+			}
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", errReadTestFile, err)
+		}
+	}
+	return stream.CloseAndRecv()
+}
+
+func (h *Handler) handleCreateTestGroup(w http.ResponseWriter, r *http.Request) {
+	problemID := chi.URLParam(r, "problemID")
+	if problemID == "" {
+		utils.WriteError(w, http.StatusBadRequest, "Problem ID is required in URL")
+		return
+	}
+
+	var req types.CreateTestGroupRequest
+	if err := utils.ParseJSON(r, &req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := h.validator.Struct(req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	resp, err := h.problemClient.CreateTestGroup(r.Context(), &problempb.CreateTestGroupRequest{
+		ProblemId:   problemID,
+		Name:        req.Name,
+		Score:       req.Score,
+		Aggregation: req.Aggregation,
+		DependsOn:   req.DependsOn,
+	})
+	if status.Code(err) == codes.InvalidArgument {
+		utils.WriteError(w, http.StatusBadRequest, status.Convert(err).Message())
+		return
+	}
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusCreated, resp)
+}
+
+func (h *Handler) handleListTestGroups(w http.ResponseWriter, r *http.Request) {
+	problemID := chi.URLParam(r, "problemID")
+	if problemID == "" {
+		utils.WriteError(w, http.StatusBadRequest, "Problem ID is required in URL")
+		return
+	}
+
+	resp, err := h.problemClient.ListTestGroups(r.Context(), &problempb.ListTestGroupsRequest{ProblemId: problemID})
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, resp.Groups)
+}
+
+func (h *Handler) handleSetChecker(w http.ResponseWriter, r *http.Request) {
+	problemID := chi.URLParam(r, "problemID")
+	if problemID == "" {
+		utils.WriteError(w, http.StatusBadRequest, "Problem ID is required in URL")
+		return
+	}
+
+	var req types.SetProgramRequest
+	if err := utils.ParseJSON(r, &req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := h.validator.Struct(req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	resp, err := h.problemClient.SetChecker(r.Context(), &problempb.SetProgramRequest{
+		ProblemId:  problemID,
+		Language:   req.Language,
+		SourceCode: req.SourceCode,
+	})
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, resp)
+}
+
+func (h *Handler) handleGetChecker(w http.ResponseWriter, r *http.Request) {
+	problemID := chi.URLParam(r, "problemID")
+	if problemID == "" {
+		utils.WriteError(w, http.StatusBadRequest, "Problem ID is required in URL")
+		return
+	}
+
+	resp, err := h.problemClient.GetChecker(r.Context(), &problempb.GetProgramRequest{ProblemId: problemID})
+	if status.Code(err) == codes.NotFound {
+		utils.WriteError(w, http.StatusNotFound, "Checker not found")
+		return
+	}
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, resp)
+}
+
+func (h *Handler) handleSetInteractor(w http.ResponseWriter, r *http.Request) {
+	problemID := chi.URLParam(r, "problemID")
+	if problemID == "" {
+		utils.WriteError(w, http.StatusBadRequest, "Problem ID is required in URL")
+		return
+	}
+
+	var req types.SetProgramRequest
+	if err := utils.ParseJSON(r, &req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := h.validator.Struct(req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	resp, err := h.problemClient.SetInteractor(r.Context(), &problempb.SetProgramRequest{
+		ProblemId:  problemID,
+		Language:   req.Language,
+		SourceCode: req.SourceCode,
+	})
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, resp)
+}
+
+func (h *Handler) handleGetInteractor(w http.ResponseWriter, r *http.Request) {
+	problemID := chi.URLParam(r, "problemID")
+	if problemID == "" {
+		utils.WriteError(w, http.StatusBadRequest, "Problem ID is required in URL")
+		return
+	}
+
+	resp, err := h.problemClient.GetInteractor(r.Context(), &problempb.GetProgramRequest{ProblemId: problemID})
+	if status.Code(err) == codes.NotFound {
+		utils.WriteError(w, http.StatusNotFound, "Interactor not found")
+		return
+	}
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, resp)
+}
@@ -0,0 +1,224 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	problempb "github.com/DeadlyParkour777/code-checker/pkg/problem"
+	resultpb "github.com/DeadlyParkour777/code-checker/pkg/result"
+	"github.com/DeadlyParkour777/code-checker/services/gateway/internal/types"
+	"github.com/go-chi/chi/v5"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeProblemClient fails every call with err, if set.
+type fakeProblemClient struct {
+	problempb.ProblemServiceClient
+	err error
+}
+
+func (c *fakeProblemClient) UploadTestFile(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[problempb.TestFileChunk, problempb.TestFile], error) {
+	return &fakeUploadStream{err: c.err}, nil
+}
+
+type fakeUploadStream struct {
+	grpc.ClientStreamingClient[problempb.TestFileChunk, problempb.TestFile]
+	size int64
+	err  error
+}
+
+func (s *fakeUploadStream) Send(chunk *problempb.TestFileChunk) error {
+	s.size += int64(len(chunk.GetData()))
+	return nil
+}
+
+func (s *fakeUploadStream) CloseAndRecv() (*problempb.TestFile, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &problempb.TestFile{Sha256: "abc", Size: s.size}, nil
+}
+
+func (c *fakeProblemClient) CreateTestGroup(ctx context.Context, in *problempb.CreateTestGroupRequest, opts ...grpc.CallOption) (*problempb.TestGroup, error) {
+	return nil, c.err
+}
+
+// withProblemID routes r as a request under /problems/{problemID}.
+func withProblemID(r *http.Request, problemID string) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("problemID", problemID)
+	return r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+}
+
+type fakeResultClient struct {
+	resultpb.ResultServiceClient
+	submission *resultpb.Submission
+}
+
+func (c *fakeResultClient) GetSubmission(ctx context.Context, in *resultpb.GetSubmissionRequest, opts ...grpc.CallOption) (*resultpb.Submission, error) {
+	return c.submission, nil
+}
+
+// fakeSubmissionEvents hands out a channel filled by the test.
+type fakeSubmissionEvents struct {
+	updates chan []byte
+}
+
+func (e *fakeSubmissionEvents) Subscribe(ctx context.Context, submissionID string) (<-chan []byte, error) {
+	return e.updates, nil
+}
+
+func serveSubmissionEvents(t *testing.T, submission *resultpb.Submission, userID string, published ...string) *httptest.ResponseRecorder {
+	t.Helper()
+	updates := make(chan []byte, len(published))
+	for _, event := range published {
+		updates <- []byte(event)
+	}
+	h := &Handler{
+		resultClient:     &fakeResultClient{submission: submission},
+		submissionEvents: &fakeSubmissionEvents{updates: updates},
+	}
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("submissionID", submission.GetId())
+	ctx := context.WithValue(context.Background(), chi.RouteCtxKey, rctx)
+	ctx = context.WithValue(ctx, userIDKey, userID)
+	r := httptest.NewRequest(http.MethodGet, "/submissions/"+submission.GetId()+"/events", nil).WithContext(ctx)
+
+	w := httptest.NewRecorder()
+	h.handleSubmissionEvents(w, r)
+	return w
+}
+
+func parseSubmissionEvents(t *testing.T, body string) []types.SubmissionEvent {
+	t.Helper()
+	var events []types.SubmissionEvent
+	for _, block := range strings.Split(strings.TrimSpace(body), "\n\n") {
+		data, ok := strings.CutPrefix(block, "event: status\ndata: ")
+		if !ok {
+			t.Fatalf("unexpected event %q", block)
+		}
+		var event types.SubmissionEvent
+		if err := json.Unmarshal([]byte(data), &event); err != nil {
+			t.Fatalf("bad event data %q: %v", data, err)
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+func TestHandleSubmissionEvents(t *testing.T) {
+	submission := &resultpb.Submission{Id: "s1", UserId: "u1", Status: "Pending"}
+	w := serveSubmissionEvents(t, submission, "u1",
+		`{"submission_id": "s1", "status": "Judging", "stage": "Running", "current_test": 1, "total_tests": 2}`,
+		`{"submission_id": "s1", "status": "WA", "message": "Wrong Answer.\nExpected:\nsecret", "score": 1, "max_score": 2}`,
+		`{"submission_id": "s1", "status": "AC"}`,
+	)
+
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("unexpected response %d %q", w.Code, w.Header().Get("Content-Type"))
+	}
+	if strings.Contains(w.Body.String(), "secret") {
+		t.Fatalf("the verdict message leaked into the stream: %s", w.Body.String())
+	}
+	events := parseSubmissionEvents(t, w.Body.String())
+	// The stream ends with the verdict, so the event after it is not sent.
+	if len(events) != 3 {
+		t.Fatalf("expected the current state, progress and verdict, got %+v", events)
+	}
+	if events[0].Status != "Pending" || events[1].CurrentTest != 1 || events[1].TotalTests != 2 {
+		t.Fatalf("unexpected events: %+v", events)
+	}
+	if events[2].Status != "WA" || events[2].Score != 1 || events[2].MaxScore != 2 {
+		t.Fatalf("unexpected verdict: %+v", events[2])
+	}
+}
+
+func TestHandleSubmissionEventsFinal(t *testing.T) {
+	submission := &resultpb.Submission{Id: "s1", UserId: "u1", Status: "AC"}
+	w := serveSubmissionEvents(t, submission, "u1", `{"submission_id": "s1", "status": "Pending"}`)
+
+	events := parseSubmissionEvents(t, w.Body.String())
+	if len(events) != 1 || events[0].Status != "AC" {
+		t.Fatalf("expected only the verdict, got %+v", events)
+	}
+}
+
+func TestHandleSubmissionEventsOwner(t *testing.T) {
+	submission := &resultpb.Submission{Id: "s1", UserId: "u1", Status: "Judging"}
+	w := serveSubmissionEvents(t, submission, "u2", `{"submission_id": "s1", "status": "AC"}`)
+
+	if w.Code != http.StatusNotFound || strings.Contains(w.Body.String(), "event:") {
+		t.Fatalf("expected another user's submission to be hidden, got %d %s", w.Code, w.Body.String())
+	}
+}
+
+func TestHandleCreateTestGroup(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantCode int
+	}{
+		{name: "unknown dependency", err: status.Error(codes.InvalidArgument, "unknown test group: g9"), wantCode: http.StatusBadRequest},
+		{name: "problem service failure", err: status.Error(codes.Unavailable, "connection refused"), wantCode: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHandler(nil, &fakeProblemClient{err: tt.err}, nil, nil, nil, nil, nil)
+			r := httptest.NewRequest(http.MethodPost, "/problems/p1/groups", strings.NewReader(`{"name": "full", "depends_on": ["g9"]}`))
+
+			w := httptest.NewRecorder()
+			h.handleCreateTestGroup(w, withProblemID(r, "p1"))
+			if w.Code != tt.wantCode {
+				t.Fatalf("expected %d, got %d %s", tt.wantCode, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+// failingReader stands for a request body that breaks off with err.
+type failingReader struct {
+	err error
+}
+
+func (r failingReader) Read(p []byte) (int, error) {
+	return 0, r.err
+}
+
+func TestHandleUploadTestFile(t *testing.T) {
+	const part = "--b\r\nContent-Disposition: form-data; name=\"file\"; filename=\"01.in\"\r\n\r\n1 2\n"
+	tests := []struct {
+		name     string
+		body     io.Reader
+		err      error
+		wantCode int
+	}{
+		{name: "stored", body: strings.NewReader(part + "\r\n--b--\r\n"), wantCode: http.StatusCreated},
+		{name: "too large", body: io.MultiReader(strings.NewReader(part), failingReader{&http.MaxBytesError{Limit: 4}}), wantCode: http.StatusRequestEntityTooLarge},
+		{name: "client read error", body: io.MultiReader(strings.NewReader(part), failingReader{errors.New("connection reset by peer")}), wantCode: http.StatusBadRequest},
+		{name: "problem service failure", body: strings.NewReader(part + "\r\n--b--\r\n"), err: status.Error(codes.Unavailable, "connection refused"), wantCode: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHandler(nil, &fakeProblemClient{err: tt.err}, nil, nil, nil, nil, nil)
+			r := httptest.NewRequest(http.MethodPost, "/testfiles", tt.body)
+			r.Header.Set("Content-Type", "multipart/form-data; boundary=b")
+
+			w := httptest.NewRecorder()
+			h.handleUploadTestFile(w, r)
+			if w.Code != tt.wantCode {
+				t.Fatalf("expected %d, got %d %s", tt.wantCode, w.Code, w.Body.String())
+			}
+		})
+	}
+}
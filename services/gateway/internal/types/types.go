@@ -11,8 +11,17 @@ type LoginRequest struct {
 }
 
 type CreateProblemRequest struct {
-	Title       string `json:"title" validate:"required"`
-	Description string `json:"description"`
+	Title         string   `json:"title" validate:"required"`
+	Description   string   `json:"description"`
+	TimeLimitMs   int32    `json:"time_limit_ms" validate:"omitempty,min=100,max=60000"`
+	MemoryLimitMB int32    `json:"memory_limit_mb" validate:"omitempty,min=16,max=4096"`
+	OutputLimitKB int32    `json:"output_limit_kb" validate:"omitempty,min=1,max=262144"`
+	Comparator    string   `json:"comparator" validate:"omitempty,oneof=trim exact tokens float case_insensitive unordered_lines"`
+	FloatAbsEps   float64  `json:"float_abs_eps" validate:"omitempty,min=0"`
+	FloatRelEps   float64  `json:"float_rel_eps" validate:"omitempty,min=0"`
+	Type          string   `json:"type" validate:"omitempty,oneof=standard interactive"`
+	ScoringMode   string   `json:"scoring_mode" validate:"omitempty,oneof=icpc points"`
+	GoModules     []string `json:"go_modules" validate:"omitempty,dive,required"`
 }
 
 type JSONResponse struct {
@@ -21,7 +30,52 @@ type JSONResponse struct {
 	Data    any    `json:"data,omitempty"`
 }
 
+// CreateTestCaseRequest carries small tests inline. Large ones are uploaded
+// with POST /testfiles first and referenced by checksum.
 type CreateTestCaseRequest struct {
-	InputData  string `json:"input_data" validate:"required"`
-	OutputData string `json:"output_data" validate:"required"`
+	InputData    string `json:"input_data" validate:"required_without=InputSHA256"`
+	OutputData   string `json:"output_data" validate:"required_without=OutputSHA256"`
+	InputSHA256  string `json:"input_sha256" validate:"omitempty,len=64,hexadecimal"`
+	OutputSHA256 string `json:"output_sha256" validate:"omitempty,len=64,hexadecimal"`
+	Points       int32  `json:"points" validate:"omitempty,min=0"`
+	GroupID      string `json:"group_id"`
+}
+
+type CreateTestGroupRequest struct {
+	Name        string   `json:"name" validate:"required"`
+	Score       int32    `json:"score" validate:"omitempty,min=0"`
+	Aggregation string   `json:"aggregation" validate:"omitempty,oneof=all_or_nothing min"`
+	DependsOn   []string `json:"depends_on"`
+}
+
+type SetProgramRequest struct {
+	Language   string `json:"language" validate:"required"`
+	SourceCode string `json:"source_code" validate:"required"`
+}
+
+type RejudgeRequest struct {
+	SubmissionID  string   `json:"submission_id" validate:"omitempty,uuid"`
+	ProblemID     string   `json:"problem_id" validate:"omitempty,uuid"`
+	Statuses      []string `json:"statuses"`
+	CreatedAfter  string   `json:"created_after" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	CreatedBefore string   `json:"created_before" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+}
+
+// SubmissionEvent is one state of a submission pushed by
+// GET /submissions/{submissionID}/events. It has no verdict message: the
+// judge puts hidden test data and checker comments there.
+type SubmissionEvent struct {
+	SubmissionID string  `json:"submission_id"`
+	Status       string  `json:"status"`
+	Stage        string  `json:"stage,omitempty"`
+	CurrentTest  int     `json:"current_test,omitempty"`
+	TotalTests   int     `json:"total_tests,omitempty"`
+	MaxMemoryKb  int64   `json:"max_memory_kb,omitempty"`
+	Score        float64 `json:"score,omitempty"`
+	MaxScore     float64 `json:"max_score,omitempty"`
+}
+
+// Final reports whether the submission has its verdict.
+func (e *SubmissionEvent) Final() bool {
+	return e.Status != "Pending" && e.Status != "Judging"
 }
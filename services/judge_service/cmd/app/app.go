@@ -2,20 +2,40 @@ package app
 
 import (
 	"context"
+	"fmt"
 	"log"
+	"net"
+	"os/signal"
 	"sync"
+	"syscall"
 	"time"
 
+	judgepb "github.com/DeadlyParkour777/code-checker/pkg/judge"
 	"github.com/DeadlyParkour777/code-checker/services/judge_service/internal/config"
+	"github.com/DeadlyParkour777/code-checker/services/judge_service/internal/deadletter"
 	"github.com/DeadlyParkour777/code-checker/services/judge_service/internal/handler"
+	"github.com/DeadlyParkour777/code-checker/services/judge_service/internal/languages"
+	"github.com/DeadlyParkour777/code-checker/services/judge_service/internal/sandbox"
 	"github.com/DeadlyParkour777/code-checker/services/judge_service/internal/service"
+	"github.com/DeadlyParkour777/code-checker/services/judge_service/internal/testcache"
 	"github.com/segmentio/kafka-go"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
 )
 
 type App struct {
-	kafkaReader *kafka.Reader
-	handler     *handler.KafkaConsumer
-	workerCount int
+	grpcServer         *grpc.Server
+	kafkaReader        *kafka.Reader
+	problemReader      *kafka.Reader
+	kafkaProducer      *kafka.Writer
+	progressProducer   *kafka.Writer
+	submissionProducer *kafka.Writer
+	deadLetters        deadletter.Queue
+	sandbox            sandbox.Sandbox
+	handler            *handler.KafkaConsumer
+	problemHandler     *handler.ProblemConsumer
+	workerCount        int
+	cfg                config.Config
 }
 
 func New(cfg config.Config) (*App, error) {
@@ -31,6 +51,18 @@ func New(cfg config.Config) (*App, error) {
 	})
 	log.Println("Kafka reader initialized")
 
+	// Every judge needs every problem event to keep its test cache fresh, so
+	// this reader has no group and starts at the end of the single partition;
+	// events before startup are irrelevant to an empty cache.
+	problemReader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:  cfg.KafkaBrokers,
+		Topic:    cfg.ProblemEventsTopic,
+		MaxBytes: 10e6,
+	})
+	if err := problemReader.SetOffset(kafka.LastOffset); err != nil {
+		return nil, fmt.Errorf("failed to seek problem events: %w", err)
+	}
+
 	// dialer := &kafka.Dialer{
 	// 	Timeout:   10 * time.Second,
 	// 	DualStack: true,
@@ -47,69 +79,243 @@ func New(cfg config.Config) (*App, error) {
 	kafkaProducer := &kafka.Writer{
 		Addr:         kafka.TCP(cfg.KafkaBrokers...),
 		Topic:        cfg.ResultTopic,
-		Balancer:     &kafka.LeastBytes{},
+		Balancer:     &kafka.Hash{},
 		RequiredAcks: kafka.RequireOne,
 		MaxAttempts:  10,
 		// Transport:    transport,
 	}
 	log.Println("Kafka producer initialized")
 
+	// Progress events are best effort and must not slow judging down, so
+	// they go out asynchronously. Both writers key messages by submission,
+	// keeping the events of one submission in a single partition.
+	progressProducer := &kafka.Writer{
+		Addr:         kafka.TCP(cfg.KafkaBrokers...),
+		Topic:        cfg.ResultTopic,
+		Balancer:     &kafka.Hash{},
+		RequiredAcks: kafka.RequireOne,
+		BatchTimeout: 50 * time.Millisecond,
+		Async:        true,
+		Completion: func(messages []kafka.Message, err error) {
+			if err != nil {
+				log.Printf("Failed to write %d progress events: %v", len(messages), err)
+			}
+		},
+	}
+
+	// Replayed dead letters go back to the submissions topic.
+	submissionProducer := &kafka.Writer{
+		Addr:         kafka.TCP(cfg.KafkaBrokers...),
+		Topic:        cfg.SubmissionTopic,
+		Balancer:     &kafka.Hash{},
+		RequiredAcks: kafka.RequireOne,
+		MaxAttempts:  10,
+	}
+	deadLetters := deadletter.NewKafkaQueue(cfg.KafkaBrokers, cfg.DeadLetterTopic)
+
+	registry, err := languages.Load(cfg.LanguagesFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load languages: %w", err)
+	}
+	log.Printf("Loaded %d languages", len(registry.List()))
+
+	profile := sandbox.Profile{
+		PidsLimit:       int64(cfg.SandboxPidsLimit),
+		RunUser:         cfg.SandboxRunUser,
+		BuildUser:       cfg.SandboxBuildUser,
+		ReadOnlyRootfs:  cfg.SandboxReadOnlyRootfs,
+		FileSizeLimitMB: int64(cfg.SandboxFileSizeLimitMB),
+		SeccompProfile:  cfg.SandboxSeccompProfile,
+	}
+	memoryLimit := int64(cfg.MemoryLimitMB) * 1024 * 1024
+
+	var sb sandbox.Sandbox
+	switch cfg.SandboxBackend {
+	case "docker":
+		sb, err = sandbox.NewDocker(profile, memoryLimit)
+	case "local":
+		sb, err = sandbox.NewLocal(profile, memoryLimit, cfg.LocalSandboxDir, cfg.LocalSandboxCgroup)
+	case "wasm":
+		sb, err = sandbox.NewWasm(memoryLimit, cfg.WasmSandboxDir)
+		registry = registry.Filter(func(lang languages.Language) bool { return lang.WasmCompile != "" })
+	default:
+		err = fmt.Errorf("unknown backend %q", cfg.SandboxBackend)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create sandbox: %w", err)
+	}
+	log.Printf("Sandbox initialized (%s)", cfg.SandboxBackend)
+
 	appService := service.NewService(
 		kafkaProducer,
+		progressProducer,
+		submissionProducer,
+		deadLetters,
+		registry,
 		time.Duration(cfg.ExecutionTimeoutSeconds)*time.Second,
-		cfg.HostTempPath,
+		cfg.MemoryLimitMB,
+		cfg.CompileMemoryLimitMB,
+		cfg.OutputLimitKB,
 		cfg.WorkerCount,
+		sb,
 		cfg.ProblemServiceAddr,
+		testcache.New(int64(cfg.TestCacheMB)*1024*1024),
 	)
 	log.Println("Service layer initialized")
 
-	kafkaHandler := handler.NewKafkaConsumer(appService)
+	kafkaHandler := handler.NewKafkaConsumer(appService, handler.RetryPolicy{
+		MaxAttempts: cfg.MaxAttempts,
+		Backoff:     time.Duration(cfg.RetryBackoffMs) * time.Millisecond,
+	})
+	problemHandler := handler.NewProblemConsumer(appService)
 	log.Println("Kafka handler initialized")
 
+	grpcServer := grpc.NewServer()
+	judgepb.RegisterJudgeServiceServer(grpcServer, handler.NewGrpcHandler(appService))
+	reflection.Register(grpcServer)
+
 	return &App{
-		kafkaReader: kafkaReader,
-		handler:     kafkaHandler,
-		workerCount: cfg.WorkerCount,
+		grpcServer:         grpcServer,
+		kafkaReader:        kafkaReader,
+		problemReader:      problemReader,
+		kafkaProducer:      kafkaProducer,
+		progressProducer:   progressProducer,
+		submissionProducer: submissionProducer,
+		deadLetters:        deadLetters,
+		sandbox:            sb,
+		handler:            kafkaHandler,
+		problemHandler:     problemHandler,
+		workerCount:        cfg.WorkerCount,
+		cfg:                cfg,
 	}, nil
 }
 
 func (a *App) Run() error {
-	defer a.kafkaReader.Close()
+	defer a.close()
+
+	go func() {
+		listenAddr := fmt.Sprintf(":%s", a.cfg.GRPCPort)
+
+		lis, err := net.Listen("tcp", listenAddr)
+		if err != nil {
+			log.Fatalf("gRPC failed to listen: %v", err)
+		}
+		log.Printf("gRPC server started on %s", listenAddr)
+		if err := a.grpcServer.Serve(lis); err != nil {
+			log.Fatalf("gRPC server failed: %v", err)
+		}
+	}()
 
 	log.Println("Judge service worker started. Waiting for submissions...")
-	ctx := context.Background()
+	// ctx ends on SIGINT/SIGTERM and stops fetching; submissions already
+	// being judged keep jobCtx until they finish or the drain deadline.
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	jobCtx, cancelJobs := context.WithCancel(context.Background())
+	defer cancelJobs()
 
+	go a.consumeProblemEvents(ctx)
+
+	// Workers finish out of order, and committing an offset commits the
+	// earlier ones too; the committer only commits past messages that are
+	// all done, so skipped, aborted and failed ones are judged again after a
+	// restart.
 	jobs := make(chan kafka.Message, a.workerCount)
+	committer := handler.NewCommitter(a.kafkaReader)
 	var wg sync.WaitGroup
-	var commitMu sync.Mutex
 
 	for i := 0; i < a.workerCount; i++ {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
 			for msg := range jobs {
-				if err := a.handler.ProcessMessage(ctx, msg); err != nil {
+				// Fetched but not started: leave it to the next judge.
+				if ctx.Err() != nil {
+					continue
+				}
+				if err := a.handler.ProcessMessage(jobCtx, msg); err != nil {
 					log.Printf("failed to process message: %v", err)
 					continue
 				}
-				commitMu.Lock()
-				if err := a.kafkaReader.CommitMessages(ctx, msg); err != nil {
+				if err := committer.Done(context.Background(), msg); err != nil {
 					log.Printf("failed to commit message: %v", err)
 				}
-				commitMu.Unlock()
 			}
 		}()
 	}
 
+	var fetchErr error
 	for {
 		msg, err := a.kafkaReader.FetchMessage(ctx)
 		if err != nil {
-			log.Printf("could not fetch message: %v", err)
-			close(jobs)
-			wg.Wait()
-			return err
+			if ctx.Err() == nil {
+				log.Printf("could not fetch message: %v", err)
+				fetchErr = err
+			}
+			break
+		}
+
+		committer.Track(msg)
+		select {
+		case jobs <- msg:
+		case <-ctx.Done():
+		}
+	}
+	close(jobs)
+
+	log.Println("Shutting down, waiting for submissions in progress...")
+	drained := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(drained)
+	}()
+	select {
+	case <-drained:
+	case <-time.After(time.Duration(a.cfg.ShutdownTimeoutSeconds) * time.Second):
+		log.Println("Shutdown timeout reached, aborting submissions in progress")
+		cancelJobs()
+		<-drained
+	}
+
+	a.grpcServer.GracefulStop()
+	return fetchErr
+}
+
+func (a *App) consumeProblemEvents(ctx context.Context) {
+	for {
+		msg, err := a.problemReader.ReadMessage(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Printf("could not read problem event: %v", err)
+			}
+			return
 		}
+		a.problemHandler.ProcessMessage(ctx, msg)
+	}
+}
 
-		jobs <- msg
+// close flushes pending offsets and results and removes the workers.
+func (a *App) close() {
+	if err := a.kafkaReader.Close(); err != nil {
+		log.Printf("Failed to close Kafka reader: %v", err)
+	}
+	if err := a.problemReader.Close(); err != nil {
+		log.Printf("Failed to close problem events reader: %v", err)
+	}
+	if err := a.kafkaProducer.Close(); err != nil {
+		log.Printf("Failed to close Kafka producer: %v", err)
+	}
+	if err := a.progressProducer.Close(); err != nil {
+		log.Printf("Failed to close Kafka progress producer: %v", err)
+	}
+	if err := a.submissionProducer.Close(); err != nil {
+		log.Printf("Failed to close Kafka submission producer: %v", err)
+	}
+	if err := a.deadLetters.Close(); err != nil {
+		log.Printf("Failed to close dead-letter queue: %v", err)
+	}
+	if err := a.sandbox.Close(); err != nil {
+		log.Printf("Failed to remove workers: %v", err)
 	}
+	log.Println("Judge service stopped")
 }
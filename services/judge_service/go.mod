@@ -1,13 +1,15 @@
 module github.com/DeadlyParkour777/code-checker/services/judge_service
 
-go 1.24.4
+go 1.25.0
 
 require (
 	github.com/DeadlyParkour777/code-checker/pkg v0.0.0-20251229162914-780baa59fdc6
 	github.com/docker/docker v28.3.3+incompatible
 	github.com/google/uuid v1.6.0
 	github.com/segmentio/kafka-go v0.4.49
+	github.com/tetratelabs/wazero v1.12.0
 	google.golang.org/grpc v1.77.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -47,7 +49,7 @@ require (
 	go.opentelemetry.io/otel/sdk/metric v1.39.0 // indirect
 	go.opentelemetry.io/otel/trace v1.39.0 // indirect
 	golang.org/x/net v0.47.0 // indirect
-	golang.org/x/sys v0.39.0 // indirect
+	golang.org/x/sys v0.44.0 // indirect
 	golang.org/x/text v0.31.0 // indirect
 	golang.org/x/time v0.14.0 // indirect
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20251202230838-ff82c1b0f217 // indirect
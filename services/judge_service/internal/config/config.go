@@ -7,14 +7,34 @@ import (
 )
 
 type Config struct {
+	GRPCPort                string
 	KafkaBrokers            []string
 	SubmissionTopic         string
 	ResultTopic             string
+	DeadLetterTopic         string
+	ProblemEventsTopic      string
 	GroupID                 string
 	ExecutionTimeoutSeconds int
-	HostTempPath            string
+	MemoryLimitMB           int
+	CompileMemoryLimitMB    int
+	OutputLimitKB           int
 	ProblemServiceAddr      string
 	WorkerCount             int
+	LanguagesFile           string
+	SandboxPidsLimit        int
+	SandboxRunUser          string
+	SandboxBuildUser        string
+	SandboxReadOnlyRootfs   bool
+	SandboxFileSizeLimitMB  int
+	SandboxSeccompProfile   string
+	SandboxBackend          string
+	LocalSandboxDir         string
+	LocalSandboxCgroup      string
+	WasmSandboxDir          string
+	ShutdownTimeoutSeconds  int
+	MaxAttempts             int
+	RetryBackoffMs          int
+	TestCacheMB             int
 }
 
 func ConfigInit() Config {
@@ -23,20 +43,50 @@ func ConfigInit() Config {
 	brokersStr := getEnv("KAFKA_BROKERS", "localhost:9092")
 
 	timeout, _ := strconv.Atoi(getEnv("EXECUTION_TIMEOUT_SECONDS", "2"))
+	memoryLimit, _ := strconv.Atoi(getEnv("MEMORY_LIMIT_MB", "128"))
+	compileMemoryLimit, _ := strconv.Atoi(getEnv("COMPILE_MEMORY_LIMIT_MB", "512"))
+	outputLimit, _ := strconv.Atoi(getEnv("OUTPUT_LIMIT_KB", "16384"))
 	workerCount, _ := strconv.Atoi(getEnv("WORKER_COUNT", "4"))
 	if workerCount <= 0 {
 		workerCount = 1
 	}
+	pidsLimit, _ := strconv.Atoi(getEnv("SANDBOX_PIDS_LIMIT", "256"))
+	fileSizeLimit, _ := strconv.Atoi(getEnv("SANDBOX_FILE_SIZE_LIMIT_MB", "64"))
+	readOnlyRootfs, _ := strconv.ParseBool(getEnv("SANDBOX_READ_ONLY_ROOTFS", "true"))
+	shutdownTimeout, _ := strconv.Atoi(getEnv("SHUTDOWN_TIMEOUT_SECONDS", "50"))
+	maxAttempts, _ := strconv.Atoi(getEnv("MAX_ATTEMPTS", "3"))
+	retryBackoff, _ := strconv.Atoi(getEnv("RETRY_BACKOFF_MS", "1000"))
+	testCache, _ := strconv.Atoi(getEnv("TEST_CACHE_MB", "256"))
 
 	return Config{
+		GRPCPort:                getEnv("GRPC_PORT", "8005"),
 		KafkaBrokers:            strings.Split(brokersStr, ","),
 		SubmissionTopic:         getEnv("SUBMISSION_TOPIC", "submissions"),
 		ResultTopic:             getEnv("RESULT_TOPIC", "results"),
+		DeadLetterTopic:         getEnv("DEAD_LETTER_TOPIC", "submissions_dlq"),
+		ProblemEventsTopic:      getEnv("PROBLEM_EVENTS_TOPIC", "problem_events"),
 		GroupID:                 getEnv("GROUP_ID", "judge-group"),
 		ExecutionTimeoutSeconds: timeout,
-		HostTempPath:            getEnv("HOST_TEMP_PATH", "/tmp/submissions"),
+		MemoryLimitMB:           memoryLimit,
+		CompileMemoryLimitMB:    compileMemoryLimit,
+		OutputLimitKB:           outputLimit,
 		ProblemServiceAddr:      getEnv("PROBLEM_SERVICE_ADDR", "problem-service:8002"),
 		WorkerCount:             workerCount,
+		LanguagesFile:           getEnv("LANGUAGES_FILE", ""),
+		SandboxPidsLimit:        pidsLimit,
+		SandboxRunUser:          getEnv("SANDBOX_RUN_USER", "65534:65534"),
+		SandboxBuildUser:        getEnv("SANDBOX_BUILD_USER", "65533:65533"),
+		SandboxReadOnlyRootfs:   readOnlyRootfs,
+		SandboxFileSizeLimitMB:  fileSizeLimit,
+		SandboxSeccompProfile:   getEnv("SANDBOX_SECCOMP_PROFILE", ""),
+		SandboxBackend:          getEnv("SANDBOX_BACKEND", "docker"),
+		LocalSandboxDir:         getEnv("LOCAL_SANDBOX_DIR", "/var/lib/code-checker/sandbox"),
+		LocalSandboxCgroup:      getEnv("LOCAL_SANDBOX_CGROUP", "/sys/fs/cgroup/code-checker-judge"),
+		WasmSandboxDir:          getEnv("WASM_SANDBOX_DIR", ""),
+		ShutdownTimeoutSeconds:  shutdownTimeout,
+		MaxAttempts:             maxAttempts,
+		RetryBackoffMs:          retryBackoff,
+		TestCacheMB:             testCache,
 	}
 }
 
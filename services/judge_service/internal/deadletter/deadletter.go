@@ -0,0 +1,241 @@
+package deadletter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+const (
+	headerError           = "error"
+	headerAttempts        = "attempts"
+	headerFailedAt        = "failed-at"
+	headerSourceTopic     = "source-topic"
+	headerSourcePartition = "source-partition"
+	headerSourceOffset    = "source-offset"
+	headerReplayed        = "replayed"
+)
+
+var (
+	ErrNotFound        = errors.New("dead letter not found")
+	ErrAlreadyReplayed = errors.New("dead letter already replayed")
+)
+
+// Letter is a submission message the judge gave up on. Offset is its
+// position in the dead-letter topic and identifies it for replay.
+type Letter struct {
+	Offset          int64
+	Key             []byte
+	Value           []byte
+	Error           string
+	Attempts        int
+	FailedAt        time.Time
+	SourceTopic     string
+	SourcePartition int
+	SourceOffset    int64
+	Replayed        bool
+}
+
+// Queue keeps dead letters in a Kafka topic. The topic is expected to have a
+// single partition, so an offset is enough to find a letter again. A topic
+// cannot drop a single message, so a replayed letter is marked by a message
+// after it that names its offset.
+type Queue interface {
+	Push(ctx context.Context, msg kafka.Message, cause error, attempts int) error
+	List(ctx context.Context, limit int) ([]Letter, error)
+	Get(ctx context.Context, offset int64) (*Letter, error)
+	MarkReplayed(ctx context.Context, offset int64) error
+	Close() error
+}
+
+type kafkaQueue struct {
+	brokers []string
+	topic   string
+	writer  *kafka.Writer
+}
+
+func NewKafkaQueue(brokers []string, topic string) Queue {
+	return &kafkaQueue{
+		brokers: brokers,
+		topic:   topic,
+		writer: &kafka.Writer{
+			Addr:         kafka.TCP(brokers...),
+			Topic:        topic,
+			RequiredAcks: kafka.RequireAll,
+			MaxAttempts:  10,
+		},
+	}
+}
+
+func (q *kafkaQueue) Push(ctx context.Context, msg kafka.Message, cause error, attempts int) error {
+	letter := kafka.Message{
+		Key:   msg.Key,
+		Value: msg.Value,
+		Headers: []kafka.Header{
+			{Key: headerError, Value: []byte(cause.Error())},
+			{Key: headerAttempts, Value: []byte(strconv.Itoa(attempts))},
+			{Key: headerFailedAt, Value: []byte(time.Now().UTC().Format(time.RFC3339))},
+			{Key: headerSourceTopic, Value: []byte(msg.Topic)},
+			{Key: headerSourcePartition, Value: []byte(strconv.Itoa(msg.Partition))},
+			{Key: headerSourceOffset, Value: []byte(strconv.FormatInt(msg.Offset, 10))},
+		},
+	}
+	if err := q.writer.WriteMessages(ctx, letter); err != nil {
+		return fmt.Errorf("failed to write dead letter: %w", err)
+	}
+	return nil
+}
+
+func (q *kafkaQueue) MarkReplayed(ctx context.Context, offset int64) error {
+	marker := kafka.Message{
+		Headers: []kafka.Header{{Key: headerReplayed, Value: []byte(strconv.FormatInt(offset, 10))}},
+	}
+	if err := q.writer.WriteMessages(ctx, marker); err != nil {
+		return fmt.Errorf("failed to mark dead letter %d replayed: %w", offset, err)
+	}
+	return nil
+}
+
+// List returns the letters among the limit most recent messages, oldest
+// first.
+func (q *kafkaQueue) List(ctx context.Context, limit int) ([]Letter, error) {
+	first, last, err := q.offsets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	start := max(first, last-int64(limit))
+	if start >= last {
+		return nil, nil
+	}
+
+	reader, err := q.readerAt(start)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	letters := make([]Letter, 0, last-start)
+	replayed := make(map[int64]bool)
+	for offset := start; offset < last; offset++ {
+		msg, err := reader.ReadMessage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read dead letter: %w", err)
+		}
+		if of, ok := replayedOffset(msg); ok {
+			replayed[of] = true
+			continue
+		}
+		letters = append(letters, toLetter(msg))
+	}
+	for i := range letters {
+		letters[i].Replayed = replayed[letters[i].Offset]
+	}
+	return letters, nil
+}
+
+func (q *kafkaQueue) Get(ctx context.Context, offset int64) (*Letter, error) {
+	first, last, err := q.offsets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if offset < first || offset >= last {
+		return nil, ErrNotFound
+	}
+
+	reader, err := q.readerAt(offset)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	msg, err := reader.ReadMessage(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dead letter: %w", err)
+	}
+	if _, ok := replayedOffset(msg); ok {
+		return nil, ErrNotFound
+	}
+	letter := toLetter(msg)
+
+	// A replay marker can only come after the letter.
+	for next := offset + 1; next < last && !letter.Replayed; next++ {
+		msg, err := reader.ReadMessage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read dead letter: %w", err)
+		}
+		if of, ok := replayedOffset(msg); ok && of == offset {
+			letter.Replayed = true
+		}
+	}
+	return &letter, nil
+}
+
+func (q *kafkaQueue) Close() error {
+	return q.writer.Close()
+}
+
+func (q *kafkaQueue) offsets(ctx context.Context) (int64, int64, error) {
+	conn, err := kafka.DialLeader(ctx, "tcp", q.brokers[0], q.topic, 0)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to connect to dead-letter topic: %w", err)
+	}
+	defer conn.Close()
+
+	first, last, err := conn.ReadOffsets()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to read dead-letter offsets: %w", err)
+	}
+	return first, last, nil
+}
+
+func (q *kafkaQueue) readerAt(offset int64) (*kafka.Reader, error) {
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:   q.brokers,
+		Topic:     q.topic,
+		Partition: 0,
+		MaxBytes:  10e6,
+	})
+	if err := reader.SetOffset(offset); err != nil {
+		reader.Close()
+		return nil, fmt.Errorf("failed to seek dead-letter topic: %w", err)
+	}
+	return reader, nil
+}
+
+// replayedOffset tells a replay marker from a letter and returns the offset
+// of the letter it marks.
+func replayedOffset(msg kafka.Message) (int64, bool) {
+	for _, h := range msg.Headers {
+		if h.Key == headerReplayed {
+			offset, err := strconv.ParseInt(string(h.Value), 10, 64)
+			return offset, err == nil
+		}
+	}
+	return 0, false
+}
+
+func toLetter(msg kafka.Message) Letter {
+	letter := Letter{Offset: msg.Offset, Key: msg.Key, Value: msg.Value}
+	for _, h := range msg.Headers {
+		value := string(h.Value)
+		switch h.Key {
+		case headerError:
+			letter.Error = value
+		case headerAttempts:
+			letter.Attempts, _ = strconv.Atoi(value)
+		case headerFailedAt:
+			letter.FailedAt, _ = time.Parse(time.RFC3339, value)
+		case headerSourceTopic:
+			letter.SourceTopic = value
+		case headerSourcePartition:
+			letter.SourcePartition, _ = strconv.Atoi(value)
+		case headerSourceOffset:
+			letter.SourceOffset, _ = strconv.ParseInt(value, 10, 64)
+		}
+	}
+	return letter
+}
@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"context"
+	"sync"
+
+	"github.com/segmentio/kafka-go"
+)
+
+type messageCommitter interface {
+	CommitMessages(ctx context.Context, msgs ...kafka.Message) error
+}
+
+// Committer commits messages that are processed concurrently. Committing an
+// offset commits every earlier one of its partition as well, so only the
+// highest offset below which every fetched message is done gets committed.
+// A message that is never done (aborted, or failed without being
+// dead-lettered) holds back the ones after it, and all of them are processed
+// again after a restart.
+type Committer struct {
+	reader messageCommitter
+
+	mu         sync.Mutex
+	partitions map[int]*partitionOffsets
+}
+
+type partitionOffsets struct {
+	inFlight []int64
+	done     map[int64]kafka.Message
+}
+
+func NewCommitter(reader messageCommitter) *Committer {
+	return &Committer{reader: reader, partitions: make(map[int]*partitionOffsets)}
+}
+
+// Track registers a fetched message; messages must be tracked in the order
+// they were fetched.
+func (c *Committer) Track(msg kafka.Message) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	p, ok := c.partitions[msg.Partition]
+	if !ok {
+		p = &partitionOffsets{done: make(map[int64]kafka.Message)}
+		c.partitions[msg.Partition] = p
+	}
+	p.inFlight = append(p.inFlight, msg.Offset)
+}
+
+// Done marks a tracked message as processed and commits what has become
+// contiguous.
+func (c *Committer) Done(ctx context.Context, msg kafka.Message) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	p, ok := c.partitions[msg.Partition]
+	if !ok {
+		return nil
+	}
+	p.done[msg.Offset] = msg
+
+	var last *kafka.Message
+	for len(p.inFlight) > 0 {
+		m, ok := p.done[p.inFlight[0]]
+		if !ok {
+			break
+		}
+		delete(p.done, p.inFlight[0])
+		p.inFlight = p.inFlight[1:]
+		last = &m
+	}
+	if last == nil {
+		return nil
+	}
+	return c.reader.CommitMessages(ctx, *last)
+}
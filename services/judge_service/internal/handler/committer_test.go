@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DeadlyParkour777/code-checker/services/judge_service/internal/service"
+	"github.com/DeadlyParkour777/code-checker/services/judge_service/internal/types"
+	"github.com/segmentio/kafka-go"
+)
+
+type fakeCommitter struct {
+	mu      sync.Mutex
+	offsets []int64
+}
+
+func (c *fakeCommitter) CommitMessages(ctx context.Context, msgs ...kafka.Message) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, msg := range msgs {
+		c.offsets = append(c.offsets, msg.Offset)
+	}
+	return nil
+}
+
+func (c *fakeCommitter) committed() []int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]int64(nil), c.offsets...)
+}
+
+// slowService judges submission "slow" until its context is cancelled and
+// every other submission at once.
+type slowService struct {
+	service.Service
+	started chan struct{}
+}
+
+func (s *slowService) ProcessSubmission(ctx context.Context, submission *types.SubmissionEvent) error {
+	if submission.SubmissionID != "slow" {
+		return nil
+	}
+	close(s.started)
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestCommitterWaitsForEarlierOffsets(t *testing.T) {
+	reader := &fakeCommitter{}
+	c := NewCommitter(reader)
+	msgs := []kafka.Message{{Offset: 10}, {Offset: 11}, {Offset: 12}, {Partition: 1, Offset: 5}}
+	for _, msg := range msgs {
+		c.Track(msg)
+	}
+
+	c.Done(context.Background(), msgs[2])
+	c.Done(context.Background(), msgs[3])
+	c.Done(context.Background(), msgs[1])
+	if got := reader.committed(); len(got) != 1 || got[0] != 5 {
+		t.Fatalf("expected only the other partition to be committed, got %v", got)
+	}
+
+	c.Done(context.Background(), msgs[0])
+	if got := reader.committed(); len(got) != 2 || got[1] != 12 {
+		t.Fatalf("expected offset 12 to be committed, got %v", got)
+	}
+}
+
+func TestAbortedJobIsNotCommittedByALaterOne(t *testing.T) {
+	svc := &slowService{started: make(chan struct{})}
+	h := NewKafkaConsumer(svc, RetryPolicy{MaxAttempts: 3})
+	reader := &fakeCommitter{}
+	c := NewCommitter(reader)
+
+	slow := kafka.Message{Offset: 1, Value: []byte(`{"submission_id": "slow"}`)}
+	fast := kafka.Message{Offset: 2, Value: []byte(`{"submission_id": "fast"}`)}
+	c.Track(slow)
+	c.Track(fast)
+
+	jobCtx, cancelJobs := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	for _, msg := range []kafka.Message{slow, fast} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := h.ProcessMessage(jobCtx, msg); err == nil {
+				c.Done(context.Background(), msg)
+			}
+		}()
+	}
+
+	// Abort the slow job only once the fast one has finished.
+	fastDone := func() bool {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		_, ok := c.partitions[0].done[fast.Offset]
+		return ok
+	}
+	<-svc.started
+	for deadline := time.Now().Add(time.Second); !fastDone(); {
+		if time.Now().After(deadline) {
+			t.Fatalf("the fast job did not finish")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	cancelJobs()
+	wg.Wait()
+
+	if got := reader.committed(); len(got) != 0 {
+		t.Fatalf("expected nothing to be committed while offset 1 is unfinished, got %v", got)
+	}
+}
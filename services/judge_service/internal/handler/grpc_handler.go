@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+
+	judgepb "github.com/DeadlyParkour777/code-checker/pkg/judge"
+	"github.com/DeadlyParkour777/code-checker/services/judge_service/internal/deadletter"
+	"github.com/DeadlyParkour777/code-checker/services/judge_service/internal/service"
+	"github.com/DeadlyParkour777/code-checker/services/judge_service/internal/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const defaultDeadLettersLimit = 50
+
+type GrpcHandler struct {
+	judgepb.UnimplementedJudgeServiceServer
+	service service.Service
+}
+
+func NewGrpcHandler(svc service.Service) *GrpcHandler {
+	return &GrpcHandler{service: svc}
+}
+
+func (h *GrpcHandler) ListLanguages(ctx context.Context, req *judgepb.ListLanguagesRequest) (*judgepb.ListLanguagesResponse, error) {
+	var pbLanguages []*judgepb.Language
+	for _, lang := range h.service.ListLanguages(ctx) {
+		pbLanguages = append(pbLanguages, &judgepb.Language{
+			Id:             lang.ID,
+			Name:           lang.Name,
+			Version:        lang.Version,
+			SourceFile:     lang.SourceFile,
+			TimeMultiplier: lang.TimeMultiplier,
+			Compiled:       lang.Compile != "",
+		})
+	}
+
+	return &judgepb.ListLanguagesResponse{Languages: pbLanguages}, nil
+}
+
+func (h *GrpcHandler) ListDeadLetters(ctx context.Context, req *judgepb.ListDeadLettersRequest) (*judgepb.ListDeadLettersResponse, error) {
+	limit := int(req.GetLimit())
+	if limit <= 0 {
+		limit = defaultDeadLettersLimit
+	}
+
+	letters, err := h.service.ListDeadLetters(ctx, limit)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list dead letters: %v", err)
+	}
+
+	pbLetters := make([]*judgepb.DeadLetter, len(letters))
+	for i := range letters {
+		pbLetters[i] = toDeadLetterPB(&letters[i])
+	}
+	return &judgepb.ListDeadLettersResponse{DeadLetters: pbLetters}, nil
+}
+
+func (h *GrpcHandler) ReplayDeadLetter(ctx context.Context, req *judgepb.ReplayDeadLetterRequest) (*judgepb.DeadLetter, error) {
+	letter, err := h.service.ReplayDeadLetter(ctx, req.GetOffset())
+	if errors.Is(err, deadletter.ErrNotFound) {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	if errors.Is(err, deadletter.ErrAlreadyReplayed) {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to replay dead letter: %v", err)
+	}
+	return toDeadLetterPB(letter), nil
+}
+
+// toDeadLetterPB still lists a letter whose payload is not a submission, with
+// the reason in payload_error, so it can be looked at.
+func toDeadLetterPB(letter *deadletter.Letter) *judgepb.DeadLetter {
+	var submission types.SubmissionEvent
+	var payloadError string
+	if err := json.Unmarshal(letter.Value, &submission); err != nil {
+		log.Printf("Dead letter %d is not a submission: %v", letter.Offset, err)
+		payloadError = err.Error()
+	}
+
+	return &judgepb.DeadLetter{
+		Offset:          letter.Offset,
+		SubmissionId:    submission.SubmissionID,
+		Error:           letter.Error,
+		Attempts:        int32(letter.Attempts),
+		FailedAt:        letter.FailedAt.Format(time.RFC3339),
+		SourceTopic:     letter.SourceTopic,
+		SourcePartition: int32(letter.SourcePartition),
+		SourceOffset:    letter.SourceOffset,
+		Payload:         string(letter.Value),
+		Replayed:        letter.Replayed,
+		PayloadError:    payloadError,
+	}
+}
@@ -0,0 +1,19 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/DeadlyParkour777/code-checker/services/judge_service/internal/deadletter"
+)
+
+func TestToDeadLetterPB(t *testing.T) {
+	letter := toDeadLetterPB(&deadletter.Letter{Offset: 3, Value: []byte(`{"submission_id": "s1"}`), Replayed: true})
+	if letter.GetSubmissionId() != "s1" || letter.GetPayloadError() != "" || !letter.GetReplayed() {
+		t.Fatalf("unexpected dead letter: %+v", letter)
+	}
+
+	corrupt := toDeadLetterPB(&deadletter.Letter{Offset: 4, Value: []byte(`not json`)})
+	if corrupt.GetSubmissionId() != "" || corrupt.GetPayloadError() == "" || corrupt.GetPayload() != "not json" {
+		t.Fatalf("expected the corrupt letter to be marked: %+v", corrupt)
+	}
+}
@@ -3,27 +3,62 @@ package handler
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"log"
+	"time"
 
 	"github.com/DeadlyParkour777/code-checker/services/judge_service/internal/service"
 	"github.com/DeadlyParkour777/code-checker/services/judge_service/internal/types"
 	"github.com/segmentio/kafka-go"
 )
 
+// RetryPolicy bounds how often a submission is judged again after a system
+// failure. The wait doubles after every attempt, starting at Backoff.
+type RetryPolicy struct {
+	MaxAttempts int
+	Backoff     time.Duration
+}
+
 type KafkaConsumer struct {
 	service service.Service
+	retry   RetryPolicy
 }
 
-func NewKafkaConsumer(svc service.Service) *KafkaConsumer {
-	return &KafkaConsumer{service: svc}
+func NewKafkaConsumer(svc service.Service, retry RetryPolicy) *KafkaConsumer {
+	if retry.MaxAttempts < 1 {
+		retry.MaxAttempts = 1
+	}
+	return &KafkaConsumer{service: svc, retry: retry}
 }
 
+// ProcessMessage returns nil once the message is done with, whether judged or
+// dead-lettered, so it can be committed. An error means it must not be.
 func (h *KafkaConsumer) ProcessMessage(ctx context.Context, msg kafka.Message) error {
 	var submission types.SubmissionEvent
 	if err := json.Unmarshal(msg.Value, &submission); err != nil {
-		log.Printf("Failed to unmarshal submission, skipping message: %v", err)
-		return nil
+		log.Printf("Failed to unmarshal submission, dead-lettering message: %v", err)
+		return h.service.DeadLetter(ctx, msg, nil, fmt.Errorf("invalid submission: %w", err), 1)
+	}
+
+	var err error
+	backoff := h.retry.Backoff
+	for attempt := 1; ; attempt++ {
+		err = h.service.ProcessSubmission(ctx, &submission)
+		if err == nil || ctx.Err() != nil {
+			return err
+		}
+		if attempt == h.retry.MaxAttempts {
+			break
+		}
+
+		log.Printf("Attempt %d for submission %s failed, retrying in %s: %v", attempt, submission.SubmissionID, backoff, err)
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		backoff *= 2
 	}
 
-	return h.service.ProcessSubmission(ctx, &submission)
+	return h.service.DeadLetter(ctx, msg, &submission, err, h.retry.MaxAttempts)
 }
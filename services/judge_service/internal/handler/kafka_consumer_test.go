@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DeadlyParkour777/code-checker/services/judge_service/internal/service"
+	"github.com/DeadlyParkour777/code-checker/services/judge_service/internal/types"
+	"github.com/segmentio/kafka-go"
+)
+
+// flakyService fails the first failures attempts to judge a submission.
+type flakyService struct {
+	service.Service
+	failures    int
+	attempts    int
+	deadLetters []*types.SubmissionEvent
+}
+
+func (s *flakyService) ProcessSubmission(ctx context.Context, submission *types.SubmissionEvent) error {
+	s.attempts++
+	if s.attempts <= s.failures {
+		return errors.New("problem service unavailable")
+	}
+	return nil
+}
+
+func (s *flakyService) DeadLetter(ctx context.Context, msg kafka.Message, submission *types.SubmissionEvent, cause error, attempts int) error {
+	s.deadLetters = append(s.deadLetters, submission)
+	return nil
+}
+
+func TestProcessMessageRetries(t *testing.T) {
+	tests := []struct {
+		name           string
+		value          string
+		failures       int
+		wantAttempts   int
+		wantDeadLetter bool
+	}{
+		{name: "recovers after a retry", value: `{"submission_id": "s1"}`, failures: 2, wantAttempts: 3},
+		{name: "gives up after max attempts", value: `{"submission_id": "s1"}`, failures: 5, wantAttempts: 3, wantDeadLetter: true},
+		{name: "poison message is not retried", value: `not json`, wantAttempts: 0, wantDeadLetter: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &flakyService{failures: tt.failures}
+			h := NewKafkaConsumer(svc, RetryPolicy{MaxAttempts: 3})
+
+			if err := h.ProcessMessage(context.Background(), kafka.Message{Value: []byte(tt.value)}); err != nil {
+				t.Fatalf("expected the message to be done with, got %v", err)
+			}
+			if svc.attempts != tt.wantAttempts || (len(svc.deadLetters) == 1) != tt.wantDeadLetter {
+				t.Fatalf("unexpected attempts %d, dead letters %d", svc.attempts, len(svc.deadLetters))
+			}
+		})
+	}
+}
+
+func TestProcessMessageAbortedIsNotDone(t *testing.T) {
+	svc := &flakyService{failures: 5}
+	h := NewKafkaConsumer(svc, RetryPolicy{MaxAttempts: 3})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := h.ProcessMessage(ctx, kafka.Message{Value: []byte(`{"submission_id": "s1"}`)}); err == nil {
+		t.Fatalf("expected an aborted message to stay uncommitted")
+	}
+	if len(svc.deadLetters) != 0 {
+		t.Fatalf("aborted message must not be dead-lettered")
+	}
+}
@@ -0,0 +1,30 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"github.com/DeadlyParkour777/code-checker/services/judge_service/internal/service"
+	"github.com/DeadlyParkour777/code-checker/services/judge_service/internal/types"
+	"github.com/segmentio/kafka-go"
+)
+
+// ProblemConsumer applies problem events to the local test cache. Every judge
+// reads all of them, so it does not join a consumer group.
+type ProblemConsumer struct {
+	service service.Service
+}
+
+func NewProblemConsumer(svc service.Service) *ProblemConsumer {
+	return &ProblemConsumer{service: svc}
+}
+
+func (h *ProblemConsumer) ProcessMessage(ctx context.Context, msg kafka.Message) {
+	var event types.ProblemEvent
+	if err := json.Unmarshal(msg.Value, &event); err != nil {
+		log.Printf("Failed to unmarshal problem event: %v", err)
+		return
+	}
+	h.service.HandleProblemEvent(ctx, &event)
+}
@@ -0,0 +1,101 @@
+package languages
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed languages.yaml
+var defaultRegistry []byte
+
+type Language struct {
+	ID             string  `yaml:"id"`
+	Name           string  `yaml:"name"`
+	Version        string  `yaml:"version"`
+	SourceFile     string  `yaml:"source_file"`
+	Compile        string  `yaml:"compile"`
+	Run            string  `yaml:"run"`
+	Image          string  `yaml:"image"`
+	TimeMultiplier float64 `yaml:"time_multiplier"`
+	// WasmCompile builds the source to a WASI module at $BIN for the wasm
+	// sandbox; languages without it are not offered there.
+	WasmCompile string `yaml:"wasm_compile"`
+	// MemoryOverheadMB is added to the worker's memory limit on top of the
+	// problem limit, which is passed to the run command as $MEMORY_LIMIT_MB.
+	MemoryOverheadMB int `yaml:"memory_overhead_mb"`
+}
+
+type Registry struct {
+	languages []Language
+	byID      map[string]Language
+}
+
+// Load reads the registry from a YAML or JSON file, falling back to the
+// embedded default when path is empty.
+func Load(path string) (*Registry, error) {
+	if path == "" {
+		return Parse(defaultRegistry)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read languages file: %w", err)
+	}
+	return Parse(data)
+}
+
+func Parse(data []byte) (*Registry, error) {
+	var langs []Language
+	if err := yaml.Unmarshal(data, &langs); err != nil {
+		return nil, fmt.Errorf("failed to parse languages: %w", err)
+	}
+	if len(langs) == 0 {
+		return nil, fmt.Errorf("no languages configured")
+	}
+
+	registry := &Registry{byID: make(map[string]Language, len(langs))}
+	for _, lang := range langs {
+		if lang.ID == "" || lang.SourceFile == "" || lang.Run == "" {
+			return nil, fmt.Errorf("language %q: id, source_file and run are required", lang.ID)
+		}
+		if _, exists := registry.byID[lang.ID]; exists {
+			return nil, fmt.Errorf("language %q is defined twice", lang.ID)
+		}
+		if lang.TimeMultiplier <= 0 {
+			lang.TimeMultiplier = 1
+		}
+		if lang.MemoryOverheadMB < 0 {
+			return nil, fmt.Errorf("language %q: memory_overhead_mb must not be negative", lang.ID)
+		}
+		if lang.Name == "" {
+			lang.Name = lang.ID
+		}
+		registry.languages = append(registry.languages, lang)
+		registry.byID[lang.ID] = lang
+	}
+	return registry, nil
+}
+
+func (r *Registry) Get(id string) (Language, bool) {
+	lang, ok := r.byID[id]
+	return lang, ok
+}
+
+// Filter returns a registry with only the languages keep accepts.
+func (r *Registry) Filter(keep func(Language) bool) *Registry {
+	filtered := &Registry{byID: make(map[string]Language)}
+	for _, lang := range r.languages {
+		if keep(lang) {
+			filtered.languages = append(filtered.languages, lang)
+			filtered.byID[lang.ID] = lang
+		}
+	}
+	return filtered
+}
+
+func (r *Registry) List() []Language {
+	return append([]Language(nil), r.languages...)
+}
@@ -0,0 +1,75 @@
+package languages
+
+import "testing"
+
+func TestLoadDefault(t *testing.T) {
+	registry, err := Load("")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, id := range []string{"go", "python", "c", "cpp17", "cpp20", "java", "kotlin"} {
+		lang, ok := registry.Get(id)
+		if !ok {
+			t.Fatalf("expected %s in default registry", id)
+		}
+		if lang.Run == "" || lang.SourceFile == "" {
+			t.Fatalf("incomplete language %s: %+v", id, lang)
+		}
+	}
+}
+
+func TestParseJSON(t *testing.T) {
+	registry, err := Parse([]byte(`[{"id": "lua", "source_file": "main.lua", "run": "exec lua \"$SRC\""}]`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lang, ok := registry.Get("lua")
+	if !ok {
+		t.Fatalf("expected lua in registry")
+	}
+	if lang.TimeMultiplier != 1 || lang.Name != "lua" {
+		t.Fatalf("defaults not applied: %+v", lang)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	tests := map[string]string{
+		"empty":             `[]`,
+		"missing run":       `[{"id": "c", "source_file": "main.c"}]`,
+		"duplicate id":      `[{"id": "c", "source_file": "a.c", "run": "x"}, {"id": "c", "source_file": "b.c", "run": "y"}]`,
+		"not a list":        `{"id": "c"}`,
+		"negative overhead": `[{"id": "java", "source_file": "Main.java", "run": "x", "memory_overhead_mb": -1}]`,
+	}
+
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Parse([]byte(data)); err == nil {
+				t.Fatalf("expected error")
+			}
+		})
+	}
+}
+
+func TestFilterWasm(t *testing.T) {
+	registry, err := Load("")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	wasm := registry.Filter(func(lang Language) bool { return lang.WasmCompile != "" })
+	var ids []string
+	for _, lang := range wasm.List() {
+		ids = append(ids, lang.ID)
+	}
+	if len(ids) != 1 || ids[0] != "go" {
+		t.Fatalf("unexpected wasm languages: %v", ids)
+	}
+	if _, ok := wasm.Get("python"); ok {
+		t.Fatalf("python has no wasm build")
+	}
+	if _, ok := wasm.Get("cpp17"); ok {
+		t.Fatalf("C++ must not be built on the judge host")
+	}
+}
@@ -0,0 +1,494 @@
+package sandbox
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	_ "embed"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/pkg/archive"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/google/uuid"
+)
+
+const jvmRuntimeImage = "code-checker-judge-runtime-jvm:latest"
+const workerLabelKey = "code-checker.worker"
+const workerLabelValue = "judge"
+const runtimeLabelKey = "code-checker.runtime.sha256"
+
+// The judge-runner kill phase signals every process except PID 1, so the
+// worker keeps restarting its sleep instead of exiting when it goes away.
+const workerCommand = "trap 'exit 0' TERM INT; while :; do sleep infinity & wait; done"
+
+// Workers share nothing with each other or the judge: the sandbox, judge and
+// /tmp directories are per-worker tmpfs mounts and everything but the
+// compiled programs is wiped after each submission.
+const tmpfsOptions = "rw,exec,nosuid,size=256m"
+
+var dockerLayout = layout{
+	sandbox: "/sandbox",
+	judge:   "/judge",
+	run:     "/judge/run",
+	cache:   cacheDir,
+	tmp:     "/tmp",
+}
+
+//go:embed runtime/Dockerfile
+var runtimeDockerfile []byte
+
+//go:embed runtime/jvm.Dockerfile
+var jvmRuntimeDockerfile []byte
+
+// runtimeImages are built locally from the embedded Dockerfiles; any other
+// image a language refers to is pulled.
+var runtimeImages = map[string][]byte{
+	DefaultImage:    runtimeDockerfile,
+	jvmRuntimeImage: jvmRuntimeDockerfile,
+}
+
+//go:embed runtime/seccomp.json
+var defaultSeccompProfile []byte
+
+// The judge-runner stays root to drop privileges, hand the sandbox to the
+// build user and kill leftovers. Submissions never run as root, so with
+// no-new-privileges they hold none of these.
+var workerCapabilities = []string{"CHOWN", "KILL", "SETGID", "SETUID"}
+
+// cacheDir keeps the build user's caches between submissions. It is a volume
+// rather than a tmpfs, which would count towards the worker's memory limit.
+const cacheDir = "/cache"
+
+type dockerBackend struct {
+	client     *client.Client
+	profile    Profile
+	hostConfig *container.HostConfig
+
+	mu      sync.Mutex
+	workers []string
+	// memory is the last memory limit set on each worker, to skip updates
+	// that would not change it.
+	memory sync.Map
+}
+
+// NewDocker runs workers as containers of the language runtime images,
+// removing any left over from a previous run.
+func NewDocker(profile Profile, memoryLimit int64) (Sandbox, error) {
+	if err := profile.validate(); err != nil {
+		return nil, err
+	}
+	hostConfig, err := profile.hostConfig(memoryLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	dockerCli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create docker client: %w", err)
+	}
+	if err := cleanupWorkerContainers(dockerCli); err != nil {
+		log.Printf("Failed to cleanup old worker containers: %v", err)
+	}
+
+	b := &dockerBackend{client: dockerCli, profile: profile, hostConfig: hostConfig}
+	return &runnerSandbox{backend: b, profile: profile}, nil
+}
+
+func (p Profile) hostConfig(memoryLimit int64) (*container.HostConfig, error) {
+	seccomp, err := p.seccomp()
+	if err != nil {
+		return nil, err
+	}
+
+	hostConfig := &container.HostConfig{
+		Resources: container.Resources{
+			Memory:     memoryLimit,
+			MemorySwap: memoryLimit,
+			NanoCPUs:   int64(0.5 * 1e9),
+		},
+		NetworkMode:    "none",
+		ReadonlyRootfs: p.ReadOnlyRootfs,
+		CapDrop:        []string{"ALL"},
+		CapAdd:         workerCapabilities,
+		SecurityOpt:    []string{"no-new-privileges", "seccomp=" + seccomp},
+		Tmpfs: map[string]string{
+			dockerLayout.sandbox: tmpfsOptions + ",mode=0755",
+			dockerLayout.judge:   tmpfsOptions + ",mode=0700",
+			dockerLayout.tmp:     tmpfsOptions + ",mode=1777",
+		},
+		Mounts: []mount.Mount{
+			{Type: mount.TypeVolume, Target: cacheDir},
+		},
+	}
+	if p.PidsLimit > 0 {
+		pidsLimit := p.PidsLimit
+		hostConfig.PidsLimit = &pidsLimit
+	}
+	if p.FileSizeLimitMB > 0 {
+		limit := p.FileSizeLimitMB * 1024 * 1024
+		hostConfig.Ulimits = []*container.Ulimit{{Name: "fsize", Soft: limit, Hard: limit}}
+	}
+	return hostConfig, nil
+}
+
+func (p Profile) seccomp() (string, error) {
+	switch p.SeccompProfile {
+	case "":
+		return string(defaultSeccompProfile), nil
+	case "unconfined":
+		return "unconfined", nil
+	}
+
+	data, err := os.ReadFile(p.SeccompProfile)
+	if err != nil {
+		return "", fmt.Errorf("failed to read seccomp profile: %w", err)
+	}
+	return string(data), nil
+}
+
+func (b *dockerBackend) layout() layout {
+	return dockerLayout
+}
+
+func (b *dockerBackend) prepare(ctx context.Context, imageName string, count int) ([]string, error) {
+	var err error
+	if dockerfile, ok := runtimeImages[imageName]; ok {
+		err = ensureRuntimeImage(b.client, imageName, dockerfile)
+	} else {
+		err = ensureImage(b.client, imageName)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to ensure image %s: %w", imageName, err)
+	}
+
+	workers := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		name := fmt.Sprintf("judge-worker-%s-%d", uuid.New().String(), i)
+		cont, err := b.client.ContainerCreate(ctx, &container.Config{
+			Image: imageName,
+			Cmd:   []string{"sh", "-c", workerCommand},
+			Labels: map[string]string{
+				workerLabelKey: workerLabelValue,
+			},
+		}, b.hostConfig, nil, nil, name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create worker container: %w", err)
+		}
+
+		if err := b.client.ContainerStart(ctx, cont.ID, container.StartOptions{}); err != nil {
+			return nil, fmt.Errorf("failed to start worker container: %w", err)
+		}
+
+		workers = append(workers, cont.ID)
+		b.mu.Lock()
+		b.workers = append(b.workers, cont.ID)
+		b.mu.Unlock()
+	}
+	return workers, nil
+}
+
+func ensureRuntimeImage(dockerCli *client.Client, imageName string, dockerfile []byte) error {
+	// Toolchains such as the JDK take a while to download on the first build.
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Minute)
+	defer cancel()
+
+	dockerfileData := normalizeLineEndings(dockerfile)
+	signature := runtimeSignature(dockerfileData)
+
+	img, _, err := dockerCli.ImageInspectWithRaw(ctx, imageName)
+	if err == nil {
+		if img.Config != nil && img.Config.Labels != nil {
+			if img.Config.Labels[runtimeLabelKey] == signature {
+				return nil
+			}
+		}
+	} else if !errdefs.IsNotFound(err) {
+		return fmt.Errorf("image inspect failed: %w", err)
+	}
+
+	tempDir, err := os.MkdirTemp("", "judge-runtime-")
+	if err != nil {
+		return fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	dockerfileWithLabel := appendRuntimeLabel(dockerfileData, signature)
+	if err := os.WriteFile(filepath.Join(tempDir, "Dockerfile"), dockerfileWithLabel, 0644); err != nil {
+		return fmt.Errorf("failed to write runtime Dockerfile: %w", err)
+	}
+
+	buildContext, err := archive.TarWithOptions(tempDir, &archive.TarOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to create tar archive: %w", err)
+	}
+	defer buildContext.Close()
+
+	resp, err := dockerCli.ImageBuild(ctx, buildContext, types.ImageBuildOptions{
+		Dockerfile:  "Dockerfile",
+		Tags:        []string{imageName},
+		Remove:      true,
+		ForceRemove: true,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to build runtime image: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if err := parseBuildErrors(resp.Body); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func ensureImage(dockerCli *client.Client, imageName string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	_, _, err := dockerCli.ImageInspectWithRaw(ctx, imageName)
+	if err == nil {
+		return nil
+	}
+	if !errdefs.IsNotFound(err) {
+		return fmt.Errorf("image inspect failed: %w", err)
+	}
+
+	log.Printf("Pulling image %s", imageName)
+	resp, err := dockerCli.ImagePull(ctx, imageName, image.PullOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to pull image: %w", err)
+	}
+	defer resp.Close()
+
+	return parseBuildErrors(resp)
+}
+
+func cleanupWorkerContainers(dockerCli *client.Client) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	args := filters.NewArgs()
+	args.Add("label", fmt.Sprintf("%s=%s", workerLabelKey, workerLabelValue))
+
+	containers, err := dockerCli.ContainerList(ctx, container.ListOptions{All: true, Filters: args})
+	if err != nil {
+		return fmt.Errorf("list worker containers: %w", err)
+	}
+
+	for _, c := range containers {
+		if err := dockerCli.ContainerRemove(ctx, c.ID, container.RemoveOptions{Force: true, RemoveVolumes: true}); err != nil {
+			return fmt.Errorf("remove worker container %s: %w", c.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// runnerCommand runs judge-runner from the script embedded in the judge, so
+// any image with a POSIX shell and coreutils can serve as a language runtime,
+// even with a read-only root filesystem.
+func runnerCommand(args ...string) []string {
+	return append([]string{"sh", "-c", runnerScript, "judge-runner"}, args...)
+}
+
+func (b *dockerBackend) exec(ctx context.Context, workerID string, args []string, stdin io.Reader, stdout io.Writer, outputLimit int64) (*Result, error) {
+	return b.execCommand(ctx, workerID, runnerCommand(args...), stdin, stdout, outputLimit)
+}
+
+func (b *dockerBackend) execCommand(
+	ctx context.Context,
+	workerID string,
+	cmd []string,
+	stdin io.Reader,
+	stdout io.Writer,
+	outputLimit int64,
+) (*Result, error) {
+	execResp, err := b.client.ContainerExecCreate(ctx, workerID, container.ExecOptions{
+		AttachStdout: true,
+		AttachStderr: true,
+		AttachStdin:  true,
+		Cmd:          cmd,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("exec create failed: %w", err)
+	}
+
+	attachResp, err := b.client.ContainerExecAttach(ctx, execResp.ID, container.ExecStartOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("exec attach failed: %w", err)
+	}
+
+	// A program may fill its output before it has read all of its input, so
+	// stdin is written while the output is read. Closing the connection on
+	// return unblocks the writer when ctx is done first; a stdin that blocks
+	// is for the caller to close.
+	go func() {
+		if stdin != nil {
+			// A program that exits without reading all of its input breaks
+			// the pipe; that is for its verdict to show, not an exec failure.
+			_, _ = io.Copy(attachResp.Conn, stdin)
+		}
+		attachResp.CloseWrite()
+	}()
+	defer attachResp.Close()
+
+	stdoutBuf := &limitedBuffer{limit: outputLimit}
+	stderrBuf := &limitedBuffer{limit: outputLimit}
+	if stdout == nil {
+		stdout = stdoutBuf
+	}
+	done := make(chan error, 1)
+	go func() {
+		_, err := stdcopy.StdCopy(stdout, stderrBuf, attachResp.Reader)
+		done <- err
+	}()
+
+	var copyErr error
+	select {
+	case <-ctx.Done():
+		b.killWorkerProcesses(workerID)
+		return nil, ctx.Err()
+	case copyErr = <-done:
+	}
+
+	// Output nobody takes any more, past the limit or to a stdout that
+	// refused it, would leave the program blocked.
+	outputExceeded := stdoutBuf.exceeded || stderrBuf.exceeded
+	if outputExceeded || copyErr != nil {
+		b.killWorkerProcesses(workerID)
+	}
+
+	for {
+		inspect, err := b.client.ContainerExecInspect(ctx, execResp.ID)
+		if err != nil {
+			return nil, fmt.Errorf("exec inspect failed: %w", err)
+		}
+		if !inspect.Running {
+			return &Result{
+				Stdout:         stdoutBuf.String(),
+				Stderr:         stderrBuf.String(),
+				ExitCode:       inspect.ExitCode,
+				OutputExceeded: outputExceeded,
+			}, nil
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+func (b *dockerBackend) killWorkerProcesses(workerID string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	execResp, err := b.client.ContainerExecCreate(ctx, workerID, container.ExecOptions{
+		Cmd: runnerCommand("--phase", "kill"),
+	})
+	if err != nil {
+		log.Printf("Failed to create kill exec in worker %s: %v", workerID, err)
+		return
+	}
+	if err := b.client.ContainerExecStart(ctx, execResp.ID, container.ExecStartOptions{}); err != nil {
+		log.Printf("Failed to kill processes in worker %s: %v", workerID, err)
+	}
+}
+
+func (b *dockerBackend) setMemory(ctx context.Context, workerID string, memoryBytes int64) error {
+	if current, ok := b.memory.Load(workerID); ok && current.(int64) == memoryBytes {
+		return nil
+	}
+	_, err := b.client.ContainerUpdate(ctx, workerID, container.UpdateConfig{
+		Resources: container.Resources{Memory: memoryBytes, MemorySwap: memoryBytes},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update worker memory limit: %w", err)
+	}
+	b.memory.Store(workerID, memoryBytes)
+	return nil
+}
+
+func (b *dockerBackend) cleanup(workerID string) error {
+	return resetWorker(b, workerID, b.profile)
+}
+
+func (b *dockerBackend) readFile(ctx context.Context, workerID, name string, limit int64) (string, error) {
+	res, err := b.execCommand(ctx, workerID, []string{"cat", name}, nil, nil, limit)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s from worker: %w", name, err)
+	}
+	if res.ExitCode != 0 {
+		return "", fmt.Errorf("failed to read %s from worker: %s", name, strings.TrimSpace(res.Stderr))
+	}
+	return res.Stdout, nil
+}
+
+func (b *dockerBackend) close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, id := range b.workers {
+		if err := b.client.ContainerRemove(ctx, id, container.RemoveOptions{Force: true, RemoveVolumes: true}); err != nil {
+			return fmt.Errorf("remove worker container %s: %w", id, err)
+		}
+	}
+	b.workers = nil
+	return b.client.Close()
+}
+
+func parseBuildErrors(r io.Reader) error {
+	dec := json.NewDecoder(r)
+	var out bytes.Buffer
+	for dec.More() {
+		var msg struct {
+			Stream string `json:"stream"`
+			Error  string `json:"error"`
+		}
+		if err := dec.Decode(&msg); err != nil {
+			return fmt.Errorf("build output parse error: %w", err)
+		}
+		if msg.Stream != "" {
+			if out.Len() < 64*1024 {
+				_, _ = out.WriteString(msg.Stream)
+			}
+		}
+		if msg.Error != "" {
+			tail := strings.TrimSpace(out.String())
+			if tail == "" {
+				return fmt.Errorf("build failed: %s", msg.Error)
+			}
+			return fmt.Errorf("build failed: %s\n%s", msg.Error, tail)
+		}
+	}
+	return nil
+}
+
+func runtimeSignature(dockerfileData []byte) string {
+	h := sha256.Sum256(dockerfileData)
+	return hex.EncodeToString(h[:])
+}
+
+func appendRuntimeLabel(dockerfileData []byte, signature string) []byte {
+	out := dockerfileData
+	if len(out) > 0 && out[len(out)-1] != '\n' {
+		out = append(out, '\n')
+	}
+	labelLine := fmt.Sprintf("LABEL %s=%s\n", runtimeLabelKey, signature)
+	out = append(out, []byte(labelLine)...)
+	return out
+}
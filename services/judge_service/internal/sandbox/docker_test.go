@@ -0,0 +1,101 @@
+package sandbox
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestProfileHostConfig(t *testing.T) {
+	profile := Profile{
+		PidsLimit:       64,
+		RunUser:         "65534:65534",
+		BuildUser:       "65533:65533",
+		ReadOnlyRootfs:  true,
+		FileSizeLimitMB: 8,
+	}
+
+	hostConfig, err := profile.hostConfig(128 * 1024 * 1024)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if hostConfig.PidsLimit == nil || *hostConfig.PidsLimit != 64 {
+		t.Fatalf("pids limit not set: %v", hostConfig.PidsLimit)
+	}
+	if !hostConfig.ReadonlyRootfs || hostConfig.NetworkMode != "none" {
+		t.Fatalf("expected read-only rootfs without network: %+v", hostConfig)
+	}
+	if !slices.Equal(hostConfig.CapDrop, []string{"ALL"}) || slices.Contains(hostConfig.CapAdd, "SYS_ADMIN") {
+		t.Fatalf("unexpected capabilities: drop %v, add %v", hostConfig.CapDrop, hostConfig.CapAdd)
+	}
+	if len(hostConfig.Ulimits) != 1 || hostConfig.Ulimits[0].Name != "fsize" || hostConfig.Ulimits[0].Hard != 8*1024*1024 {
+		t.Fatalf("unexpected ulimits: %v", hostConfig.Ulimits)
+	}
+	if !slices.Contains(hostConfig.SecurityOpt, "no-new-privileges") {
+		t.Fatalf("no-new-privileges not set: %v", hostConfig.SecurityOpt)
+	}
+
+	var seccomp struct {
+		DefaultAction string `json:"defaultAction"`
+		Syscalls      []struct {
+			Names  []string `json:"names"`
+			Action string   `json:"action"`
+		} `json:"syscalls"`
+	}
+	opt := hostConfig.SecurityOpt[1]
+	if err := json.Unmarshal([]byte(strings.TrimPrefix(opt, "seccomp=")), &seccomp); err != nil {
+		t.Fatalf("invalid seccomp profile: %v", err)
+	}
+	for _, name := range []string{"ptrace", "mount", "unshare", "setns", "bpf", "keyctl"} {
+		blocked := false
+		for _, rule := range seccomp.Syscalls {
+			if rule.Action == "SCMP_ACT_ERRNO" && slices.Contains(rule.Names, name) {
+				blocked = true
+			}
+		}
+		if !blocked {
+			t.Fatalf("seccomp profile allows %s", name)
+		}
+	}
+}
+
+func TestProfileInvalid(t *testing.T) {
+	tests := map[string]Profile{
+		"root user":        {RunUser: "0:0"},
+		"user without gid": {BuildUser: "1000"},
+		"user name":        {RunUser: "nobody:nogroup"},
+		"missing seccomp":  {SeccompProfile: filepath.Join(t.TempDir(), "missing.json")},
+	}
+
+	for name, profile := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := profile.validate(); err != nil {
+				return
+			}
+			if _, err := profile.hostConfig(0); err == nil {
+				t.Fatalf("expected error")
+			}
+		})
+	}
+}
+
+func TestProfileSeccompFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "seccomp.json")
+	if err := os.WriteFile(path, []byte(`{"defaultAction": "SCMP_ACT_ALLOW"}`), 0644); err != nil {
+		t.Fatalf("failed to write profile: %v", err)
+	}
+
+	for profile, want := range map[string]string{path: `seccomp={"defaultAction": "SCMP_ACT_ALLOW"}`, "unconfined": "seccomp=unconfined"} {
+		hostConfig, err := Profile{SeccompProfile: profile}.hostConfig(0)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !slices.Contains(hostConfig.SecurityOpt, want) {
+			t.Fatalf("security options %v, want %s", hostConfig.SecurityOpt, want)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package sandbox
+
+import (
+	"context"
+	"maps"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+)
+
+const (
+	testWorkerImage      = "code-checker-judge-test:latest"
+	testWorkerDockerfile = "FROM alpine:3.20\nRUN apk add --no-cache setpriv\n"
+	testMemoryLimit      = 256 * 1024 * 1024
+)
+
+var shellProgram = Program{Name: "shell", SourceFile: "main.sh", Run: `exec sh "$SRC"`}
+
+// forEachBackend runs test against count workers of every backend usable
+// here, skipping Docker without a daemon and the local backend without root
+// and cgroup v2.
+func forEachBackend(t *testing.T, profile Profile, count int, test func(t *testing.T, s *runnerSandbox, workers []string)) {
+	t.Run("docker", func(t *testing.T) {
+		s := newTestDocker(t, profile)
+		test(t, s, prepareTestWorkers(t, s, testWorkerImage, count))
+	})
+	t.Run("local", func(t *testing.T) {
+		s := newTestLocal(t, profile)
+		test(t, s, prepareTestWorkers(t, s, DefaultImage, count))
+	})
+}
+
+// newTestDocker builds the backend by hand, as NewDocker would remove the
+// workers of a judge running on the same daemon.
+func newTestDocker(t *testing.T, profile Profile) *runnerSandbox {
+	t.Helper()
+
+	dockerCli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	if err != nil {
+		t.Skipf("docker client: %v", err)
+	}
+	if _, err := dockerCli.Ping(context.Background()); err != nil {
+		t.Skipf("docker is not available: %v", err)
+	}
+	if err := ensureRuntimeImage(dockerCli, testWorkerImage, []byte(testWorkerDockerfile)); err != nil {
+		t.Fatalf("failed to build worker image: %v", err)
+	}
+	hostConfig, err := profile.hostConfig(testMemoryLimit)
+	if err != nil {
+		t.Fatalf("invalid profile: %v", err)
+	}
+
+	b := &dockerBackend{client: dockerCli, profile: profile, hostConfig: hostConfig}
+	t.Cleanup(func() {
+		for _, id := range b.workers {
+			_ = dockerCli.ContainerRemove(context.Background(), id, container.RemoveOptions{Force: true, RemoveVolumes: true})
+		}
+	})
+	return &runnerSandbox{backend: b, profile: profile}
+}
+
+func newTestLocal(t *testing.T, profile Profile) *runnerSandbox {
+	t.Helper()
+
+	// The sandbox dir must be reachable by the sandbox users and lie outside
+	// /tmp, which every worker replaces with its own.
+	dir, err := os.MkdirTemp("/var/lib", "code-checker-sandbox-test-")
+	if err != nil {
+		t.Skipf("local sandbox dir: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	s, err := NewLocal(profile, testMemoryLimit, dir, "/sys/fs/cgroup/code-checker-judge-test")
+	if err != nil {
+		t.Skipf("local sandbox is not available: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s.(*runnerSandbox)
+}
+
+func prepareTestWorkers(t *testing.T, s *runnerSandbox, image string, count int) []string {
+	t.Helper()
+
+	workers, err := s.Prepare(context.Background(), image, count)
+	if err != nil {
+		t.Fatalf("failed to create workers: %v", err)
+	}
+	for _, id := range workers {
+		if err := s.Cleanup(id); err != nil {
+			t.Fatalf("failed to reset worker: %v", err)
+		}
+	}
+	return workers
+}
+
+// shellInWorker runs script as root in the worker. Paths in it are given as
+// in the Docker backend.
+func shellInWorker(t *testing.T, s *runnerSandbox, workerID, script string) string {
+	t.Helper()
+
+	l := s.layout()
+	script = strings.NewReplacer("/sandbox", l.sandbox, "/judge", l.judge, "/cache", l.cache).Replace(script)
+
+	ctx := context.Background()
+	if _, err := s.Compile(ctx, workerID, CompileRequest{Program: shellProgram, Source: script, Timeout: time.Minute}); err != nil {
+		t.Fatalf("failed to write script: %v", err)
+	}
+	res, err := s.Run(ctx, workerID, RunRequest{Program: shellProgram, Timeout: 10 * time.Second, OutputLimit: 1024 * 1024})
+	if err != nil {
+		t.Fatalf("exec failed: %v", err)
+	}
+	return res.Stdout + res.Stderr
+}
+
+func TestSubmissionsCannotReadEachOther(t *testing.T) {
+	forEachBackend(t, Profile{}, 2, func(t *testing.T, s *runnerSandbox, workers []string) {
+		first, second := workers[0], workers[1]
+
+		const secret = "print('secret solution')"
+		if err := s.writeFiles(context.Background(), first, s.layout().sandbox, "", map[string]string{"main.py": secret}); err != nil {
+			t.Fatalf("failed to write submission: %v", err)
+		}
+		if out := shellInWorker(t, s, first, "cat /sandbox/main.py"); out != secret {
+			t.Fatalf("submission is not visible to its own worker: %q", out)
+		}
+
+		// A program judged concurrently in another worker.
+		if out := shellInWorker(t, s, second, "ls -A /sandbox /judge /tmp"); strings.Contains(out, "main.py") {
+			t.Fatalf("other worker can list the submission:\n%s", out)
+		}
+		if out := shellInWorker(t, s, second, "cat /sandbox/main.py; grep -rl 'secret solution' /sandbox /judge/run /tmp /root 2>/dev/null"); strings.Contains(out, "secret") {
+			t.Fatalf("other worker can read the submission:\n%s", out)
+		}
+
+		// A program judged later in the same worker, after one that tried to
+		// outlive its submission.
+		shellInWorker(t, s, first, "cp /sandbox/main.py /tmp/copy; nohup sh -c 'while :; do sleep 1; done' >/dev/null 2>&1 &")
+		if err := s.Cleanup(first); err != nil {
+			t.Fatalf("failed to reset worker: %v", err)
+		}
+		out := shellInWorker(t, s, first, "ls -A /sandbox /judge/run /tmp; ps -o args | grep '[w]hile'")
+		if strings.Contains(out, "main.py") || strings.Contains(out, "copy") || strings.Contains(out, "while") {
+			t.Fatalf("previous submission survived the reset:\n%s", out)
+		}
+	})
+}
+
+func TestHardenedWorkerContainsMaliciousPrograms(t *testing.T) {
+	profile := Profile{
+		PidsLimit:       64,
+		RunUser:         "65534:65534",
+		BuildUser:       "65533:65533",
+		ReadOnlyRootfs:  true,
+		FileSizeLimitMB: 8,
+	}
+
+	// Every program prints ESCAPED only if it got past the sandbox. Only the
+	// Docker backend filters syscalls, so only it stops user namespaces.
+	tests := map[string]string{
+		"fork bomb":       `i=0; while [ $i -lt 200 ]; do sleep 30 >/dev/null 2>&1 & i=$((i+1)); done 2>/dev/null; [ "$(ls /proc | grep -c '^[0-9]')" -lt 100 ] || echo ESCAPED`,
+		"file bomb":       `head -c 16777216 /dev/zero > /tmp/big 2>/dev/null; [ "$(wc -c < /tmp/big)" -le 8388608 ] || echo ESCAPED`,
+		"judge files":     `cat /judge/run/answer 2>/dev/null && echo ESCAPED; ls /judge 2>/dev/null && echo ESCAPED`,
+		"root filesystem": `touch /usr/bin/pwned 2>/dev/null && echo ESCAPED`,
+		"build cache":     `touch /cache/poison 2>/dev/null && echo ESCAPED; touch /sandbox/poison 2>/dev/null && echo ESCAPED`,
+		"privileges":      `[ "$(id -u)" != 0 ] || echo ESCAPED; grep CapEff /proc/self/status | grep -qv 0000000000000000 && echo ESCAPED`,
+	}
+
+	forEachBackend(t, profile, 1, func(t *testing.T, s *runnerSandbox, workers []string) {
+		worker := workers[0]
+		ctx := context.Background()
+		l := s.layout()
+
+		scripts := maps.Clone(tests)
+		if _, ok := s.backend.(*dockerBackend); ok {
+			scripts["namespaces"] = `unshare -U -r true 2>/dev/null && echo ESCAPED; unshare -m true 2>/dev/null && echo ESCAPED`
+		}
+
+		for name, script := range scripts {
+			t.Run(name, func(t *testing.T) {
+				if err := s.Cleanup(worker); err != nil {
+					t.Fatalf("failed to reset worker: %v", err)
+				}
+				script = strings.NewReplacer("/sandbox", l.sandbox, "/judge", l.judge, "/cache", l.cache).Replace(script)
+				program := Program{SourceFile: "main.sh", Run: `exec sh "$SRC"`}
+				if _, err := s.Compile(ctx, worker, CompileRequest{Program: program, Source: script, Timeout: time.Minute}); err != nil {
+					t.Fatalf("failed to write program: %v", err)
+				}
+
+				res, err := s.Run(ctx, worker, RunRequest{
+					Program:     program,
+					Files:       []File{{Name: "answer", Data: "42"}},
+					Timeout:     10 * time.Second,
+					OutputLimit: 1024 * 1024,
+				})
+				if err != nil {
+					t.Fatalf("run failed: %v", err)
+				}
+				if strings.Contains(res.Stdout, "ESCAPED") {
+					t.Fatalf("program escaped the sandbox:\n%s%s", res.Stdout, res.Stderr)
+				}
+
+				if err := s.Cleanup(worker); err != nil {
+					t.Fatalf("worker did not survive: %v", err)
+				}
+			})
+		}
+	})
+}
@@ -0,0 +1,380 @@
+//go:build linux
+
+package sandbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const localWorkerPrefix = "judge-worker-"
+
+// localPrelude runs as the first process of every exec, in fresh mount, PID,
+// network, IPC and UTS namespaces. It puts the worker's directory over the
+// sandbox root, so a worker sees none of the others, gives it its own /tmp,
+// /var/tmp and /dev/shm, and applies the rlimits before handing over to
+// judge-runner.
+const localPrelude = `set -e
+mount --make-rprivate /
+mount --bind "$1" "$2"
+for dir in /tmp /var/tmp; do
+  [ -d "$dir" ] && mount --bind "$2/tmp" "$dir"
+done
+[ -d /dev/shm ] && mount -t tmpfs -o nosuid,nodev,size=64m tmpfs /dev/shm
+mount -t proc proc /proc
+prlimit --pid $$ --core=0 ${3:+--fsize=$3}
+runner=$4
+shift 4
+exec sh -c "$runner" judge-runner "$@"`
+
+// localBackend runs workers directly on the judge's host, for deployments
+// without a Docker daemon. Each worker is a directory and a cgroup v2 group
+// holding its memory, pids and CPU limits. Submissions use the host's
+// toolchains and, unlike in Docker, are not confined by seccomp or a
+// read-only root filesystem; they still run as the unprivileged sandbox users
+// without network access.
+type localBackend struct {
+	profile     Profile
+	memoryLimit int64
+	dir         string
+	cgroupRoot  string
+
+	mu      sync.Mutex
+	workers []string
+}
+
+// NewLocal keeps workers under dir and their cgroups under cgroupRoot. The
+// judge has to run as root on a host with cgroup v2.
+func NewLocal(profile Profile, memoryLimit int64, dir, cgroupRoot string) (Sandbox, error) {
+	if err := profile.validate(); err != nil {
+		return nil, err
+	}
+	if os.Geteuid() != 0 {
+		return nil, errors.New("local sandbox requires root")
+	}
+	if _, err := os.Stat("/sys/fs/cgroup/cgroup.controllers"); err != nil {
+		return nil, errors.New("local sandbox requires cgroup v2")
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create sandbox dir: %w", err)
+	}
+	if err := os.MkdirAll(cgroupRoot, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create sandbox cgroup: %w", err)
+	}
+	// The parent may already delegate these controllers or refuse to while
+	// it has processes of its own; only the sandbox cgroup has to enable them.
+	_ = writeCgroupFile(filepath.Dir(cgroupRoot), "cgroup.subtree_control", "+memory +pids +cpu")
+	if err := writeCgroupFile(cgroupRoot, "cgroup.subtree_control", "+memory +pids +cpu"); err != nil {
+		return nil, err
+	}
+
+	b := &localBackend{profile: profile, memoryLimit: memoryLimit, dir: dir, cgroupRoot: cgroupRoot}
+	if err := b.cleanupLocalWorkers(); err != nil {
+		log.Printf("Failed to cleanup old local workers: %v", err)
+	}
+	return &runnerSandbox{backend: b, profile: profile}, nil
+}
+
+func (b *localBackend) layout() layout {
+	return layout{
+		sandbox: filepath.Join(b.dir, "sandbox"),
+		judge:   filepath.Join(b.dir, "judge"),
+		run:     filepath.Join(b.dir, "judge", "run"),
+		cache:   filepath.Join(b.dir, "cache"),
+		tmp:     "/tmp",
+	}
+}
+
+// prepare ignores the image: local workers all use the host's toolchains.
+func (b *localBackend) prepare(ctx context.Context, image string, count int) ([]string, error) {
+	workers := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		id := localWorkerPrefix + uuid.New().String()
+		if err := b.createWorker(id); err != nil {
+			return nil, err
+		}
+		b.mu.Lock()
+		b.workers = append(b.workers, id)
+		b.mu.Unlock()
+
+		if err := b.cleanup(id); err != nil {
+			return nil, err
+		}
+		workers = append(workers, id)
+	}
+	return workers, nil
+}
+
+func (b *localBackend) createWorker(id string) error {
+	root := filepath.Join(b.dir, id)
+	dirs := []struct {
+		name string
+		mode os.FileMode
+	}{
+		{"sandbox", 0755},
+		{"judge", 0700},
+		{"judge/run", 0700},
+		{"cache", 0700},
+		{"tmp", 0777 | os.ModeSticky},
+	}
+	for _, d := range dirs {
+		dir := filepath.Join(root, d.name)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create worker dir: %w", err)
+		}
+		if err := os.Chmod(dir, d.mode); err != nil {
+			return fmt.Errorf("failed to create worker dir: %w", err)
+		}
+	}
+
+	cgroup := b.cgroupDir(id)
+	if err := os.Mkdir(cgroup, 0755); err != nil {
+		return fmt.Errorf("failed to create worker cgroup: %w", err)
+	}
+	if err := writeCgroupFile(cgroup, "memory.max", strconv.FormatInt(b.memoryLimit, 10)); err != nil {
+		return err
+	}
+	if err := writeCgroupFile(cgroup, "memory.swap.max", "0"); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if err := writeCgroupFile(cgroup, "cpu.max", "50000 100000"); err != nil {
+		return err
+	}
+	if b.profile.PidsLimit > 0 {
+		if err := writeCgroupFile(cgroup, "pids.max", strconv.FormatInt(b.profile.PidsLimit, 10)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *localBackend) cgroupDir(workerID string) string {
+	return filepath.Join(b.cgroupRoot, workerID)
+}
+
+func (b *localBackend) exec(ctx context.Context, workerID string, args []string, stdin io.Reader, stdout io.Writer, outputLimit int64) (*Result, error) {
+	cgroupDir := b.cgroupDir(workerID)
+	cgroup, err := os.Open(cgroupDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open worker cgroup: %w", err)
+	}
+	defer cgroup.Close()
+
+	fileSizeLimit := ""
+	if b.profile.FileSizeLimitMB > 0 {
+		fileSizeLimit = strconv.FormatInt(b.profile.FileSizeLimitMB*1024*1024, 10)
+	}
+	cmdArgs := []string{"-c", localPrelude, "judge-sandbox",
+		filepath.Join(b.dir, workerID), b.dir, fileSizeLimit, runnerScript,
+		"--cgroup", cgroupDir,
+	}
+	cmd := exec.Command("sh", append(cmdArgs, args...)...)
+	cmd.Dir = "/"
+	cmd.Env = append([]string{"PATH=" + os.Getenv("PATH")}, goModuleEnv()...)
+	// Wait would wait for a stdin that blocks, like a pipe from an
+	// interactor, so it is copied outside of exec; the caller closes it.
+	var stdinPipe io.WriteCloser
+	if stdin != nil {
+		var err error
+		if stdinPipe, err = cmd.StdinPipe(); err != nil {
+			return nil, fmt.Errorf("failed to open worker stdin: %w", err)
+		}
+	}
+
+	kill := func() { b.kill(workerID) }
+	stdoutBuf := &limitedBuffer{limit: outputLimit}
+	stderrBuf := &limitedBuffer{limit: outputLimit}
+	if stdout == nil {
+		stdout = stdoutBuf
+	}
+	cmd.Stdout = killOnExceed{stdout, kill}
+	cmd.Stderr = killOnExceed{stderrBuf, kill}
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		Cloneflags: syscall.CLONE_NEWNS | syscall.CLONE_NEWPID | syscall.CLONE_NEWNET |
+			syscall.CLONE_NEWIPC | syscall.CLONE_NEWUTS,
+		UseCgroupFD: true,
+		CgroupFD:    int(cgroup.Fd()),
+		Pdeathsig:   syscall.SIGKILL,
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start worker process: %w", err)
+	}
+	if stdinPipe != nil {
+		go func() {
+			// A program that exits without reading all of its input breaks
+			// the pipe; that is for its verdict to show.
+			_, _ = io.Copy(stdinPipe, stdin)
+			stdinPipe.Close()
+		}()
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+
+	var waitErr error
+	select {
+	case <-ctx.Done():
+		kill()
+		<-done
+		return nil, ctx.Err()
+	case waitErr = <-done:
+	}
+
+	res := &Result{
+		Stdout:         stdoutBuf.String(),
+		Stderr:         stderrBuf.String(),
+		OutputExceeded: stdoutBuf.exceeded || stderrBuf.exceeded,
+	}
+	var exitErr *exec.ExitError
+	switch {
+	case errors.As(waitErr, &exitErr):
+		res.ExitCode = exitCode(exitErr.ProcessState)
+	case waitErr != nil && !errors.Is(waitErr, errOutputLimitExceeded) && !errors.Is(waitErr, io.ErrClosedPipe):
+		return nil, fmt.Errorf("worker process failed: %w", waitErr)
+	}
+	return res, nil
+}
+
+// exitCode reports a signal the way a shell does, as 128 plus its number.
+func exitCode(state *os.ProcessState) int {
+	if status, ok := state.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+		return 128 + int(status.Signal())
+	}
+	return state.ExitCode()
+}
+
+func (b *localBackend) kill(workerID string) {
+	cgroup := b.cgroupDir(workerID)
+	if err := writeCgroupFile(cgroup, "cgroup.kill", "1"); err == nil {
+		return
+	}
+
+	// cgroup.kill only exists since Linux 5.14.
+	procs, err := os.ReadFile(filepath.Join(cgroup, "cgroup.procs"))
+	if err != nil {
+		log.Printf("Failed to kill processes in worker %s: %v", workerID, err)
+		return
+	}
+	for _, line := range strings.Fields(string(procs)) {
+		if pid, err := strconv.Atoi(line); err == nil {
+			_ = syscall.Kill(pid, syscall.SIGKILL)
+		}
+	}
+}
+
+func (b *localBackend) setMemory(ctx context.Context, workerID string, memoryBytes int64) error {
+	if err := writeCgroupFile(b.cgroupDir(workerID), "memory.max", strconv.FormatInt(memoryBytes, 10)); err != nil {
+		return fmt.Errorf("failed to update worker memory limit: %w", err)
+	}
+	return nil
+}
+
+func (b *localBackend) cleanup(workerID string) error {
+	b.kill(workerID)
+	return resetWorker(b, workerID, b.profile)
+}
+
+// readFile reads a judge file straight from the host; only root can write to
+// the judge directory, so no submission can swap it for a symlink.
+func (b *localBackend) readFile(ctx context.Context, workerID, name string, limit int64) (string, error) {
+	rel, err := filepath.Rel(b.dir, name)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return "", fmt.Errorf("failed to read %s from worker: outside the worker dir", name)
+	}
+
+	f, err := os.Open(filepath.Join(b.dir, workerID, rel))
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s from worker: %w", name, err)
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(io.LimitReader(f, limit))
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s from worker: %w", name, err)
+	}
+	return string(data), nil
+}
+
+func (b *localBackend) close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, id := range b.workers {
+		if err := b.removeWorker(id); err != nil {
+			return err
+		}
+	}
+	b.workers = nil
+	return nil
+}
+
+func (b *localBackend) cleanupLocalWorkers() error {
+	entries, err := os.ReadDir(b.cgroupRoot)
+	if err != nil {
+		return fmt.Errorf("list worker cgroups: %w", err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() && strings.HasPrefix(entry.Name(), localWorkerPrefix) {
+			if err := b.removeWorker(entry.Name()); err != nil {
+				return err
+			}
+		}
+	}
+
+	dirs, err := filepath.Glob(filepath.Join(b.dir, localWorkerPrefix+"*"))
+	if err != nil {
+		return fmt.Errorf("list worker dirs: %w", err)
+	}
+	for _, dir := range dirs {
+		if err := os.RemoveAll(dir); err != nil {
+			return fmt.Errorf("remove worker dir %s: %w", dir, err)
+		}
+	}
+	return nil
+}
+
+func (b *localBackend) removeWorker(id string) error {
+	b.kill(id)
+
+	// The cgroup can only go once the kernel has reaped its processes.
+	cgroup := b.cgroupDir(id)
+	var err error
+	for i := 0; i < 50; i++ {
+		if err = os.Remove(cgroup); err == nil || errors.Is(err, os.ErrNotExist) {
+			err = nil
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	if err != nil {
+		return fmt.Errorf("remove worker cgroup %s: %w", id, err)
+	}
+
+	if err := os.RemoveAll(filepath.Join(b.dir, id)); err != nil {
+		return fmt.Errorf("remove worker dir %s: %w", id, err)
+	}
+	return nil
+}
+
+func writeCgroupFile(dir, name, value string) error {
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(value), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,11 @@
+//go:build !linux
+
+package sandbox
+
+import "errors"
+
+// NewLocal is only available on Linux, which has the namespaces and cgroups
+// the local backend relies on.
+func NewLocal(profile Profile, memoryLimit int64, dir, cgroupRoot string) (Sandbox, error) {
+	return nil, errors.New("local sandbox is only supported on linux")
+}
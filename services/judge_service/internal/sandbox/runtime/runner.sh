@@ -0,0 +1,245 @@
+#!/bin/sh
+set -eu
+
+WORKDIR=""
+PHASE=""
+SRC=""
+OUTBIN=""
+TIMEOUT=""
+MEMORY_MB=""
+STATS=""
+COMPILE_CMD=""
+RUN_CMD=""
+INTERACTOR_RUN=""
+INTERACTOR_DIR=""
+INTERACTOR_SRC=""
+INTERACTOR_BIN=""
+INTERACTOR_TIMEOUT=""
+OUTPUT_LIMIT=""
+USERS=""
+HOME_DIR=""
+OWNER=""
+CACHE_DIR=""
+CGROUP_DIR="/sys/fs/cgroup"
+
+while [ $# -gt 0 ]; do
+  case "$1" in
+    --workdir)
+      WORKDIR="$2"; shift 2;;
+    --phase)
+      PHASE="$2"; shift 2;;
+    --src)
+      SRC="$2"; shift 2;;
+    --outbin)
+      OUTBIN="$2"; shift 2;;
+    --timeout)
+      TIMEOUT="$2"; shift 2;;
+    --memory-mb)
+      MEMORY_MB="$2"; shift 2;;
+    --stats)
+      STATS="$2"; shift 2;;
+    --compile)
+      COMPILE_CMD="$2"; shift 2;;
+    --run)
+      RUN_CMD="$2"; shift 2;;
+    --interactor-run)
+      INTERACTOR_RUN="$2"; shift 2;;
+    --interactor-dir)
+      INTERACTOR_DIR="$2"; shift 2;;
+    --interactor-src)
+      INTERACTOR_SRC="$2"; shift 2;;
+    --interactor-bin)
+      INTERACTOR_BIN="$2"; shift 2;;
+    --interactor-timeout)
+      INTERACTOR_TIMEOUT="$2"; shift 2;;
+    --output-limit)
+      OUTPUT_LIMIT="$2"; shift 2;;
+    --user)
+      USERS="${USERS:+$USERS }$2"; shift 2;;
+    --home)
+      HOME_DIR="$2"; shift 2;;
+    --owner)
+      OWNER="$2"; shift 2;;
+    --cache)
+      CACHE_DIR="$2"; shift 2;;
+    --cgroup)
+      CGROUP_DIR="$2"; shift 2;;
+    --)
+      shift; break;;
+    *)
+      echo "unknown arg: $1" >&2; exit 2;;
+  esac
+done
+
+# drop prints the command prefix that runs a program as uid:gid. The runner
+# itself stays root, so it can still read judge files and kill leftovers.
+drop() {
+  if [ -z "$1" ]; then
+    return 0
+  fi
+  if ! command -v setpriv >/dev/null 2>&1; then
+    echo "setpriv is required to run as $1" >&2
+    return 2
+  fi
+  echo "setpriv --reuid=${1%%:*} --regid=${1#*:} --clear-groups"
+}
+
+if [ "$PHASE" = "kill" ]; then
+  # Signals everything in the worker except PID 1 and this shell.
+  kill -KILL -1 2>/dev/null || true
+  exit 0
+fi
+
+if [ "$PHASE" = "cleanup" ]; then
+  # Kills what the last submission left running, then empties the workdir and
+  # every directory given after --. Root may not delete the sandbox users'
+  # files, so each user removes its own first. Finally the workdir and the
+  # build cache are handed to --owner.
+  kill -KILL -1 2>/dev/null || true
+  for dir in "$WORKDIR" "$@"; do
+    mkdir -p "$dir"
+    for user in $USERS; do
+      prefix=$(drop "$user")
+      $prefix sh -c 'chmod -R u+rwX "$1"; find "$1" -mindepth 1 -delete' sh "$dir" 2>/dev/null || true
+    done
+    find "$dir" -mindepth 1 -delete
+  done
+  if [ -n "$OWNER" ]; then
+    chown "$OWNER" "$WORKDIR"
+    if [ -n "$CACHE_DIR" ] && [ "$(stat -c %u:%g "$CACHE_DIR")" != "$OWNER" ]; then
+      chmod 700 "$CACHE_DIR"
+      chown "$OWNER" "$CACHE_DIR"
+    fi
+  fi
+  exit 0
+fi
+
+SANDBOX=$(drop "$USERS")
+
+if [ "$PHASE" = "write" ]; then
+  # Unpacks a tar archive from stdin into the workdir.
+  mkdir -p "$WORKDIR"
+  exec $SANDBOX tar -xof - -C "$WORKDIR"
+fi
+
+if [ -z "$WORKDIR" ] || [ -z "$PHASE" ] || [ -z "$TIMEOUT" ]; then
+  echo "missing args" >&2
+  exit 2
+fi
+
+cd "$WORKDIR"
+if [ -n "$HOME_DIR" ]; then
+  export HOME="$HOME_DIR"
+fi
+export SRC
+export BIN="$OUTBIN"
+export MEMORY_LIMIT_MB="$MEMORY_MB"
+
+oom_kills() {
+  if [ -f "$CGROUP_DIR/memory.events" ]; then
+    awk '$1 == "oom_kill" { print $2 }' "$CGROUP_DIR/memory.events"
+  elif [ -f "$CGROUP_DIR/memory/memory.oom_control" ]; then
+    awk '$1 == "oom_kill" { print $2 }' "$CGROUP_DIR/memory/memory.oom_control"
+  else
+    echo 0
+  fi
+}
+
+# run_measured runs the program under the time limit and, when --stats is
+# given, writes its peak RSS and the number of OOM kills it caused there.
+run_measured() {
+  if [ -z "$STATS" ]; then
+    timeout "${TIMEOUT}s" "$@"
+    return
+  fi
+
+  rm -f "$STATS"
+  before=$(oom_kills)
+  set +e
+  if [ -x /usr/bin/time ]; then
+    /usr/bin/time -f "%M" -o "$STATS.rss" timeout "${TIMEOUT}s" "$@"
+  else
+    timeout "${TIMEOUT}s" "$@"
+  fi
+  code=$?
+  set -e
+  after=$(oom_kills)
+
+  rss=$(tail -n 1 "$STATS.rss" 2>/dev/null || echo 0)
+  rm -f "$STATS.rss"
+  printf 'max_rss_kb=%s\noom_kills=%s\n' "${rss:-0}" "$((${after:-0} - ${before:-0}))" > "$STATS"
+  return "$code"
+}
+
+case "$PHASE" in
+  compile)
+    if [ -z "$COMPILE_CMD" ]; then
+      exit 0
+    fi
+    # Whatever the build user writes stays private to it, the build cache
+    # above all; only the workdir is opened up to the run user afterwards.
+    code=0
+    timeout "${TIMEOUT}s" $SANDBOX sh -c "umask 077; $COMPILE_CMD" || code=$?
+    $SANDBOX chmod -R a+rX . 2>/dev/null || true
+    exit "$code"
+    ;;
+  run)
+    if [ -z "$RUN_CMD" ]; then
+      echo "missing run command" >&2; exit 2
+    fi
+    run_measured $SANDBOX sh -c "$RUN_CMD" sh "$@"
+    ;;
+  interact)
+    # The program and the interactor are wired together through two FIFOs.
+    # The program's exit code is returned as usual, the interactor's one is
+    # appended to the stats file and its stderr goes to our stdout.
+    if [ -z "$RUN_CMD" ] || [ -z "$INTERACTOR_RUN" ] || [ -z "$INTERACTOR_DIR" ] || [ -z "$STATS" ]; then
+      echo "missing interactor args" >&2; exit 2
+    fi
+
+    pipes=$(mktemp -d)
+    mkfifo "$pipes/to_interactor" "$pipes/to_program"
+    exec 3>&1
+
+    # With --output-limit the program's output is also counted on its way to
+    # the interactor. Once it goes past the limit, head stops reading, which
+    # stops tee and leaves the program writing to a closed pipe.
+    program_out="$pipes/to_interactor"
+    limiter=""
+    if [ -n "$OUTPUT_LIMIT" ]; then
+      mkfifo "$pipes/from_program"
+      program_out="$pipes/from_program"
+      (
+        written=$(tee "$pipes/to_interactor" | head -c "$((OUTPUT_LIMIT + 1))" | wc -c)
+        if [ "$written" -gt "$OUTPUT_LIMIT" ]; then
+          : >"$pipes/overflow"
+        fi
+      ) <"$pipes/from_program" &
+      limiter=$!
+    fi
+
+    (
+      cd "$INTERACTOR_DIR"
+      SRC="$INTERACTOR_SRC" BIN="$INTERACTOR_BIN" \
+        exec timeout "${INTERACTOR_TIMEOUT:-$TIMEOUT}s" sh -c "$INTERACTOR_RUN" sh "$@"
+    ) <"$pipes/to_interactor" >"$pipes/to_program" 2>&3 &
+    interactor=$!
+
+    code=0
+    run_measured $SANDBOX sh -c "$RUN_CMD" sh >"$program_out" <"$pipes/to_program" || code=$?
+    interactor_code=0
+    wait "$interactor" || interactor_code=$?
+    if [ -n "$limiter" ]; then
+      wait "$limiter" || true
+    fi
+
+    if [ -e "$pipes/overflow" ]; then
+      echo "output_exceeded=1" >> "$STATS"
+    fi
+    rm -rf "$pipes"
+    echo "interactor_exit=$interactor_code" >> "$STATS"
+    exit "$code"
+    ;;
+  *)
+    echo "unknown phase: $PHASE" >&2; exit 2;;
+esac
\ No newline at end of file
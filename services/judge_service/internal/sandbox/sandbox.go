@@ -0,0 +1,585 @@
+package sandbox
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Sandbox runs untrusted programs in isolated workers. A worker judges one
+// submission at a time; Cleanup kills whatever the submission left running
+// and wipes its files before the worker is reused.
+type Sandbox interface {
+	// Prepare starts count workers able to run programs built for image.
+	Prepare(ctx context.Context, image string, count int) ([]string, error)
+	// Compile writes the program's sources to the worker and builds it.
+	Compile(ctx context.Context, workerID string, req CompileRequest) (*Result, error)
+	// Run executes a compiled program under the request's limits.
+	Run(ctx context.Context, workerID string, req RunRequest) (*Result, error)
+	Cleanup(workerID string) error
+	Close() error
+}
+
+// Program is either the submission, which lives in the worker's sandbox
+// directory, or a checker or interactor kept in the judge directory under
+// Name, out of the submission's reach.
+type Program struct {
+	Name       string
+	SourceFile string
+	Compile    string
+	Run        string
+	// WasmCompile replaces Compile and Run in the WebAssembly backend.
+	WasmCompile string
+}
+
+type CompileRequest struct {
+	Program Program
+	Source  string
+	// Files are the other files of a multi-file program, named by their
+	// slash-separated path relative to Source.
+	Files       []File
+	Timeout     time.Duration
+	MemoryBytes int64
+	OutputLimit int64
+}
+
+func (r CompileRequest) sources() map[string]string {
+	files := make(map[string]string, len(r.Files)+1)
+	for _, f := range r.Files {
+		files[f.Name] = f.Data
+	}
+	files[r.Program.SourceFile] = r.Source
+	return files
+}
+
+// goModuleEnv passes the judge's Go module settings to builds that run on its
+// host, as in the local and wasm backends: without network access these need
+// GOPROXY to point at a directory with the modules problems allow, like the
+// one baked into the runtime image.
+func goModuleEnv() []string {
+	var env []string
+	for _, key := range []string{"GOPROXY", "GOSUMDB", "GOTOOLCHAIN"} {
+		if value, ok := os.LookupEnv(key); ok {
+			env = append(env, key+"="+value)
+		}
+	}
+	return env
+}
+
+// CheckPath rejects file names that would land outside the program's
+// directory once unpacked.
+func CheckPath(name string) error {
+	if name == "" || path.IsAbs(name) || strings.Contains(name, `\`) {
+		return fmt.Errorf("invalid file path %q", name)
+	}
+	for _, elem := range strings.Split(name, "/") {
+		if elem == "" || elem == "." || elem == ".." {
+			return fmt.Errorf("invalid file path %q", name)
+		}
+	}
+	return nil
+}
+
+type RunRequest struct {
+	Program Program
+	Stdin   string
+	// Files are written to the judge run directory and passed by path to the
+	// interactor, or to the program when there is none.
+	Files   []File
+	Timeout time.Duration
+	// MemoryBytes is the worker memory limit, left as is when zero.
+	// MemoryLimitMB is what the program is told via $MEMORY_LIMIT_MB.
+	MemoryBytes   int64
+	MemoryLimitMB int64
+	OutputLimit   int64
+	Interactor    *Program
+	// InteractorWorker is the worker the interactor was compiled in, if not
+	// the program's one; the two then talk through the judge.
+	InteractorWorker  string
+	InteractorTimeout time.Duration
+}
+
+type File struct {
+	Name string
+	Data string
+}
+
+type Result struct {
+	Stdout         string
+	Stderr         string
+	ExitCode       int
+	OutputExceeded bool
+	MaxRSSKb       int64
+	OOMKills       int64
+	// InteractorExit is the interactor's exit code, or -1 if it reported none.
+	InteractorExit int64
+}
+
+// Profile configures how workers are locked down. A submission is compiled
+// as BuildUser and run as RunUser ("uid:gid"), so the running program can read
+// neither judge files nor the build cache. An empty user leaves that phase
+// running as root; zero limits are not applied. ReadOnlyRootfs and
+// SeccompProfile only apply to the Docker backend.
+type Profile struct {
+	PidsLimit       int64
+	RunUser         string
+	BuildUser       string
+	ReadOnlyRootfs  bool
+	FileSizeLimitMB int64
+	// SeccompProfile is the path to a Docker seccomp profile, "unconfined",
+	// or empty for the embedded one.
+	SeccompProfile string
+}
+
+func (p Profile) validate() error {
+	for _, user := range []string{p.RunUser, p.BuildUser} {
+		if user == "" {
+			continue
+		}
+		if _, _, err := parseUser(user); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func parseUser(user string) (int, int, error) {
+	uid, gid, ok := strings.Cut(user, ":")
+	if !ok {
+		return 0, 0, fmt.Errorf("sandbox user %q must be uid:gid", user)
+	}
+	ids := make([]int, 0, 2)
+	for _, id := range []string{uid, gid} {
+		n, err := strconv.ParseUint(id, 10, 32)
+		if err != nil || n == 0 {
+			return 0, 0, fmt.Errorf("sandbox user %q must be a non-root numeric uid:gid", user)
+		}
+		ids = append(ids, int(n))
+	}
+	return ids[0], ids[1], nil
+}
+
+// DefaultImage is the runtime for languages that do not name their own.
+const DefaultImage = "code-checker-judge-runtime:latest"
+
+const (
+	execGrace      = 5 * time.Second
+	maxStatsBytes  = 4 * 1024
+	maxStderrBytes = 4 * 1024
+	binaryName     = "app.bin"
+)
+
+//go:embed runtime/runner.sh
+var runtimeRunnerScript []byte
+
+var runnerScript = string(normalizeLineEndings(runtimeRunnerScript))
+
+// layout is where a worker keeps its files, as judge-runner sees them. The
+// submission lives in sandbox, judge-only files (stats, checker files,
+// compiled checkers and interactors) in judge, which only root can enter,
+// and the build user's caches in cache.
+type layout struct {
+	sandbox string
+	judge   string
+	run     string
+	cache   string
+	tmp     string
+}
+
+// backend is what an isolation mechanism has to provide; judge-runner, run
+// inside a worker, does the rest.
+type backend interface {
+	prepare(ctx context.Context, image string, count int) ([]string, error)
+	layout() layout
+	// exec runs judge-runner with args inside the worker, feeding it stdin if
+	// there is one. Its stdout goes to stdout when that is given and to the
+	// result otherwise; a failed write to it stops the run.
+	exec(ctx context.Context, workerID string, args []string, stdin io.Reader, stdout io.Writer, outputLimit int64) (*Result, error)
+	readFile(ctx context.Context, workerID, name string, limit int64) (string, error)
+	setMemory(ctx context.Context, workerID string, memoryBytes int64) error
+	cleanup(workerID string) error
+	close() error
+}
+
+type runnerSandbox struct {
+	backend
+	profile Profile
+}
+
+func (s *runnerSandbox) Prepare(ctx context.Context, image string, count int) ([]string, error) {
+	return s.prepare(ctx, image, count)
+}
+
+func (s *runnerSandbox) Compile(ctx context.Context, workerID string, req CompileRequest) (*Result, error) {
+	l := s.layout()
+	dir, user, home := s.programDir(req.Program), "", l.judge
+	if req.Program.Name == "" {
+		user, home = s.profile.BuildUser, l.cache
+	}
+
+	if err := s.writeFiles(ctx, workerID, dir, user, req.sources()); err != nil {
+		return nil, fmt.Errorf("failed to write source: %w", err)
+	}
+	if req.MemoryBytes > 0 {
+		if err := s.setMemory(ctx, workerID, req.MemoryBytes); err != nil {
+			return nil, err
+		}
+	}
+
+	buildCtx, cancel := context.WithTimeout(ctx, req.Timeout+execGrace)
+	defer cancel()
+
+	return s.exec(buildCtx, workerID, []string{
+		"--phase", "compile",
+		"--workdir", dir,
+		"--src", req.Program.SourceFile,
+		"--outbin", path.Join(dir, binaryName),
+		"--compile", req.Program.Compile,
+		"--timeout", fmt.Sprintf("%d", int(req.Timeout.Seconds())),
+		"--user", user,
+		"--home", home,
+	}, nil, nil, req.OutputLimit)
+}
+
+func (s *runnerSandbox) Run(ctx context.Context, workerID string, req RunRequest) (*Result, error) {
+	if req.Interactor != nil && req.InteractorWorker != "" && req.InteractorWorker != workerID {
+		return s.relayInteraction(ctx, workerID, req)
+	}
+
+	paths, err := s.writeRunFiles(ctx, workerID, req.Files)
+	if err != nil {
+		return nil, err
+	}
+	if req.MemoryBytes > 0 {
+		if err := s.setMemory(ctx, workerID, req.MemoryBytes); err != nil {
+			return nil, err
+		}
+	}
+
+	phase := "run"
+	if req.Interactor != nil {
+		phase = "interact"
+	}
+	args := s.runArgs(phase, req.Program, req.Timeout, req.MemoryLimitMB)
+	timeout := req.Timeout
+	if req.Interactor != nil {
+		interactorDir := s.programDir(*req.Interactor)
+		args = append(args,
+			"--interactor-run", req.Interactor.Run,
+			"--interactor-dir", interactorDir,
+			"--interactor-src", req.Interactor.SourceFile,
+			"--interactor-bin", path.Join(interactorDir, binaryName),
+			"--interactor-timeout", fmt.Sprintf("%.3f", req.InteractorTimeout.Seconds()),
+		)
+		if req.OutputLimit > 0 {
+			args = append(args, "--output-limit", fmt.Sprintf("%d", req.OutputLimit))
+		}
+		timeout = max(timeout, req.InteractorTimeout)
+	}
+	args = append(append(args, "--"), paths...)
+
+	runCtx, cancel := context.WithTimeout(ctx, timeout+execGrace)
+	defer cancel()
+
+	res, err := s.exec(runCtx, workerID, args, strings.NewReader(req.Stdin), nil, req.OutputLimit)
+	if err != nil {
+		return nil, err
+	}
+	stats, _ := s.readFile(runCtx, workerID, s.statsPath(), maxStatsBytes)
+	parseStats(res, stats)
+	return res, nil
+}
+
+// relayInteraction runs the program and an interactor compiled in another
+// worker with a run each and passes their output between them, since the
+// interact phase can only wire up programs of one worker.
+func (s *runnerSandbox) relayInteraction(ctx context.Context, workerID string, req RunRequest) (*Result, error) {
+	// The judge files stay with the interactor, out of the program's reach.
+	paths, err := s.writeRunFiles(ctx, req.InteractorWorker, req.Files)
+	if err != nil {
+		return nil, err
+	}
+	if req.MemoryBytes > 0 {
+		for _, id := range []string{workerID, req.InteractorWorker} {
+			if err := s.setMemory(ctx, id, req.MemoryBytes); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, max(req.Timeout, req.InteractorTimeout)+execGrace)
+	defer cancel()
+
+	programIn, interactorOut := io.Pipe()
+	interactorIn, programOut := io.Pipe()
+	type execResult struct {
+		res *Result
+		err error
+	}
+	interactorDone := make(chan execResult, 1)
+	go func() {
+		args := append(append(s.runArgs("run", *req.Interactor, req.InteractorTimeout, 0), "--"), paths...)
+		res, err := s.exec(runCtx, req.InteractorWorker, args, interactorIn, interactorOut, req.OutputLimit)
+		interactorOut.Close()
+		interactorIn.Close()
+		interactorDone <- execResult{res, err}
+	}()
+
+	// The program's output to the interactor counts against the output limit
+	// too; once the pipe refuses it, the backend stops the program.
+	toInteractor := &limitedPipe{w: programOut, limit: req.OutputLimit}
+	res, err := s.exec(runCtx, workerID, s.runArgs("run", req.Program, req.Timeout, req.MemoryLimitMB), programIn, toInteractor, req.OutputLimit)
+	programOut.Close()
+	programIn.Close()
+	interactor := <-interactorDone
+	if err != nil {
+		return nil, err
+	}
+	if interactor.err != nil {
+		return nil, fmt.Errorf("interactor failed: %w", interactor.err)
+	}
+
+	stats, _ := s.readFile(runCtx, workerID, s.statsPath(), maxStatsBytes)
+	parseStats(res, stats)
+	// Like the interact phase, the result's stdout is the interactor's stderr.
+	res.Stdout = interactor.res.Stderr
+	res.InteractorExit = int64(interactor.res.ExitCode)
+	res.OutputExceeded = res.OutputExceeded || interactor.res.OutputExceeded || toInteractor.exceeded
+	return res, nil
+}
+
+// runArgs are judge-runner's arguments for running program in phase. The
+// submission runs as the run user; checkers and interactors run as root in
+// the judge directory.
+func (s *runnerSandbox) runArgs(phase string, program Program, timeout time.Duration, memoryLimitMB int64) []string {
+	l := s.layout()
+	dir, user, home := s.programDir(program), "", l.judge
+	if program.Name == "" {
+		user, home = s.profile.RunUser, l.tmp
+	}
+	args := []string{
+		"--phase", phase,
+		"--workdir", dir,
+		"--src", program.SourceFile,
+		"--outbin", path.Join(dir, binaryName),
+		"--run", program.Run,
+		"--timeout", fmt.Sprintf("%.3f", timeout.Seconds()),
+		"--stats", s.statsPath(),
+		"--user", user,
+		"--home", home,
+	}
+	if memoryLimitMB > 0 {
+		args = append(args, "--memory-mb", fmt.Sprintf("%d", memoryLimitMB))
+	}
+	return args
+}
+
+func (s *runnerSandbox) statsPath() string {
+	return path.Join(s.layout().run, "run.stats")
+}
+
+// writeRunFiles writes the judge files of a run to the worker's run
+// directory and returns their paths there.
+func (s *runnerSandbox) writeRunFiles(ctx context.Context, workerID string, runFiles []File) ([]string, error) {
+	l := s.layout()
+	files := make(map[string]string, len(runFiles))
+	var paths []string
+	for _, file := range runFiles {
+		files[file.Name] = file.Data
+		paths = append(paths, path.Join(l.run, file.Name))
+	}
+	if len(files) > 0 {
+		if err := s.writeFiles(ctx, workerID, l.run, "", files); err != nil {
+			return nil, fmt.Errorf("failed to write judge files: %w", err)
+		}
+	}
+	return paths, nil
+}
+
+func (s *runnerSandbox) Cleanup(workerID string) error {
+	return s.cleanup(workerID)
+}
+
+func (s *runnerSandbox) Close() error {
+	return s.close()
+}
+
+func (s *runnerSandbox) programDir(program Program) string {
+	if program.Name == "" {
+		return s.layout().sandbox
+	}
+	return path.Join(s.layout().judge, program.Name)
+}
+
+// writeFiles extracts files into dir inside the worker as user. Going through
+// judge-runner rather than writing them directly keeps a user from making the
+// judge follow its symlinks, and works where the Docker copy API cannot see
+// the worker's tmpfs mounts.
+func (s *runnerSandbox) writeFiles(ctx context.Context, workerID, dir, user string, files map[string]string) error {
+	dirs := make(map[string]bool)
+	for name := range files {
+		if err := CheckPath(name); err != nil {
+			return err
+		}
+		for d := path.Dir(name); d != "."; d = path.Dir(d) {
+			dirs[d] = true
+		}
+	}
+
+	// Directories come first, parents before children, so tar creates them
+	// as the program's user.
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, d := range slices.Sorted(maps.Keys(dirs)) {
+		if err := tw.WriteHeader(&tar.Header{Name: d + "/", Typeflag: tar.TypeDir, Mode: 0755}); err != nil {
+			return fmt.Errorf("failed to write %s header: %w", d, err)
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(files)) {
+		data := files[name]
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(data))}); err != nil {
+			return fmt.Errorf("failed to write %s header: %w", name, err)
+		}
+		if _, err := tw.Write([]byte(data)); err != nil {
+			return fmt.Errorf("failed to write %s: %w", name, err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to close archive: %w", err)
+	}
+
+	res, err := s.exec(ctx, workerID, []string{"--phase", "write", "--workdir", dir, "--user", user}, &buf, nil, maxStderrBytes)
+	if err != nil {
+		return fmt.Errorf("failed to copy files to worker: %w", err)
+	}
+	if res.ExitCode != 0 {
+		return fmt.Errorf("failed to copy files to worker (exit code %d): %s", res.ExitCode, strings.TrimSpace(res.Stderr))
+	}
+	return nil
+}
+
+// resetWorker makes judge-runner kill what the last submission left running,
+// empty its directories and hand the sandbox and the build cache to the
+// build user.
+func resetWorker(b backend, workerID string, profile Profile) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	l := b.layout()
+	res, err := b.exec(ctx, workerID, []string{
+		"--phase", "cleanup",
+		"--workdir", l.sandbox,
+		"--user", profile.RunUser,
+		"--user", profile.BuildUser,
+		"--owner", profile.BuildUser,
+		"--cache", l.cache,
+		"--", l.run, l.tmp,
+	}, nil, nil, maxStderrBytes)
+	if err != nil {
+		return fmt.Errorf("failed to reset worker: %w", err)
+	}
+	if res.ExitCode != 0 {
+		return fmt.Errorf("failed to reset worker (exit code %d): %s", res.ExitCode, strings.TrimSpace(res.Stderr))
+	}
+	return nil
+}
+
+func parseStats(res *Result, data string) {
+	res.InteractorExit = -1
+	for _, line := range strings.Split(data, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), "=")
+		if !ok {
+			continue
+		}
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			continue
+		}
+		switch key {
+		case "max_rss_kb":
+			res.MaxRSSKb = n
+		case "oom_kills":
+			res.OOMKills = n
+		case "interactor_exit":
+			res.InteractorExit = n
+		case "output_exceeded":
+			res.OutputExceeded = res.OutputExceeded || n > 0
+		}
+	}
+}
+
+var errOutputLimitExceeded = errors.New("output limit exceeded")
+
+type limitedBuffer struct {
+	bytes.Buffer
+	limit    int64
+	exceeded bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	remaining := b.limit - int64(b.Len())
+	if int64(len(p)) > remaining {
+		b.exceeded = true
+		if remaining > 0 {
+			b.Buffer.Write(p[:remaining])
+		}
+		return 0, errOutputLimitExceeded
+	}
+	return b.Buffer.Write(p)
+}
+
+// killOnExceed stops the program once a write fails, past the output limit
+// or to an interactor that is gone, rather than leaving it blocked on a pipe
+// nobody reads.
+type killOnExceed struct {
+	io.Writer
+	kill func()
+}
+
+func (w killOnExceed) Write(p []byte) (int, error) {
+	n, err := w.Writer.Write(p)
+	if err != nil {
+		w.kill()
+	}
+	return n, err
+}
+
+// limitedPipe passes at most limit bytes on to w and calls kill, if set, on
+// a write that goes past it.
+type limitedPipe struct {
+	w        io.Writer
+	limit    int64
+	written  int64
+	exceeded bool
+	kill     func()
+}
+
+func (p *limitedPipe) Write(b []byte) (int, error) {
+	if p.limit > 0 && p.written+int64(len(b)) > p.limit {
+		p.exceeded = true
+		if p.kill != nil {
+			p.kill()
+		}
+		return 0, errOutputLimitExceeded
+	}
+	n, err := p.w.Write(b)
+	p.written += int64(n)
+	return n, err
+}
+
+func normalizeLineEndings(data []byte) []byte {
+	out := bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+	out = bytes.ReplaceAll(out, []byte("\r"), []byte("\n"))
+	return out
+}
@@ -0,0 +1,320 @@
+package sandbox
+
+import (
+	"archive/tar"
+	"context"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeExec struct {
+	workerID string
+	args     []string
+	stdin    string
+}
+
+// fakeBackend records what judge-runner would be asked to do.
+type fakeBackend struct {
+	mu     sync.Mutex
+	execs  []fakeExec
+	result Result
+	stats  string
+	// talk stands in for a program whose output goes to a writer, reading
+	// and writing as it likes.
+	talk func(workerID string, stdin io.Reader, stdout io.Writer) Result
+}
+
+func (b *fakeBackend) prepare(ctx context.Context, image string, count int) ([]string, error) {
+	return []string{"worker"}, nil
+}
+
+func (b *fakeBackend) layout() layout {
+	return dockerLayout
+}
+
+func (b *fakeBackend) exec(ctx context.Context, workerID string, args []string, stdin io.Reader, stdout io.Writer, outputLimit int64) (*Result, error) {
+	res := b.result
+	var data []byte
+	if stdout != nil {
+		res = b.talk(workerID, stdin, stdout)
+	} else if stdin != nil {
+		data, _ = io.ReadAll(stdin)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.execs = append(b.execs, fakeExec{workerID: workerID, args: args, stdin: string(data)})
+	return &res, nil
+}
+
+func (b *fakeBackend) readFile(ctx context.Context, workerID, name string, limit int64) (string, error) {
+	return b.stats, nil
+}
+
+func (b *fakeBackend) setMemory(ctx context.Context, workerID string, memoryBytes int64) error {
+	return nil
+}
+
+func (b *fakeBackend) cleanup(workerID string) error {
+	return nil
+}
+
+func (b *fakeBackend) close() error {
+	return nil
+}
+
+var testProfile = Profile{RunUser: "65534:65534", BuildUser: "65533:65533"}
+
+func flag(args []string, name string) string {
+	for i := 0; i+1 < len(args); i++ {
+		if args[i] == name {
+			return args[i+1]
+		}
+	}
+	return ""
+}
+
+func TestCompileUsers(t *testing.T) {
+	tests := []struct {
+		name     string
+		program  Program
+		wantDir  string
+		wantUser string
+		wantHome string
+	}{
+		{
+			name:     "submission",
+			program:  Program{SourceFile: "main.go", Compile: "go build"},
+			wantDir:  "/sandbox",
+			wantUser: "65533:65533",
+			wantHome: "/cache",
+		},
+		{
+			name:     "checker",
+			program:  Program{Name: "checkers/p1-abc", SourceFile: "main.cpp", Compile: "g++"},
+			wantDir:  "/judge/checkers/p1-abc",
+			wantHome: "/judge",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &fakeBackend{}
+			s := &runnerSandbox{backend: b, profile: testProfile}
+
+			if _, err := s.Compile(context.Background(), "worker", CompileRequest{Program: tt.program, Source: "code", Timeout: time.Minute}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(b.execs) != 2 {
+				t.Fatalf("got %d execs, want write and compile", len(b.execs))
+			}
+
+			write, compile := b.execs[0].args, b.execs[1].args
+			if flag(write, "--phase") != "write" || flag(write, "--workdir") != tt.wantDir || flag(write, "--user") != tt.wantUser {
+				t.Fatalf("unexpected write args: %v", write)
+			}
+			tr := tar.NewReader(strings.NewReader(b.execs[0].stdin))
+			hdr, err := tr.Next()
+			if err != nil || hdr.Name != tt.program.SourceFile {
+				t.Fatalf("unexpected archive entry %v: %v", hdr, err)
+			}
+			if data, _ := io.ReadAll(tr); string(data) != "code" {
+				t.Fatalf("unexpected source %q", data)
+			}
+
+			if flag(compile, "--phase") != "compile" || flag(compile, "--workdir") != tt.wantDir ||
+				flag(compile, "--user") != tt.wantUser || flag(compile, "--home") != tt.wantHome ||
+				flag(compile, "--outbin") != tt.wantDir+"/app.bin" {
+				t.Fatalf("unexpected compile args: %v", compile)
+			}
+		})
+	}
+}
+
+func TestCompileWritesFileTree(t *testing.T) {
+	b := &fakeBackend{}
+	s := &runnerSandbox{backend: b, profile: testProfile}
+	req := CompileRequest{
+		Program: Program{SourceFile: "main.go", Compile: "go build"},
+		Source:  "package main",
+		Files: []File{
+			{Name: "internal/calc/calc.go", Data: "package calc"},
+			{Name: "go.mod", Data: "module app"},
+		},
+		Timeout: time.Minute,
+	}
+	if _, err := s.Compile(context.Background(), "worker", req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var names []string
+	tr := tar.NewReader(strings.NewReader(b.execs[0].stdin))
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("failed to read archive: %v", err)
+		}
+		names = append(names, hdr.Name)
+	}
+	want := []string{"internal/", "internal/calc/", "go.mod", "internal/calc/calc.go", "main.go"}
+	if !slices.Equal(names, want) {
+		t.Fatalf("unexpected archive entries %q, want %q", names, want)
+	}
+
+	for _, name := range []string{"../escape.go", "/etc/passwd", "a//b.go"} {
+		req.Files = []File{{Name: name}}
+		if _, err := s.Compile(context.Background(), "worker", req); err == nil {
+			t.Fatalf("expected %q to be rejected", name)
+		}
+	}
+}
+
+func TestRunInteractive(t *testing.T) {
+	b := &fakeBackend{
+		result: Result{Stdout: "ok", ExitCode: 0},
+		stats:  "max_rss_kb=2048\noom_kills=1\noutput_exceeded=1\ninteractor_exit=2\n",
+	}
+	s := &runnerSandbox{backend: b, profile: testProfile}
+
+	res, err := s.Run(context.Background(), "worker", RunRequest{
+		Program:           Program{SourceFile: "main.py", Run: "python3 main.py"},
+		Files:             []File{{Name: "interactor.in", Data: "1"}, {Name: "interactor.ans", Data: "2"}},
+		Timeout:           time.Second,
+		MemoryLimitMB:     64,
+		OutputLimit:       1024,
+		Interactor:        &Program{Name: "interactors/p1-abc", SourceFile: "main.py", Run: "python3 main.py"},
+		InteractorTimeout: 2 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.MaxRSSKb != 2048 || res.OOMKills != 1 || res.InteractorExit != 2 || res.Stdout != "ok" || !res.OutputExceeded {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+
+	run := b.execs[len(b.execs)-1].args
+	if flag(run, "--phase") != "interact" || flag(run, "--user") != "65534:65534" || flag(run, "--home") != "/tmp" ||
+		flag(run, "--memory-mb") != "64" || flag(run, "--interactor-dir") != "/judge/interactors/p1-abc" ||
+		flag(run, "--output-limit") != "1024" {
+		t.Fatalf("unexpected run args: %v", run)
+	}
+	files := run[slices.Index(run, "--")+1:]
+	if !slices.Equal(files, []string{"/judge/run/interactor.in", "/judge/run/interactor.ans"}) {
+		t.Fatalf("unexpected program args: %v", files)
+	}
+}
+
+func TestRunInteractiveAcrossWorkers(t *testing.T) {
+	b := &fakeBackend{
+		stats: "max_rss_kb=2048\noom_kills=0\n",
+		talk: func(workerID string, stdin io.Reader, stdout io.Writer) Result {
+			line := make([]byte, 3)
+			if workerID == "worker" {
+				io.WriteString(stdout, "42\n")
+				io.ReadFull(stdin, line)
+				return Result{Stderr: "got " + string(line)}
+			}
+			io.ReadFull(stdin, line)
+			io.WriteString(stdout, "ok\n")
+			return Result{Stderr: "guessed " + string(line), ExitCode: 0}
+		},
+	}
+	s := &runnerSandbox{backend: b, profile: testProfile}
+
+	res, err := s.Run(context.Background(), "worker", RunRequest{
+		Program:           Program{SourceFile: "main.py", Run: "python3 main.py"},
+		Files:             []File{{Name: "interactor.in", Data: "42"}},
+		Timeout:           time.Second,
+		OutputLimit:       1024,
+		Interactor:        &Program{Name: "interactors/p1-abc", SourceFile: "main.cpp", Run: "./app.bin"},
+		InteractorWorker:  "setter",
+		InteractorTimeout: 2 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Stdout != "guessed 42\n" || res.Stderr != "got ok\n" || res.InteractorExit != 0 || res.MaxRSSKb != 2048 || res.OutputExceeded {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+
+	// The judge files go to the interactor's worker only, and each program
+	// runs in its own.
+	runs := map[string][]string{}
+	for _, e := range b.execs {
+		switch flag(e.args, "--phase") {
+		case "write":
+			if e.workerID != "setter" {
+				t.Fatalf("judge files written to %s", e.workerID)
+			}
+		case "run":
+			runs[e.workerID] = e.args
+		}
+	}
+	program, interactor := runs["worker"], runs["setter"]
+	if flag(program, "--user") != "65534:65534" || flag(program, "--workdir") != "/sandbox" || slices.Contains(program, "/judge/run/interactor.in") {
+		t.Fatalf("unexpected program args: %v", program)
+	}
+	if flag(interactor, "--user") != "" || flag(interactor, "--workdir") != "/judge/interactors/p1-abc" ||
+		interactor[len(interactor)-1] != "/judge/run/interactor.in" {
+		t.Fatalf("unexpected interactor args: %v", interactor)
+	}
+}
+
+func TestRunInteractiveAcrossWorkersOutputLimit(t *testing.T) {
+	b := &fakeBackend{
+		talk: func(workerID string, stdin io.Reader, stdout io.Writer) Result {
+			if workerID == "worker" {
+				if _, err := io.WriteString(stdout, strings.Repeat("x", 100)); err == nil {
+					return Result{}
+				}
+				return Result{ExitCode: 137}
+			}
+			io.Copy(io.Discard, stdin)
+			return Result{ExitCode: 1}
+		},
+	}
+	s := &runnerSandbox{backend: b, profile: testProfile}
+
+	res, err := s.Run(context.Background(), "worker", RunRequest{
+		Program:           Program{SourceFile: "main.py", Run: "python3 main.py"},
+		Timeout:           time.Second,
+		OutputLimit:       10,
+		Interactor:        &Program{Name: "interactors/p1-abc", SourceFile: "main.py", Run: "python3 main.py"},
+		InteractorWorker:  "setter",
+		InteractorTimeout: time.Second,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !res.OutputExceeded || res.ExitCode != 137 || res.InteractorExit != 1 {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+}
+
+func TestRunWithoutStats(t *testing.T) {
+	b := &fakeBackend{}
+	s := &runnerSandbox{backend: b, profile: testProfile}
+
+	res, err := s.Run(context.Background(), "worker", RunRequest{
+		Program: Program{Name: "checkers/p1-abc", SourceFile: "main.py", Run: "python3 main.py"},
+		Timeout: time.Second,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.InteractorExit != -1 {
+		t.Fatalf("interactor exit %d, want -1", res.InteractorExit)
+	}
+
+	run := b.execs[0].args
+	if flag(run, "--user") != "" || flag(run, "--home") != "/judge" || slices.Contains(run, "--memory-mb") {
+		t.Fatalf("unexpected run args: %v", run)
+	}
+}
@@ -0,0 +1,450 @@
+package sandbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/experimental"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+	"github.com/tetratelabs/wazero/sys"
+)
+
+const (
+	wasmBinaryName = "app.wasm"
+	wasmPageSize   = 64 * 1024
+)
+
+// wasmRunDir is where checkers and interactors find their files.
+const wasmRunDir = "/judge/run"
+
+// wasmSandbox builds programs to WebAssembly with the host's toolchains and
+// runs them in-process on wazero, without Docker or root. A submission gets
+// no filesystem and no network, only stdin, stdout and stderr; its memory is
+// capped by refusing to grow its linear memory past the limit and its time by
+// aborting it at the deadline. The compilers themselves run unconfined on the
+// host, like in the local backend.
+type wasmSandbox struct {
+	runtime     wazero.Runtime
+	cache       wazero.CompilationCache
+	dir         string
+	memoryLimit int64
+
+	mu      sync.Mutex
+	workers map[string]*wasmWorker
+}
+
+type wasmWorker struct {
+	dir    string
+	memory int64
+	// modules holds the compiled program of each program directory.
+	modules map[string]wazero.CompiledModule
+}
+
+// NewWasm keeps the workers' files under dir, or in a temporary directory
+// when dir is empty.
+func NewWasm(memoryLimit int64, dir string) (Sandbox, error) {
+	var err error
+	if dir == "" {
+		dir, err = os.MkdirTemp("", "code-checker-wasm-")
+	} else {
+		err = os.MkdirAll(dir, 0700)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create sandbox dir: %w", err)
+	}
+
+	ctx := context.Background()
+	cache := wazero.NewCompilationCache()
+	runtime := wazero.NewRuntimeWithConfig(ctx, wazero.NewRuntimeConfig().
+		WithCompilationCache(cache).
+		WithCloseOnContextDone(true))
+	if _, err := wasi_snapshot_preview1.Instantiate(ctx, runtime); err != nil {
+		runtime.Close(ctx)
+		return nil, fmt.Errorf("failed to instantiate WASI: %w", err)
+	}
+
+	return &wasmSandbox{
+		runtime:     runtime,
+		cache:       cache,
+		dir:         dir,
+		memoryLimit: memoryLimit,
+		workers:     make(map[string]*wasmWorker),
+	}, nil
+}
+
+// Prepare ignores the image: every language is built to WebAssembly.
+func (s *wasmSandbox) Prepare(ctx context.Context, image string, count int) ([]string, error) {
+	workers := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		id := "judge-worker-" + uuid.New().String()
+		w := &wasmWorker{
+			dir:     filepath.Join(s.dir, id),
+			memory:  s.memoryLimit,
+			modules: make(map[string]wazero.CompiledModule),
+		}
+		for _, sub := range []string{"sandbox", "judge/run"} {
+			if err := os.MkdirAll(filepath.Join(w.dir, sub), 0700); err != nil {
+				return nil, fmt.Errorf("failed to create worker dir: %w", err)
+			}
+		}
+
+		s.mu.Lock()
+		s.workers[id] = w
+		s.mu.Unlock()
+		workers = append(workers, id)
+	}
+	return workers, nil
+}
+
+func (s *wasmSandbox) worker(workerID string) (*wasmWorker, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	w, ok := s.workers[workerID]
+	if !ok {
+		return nil, fmt.Errorf("unknown worker %s", workerID)
+	}
+	return w, nil
+}
+
+func (w *wasmWorker) programDir(program Program) string {
+	if program.Name == "" {
+		return filepath.Join(w.dir, "sandbox")
+	}
+	return filepath.Join(w.dir, "judge", filepath.FromSlash(program.Name))
+}
+
+func (s *wasmSandbox) Compile(ctx context.Context, workerID string, req CompileRequest) (*Result, error) {
+	w, err := s.worker(workerID)
+	if err != nil {
+		return nil, err
+	}
+	if req.Program.WasmCompile == "" {
+		return nil, errors.New("language cannot be built to WebAssembly")
+	}
+
+	dir := w.programDir(req.Program)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to write source: %w", err)
+	}
+	for name, data := range req.sources() {
+		if err := CheckPath(name); err != nil {
+			return nil, err
+		}
+		file := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+			return nil, fmt.Errorf("failed to write source: %w", err)
+		}
+		if err := os.WriteFile(file, []byte(data), 0600); err != nil {
+			return nil, fmt.Errorf("failed to write source: %w", err)
+		}
+	}
+
+	buildCtx, cancel := context.WithTimeout(ctx, req.Timeout)
+	defer cancel()
+
+	bin := filepath.Join(dir, wasmBinaryName)
+	stdout := &limitedBuffer{limit: req.OutputLimit}
+	stderr := &limitedBuffer{limit: req.OutputLimit}
+	cmd := exec.CommandContext(buildCtx, "sh", "-c", req.Program.WasmCompile)
+	cmd.Dir = dir
+	cmd.Env = []string{
+		"PATH=" + os.Getenv("PATH"),
+		"HOME=" + filepath.Join(s.dir, "cache"),
+		"SRC=" + req.Program.SourceFile,
+		"BIN=" + bin,
+	}
+	cmd.Env = append(cmd.Env, goModuleEnv()...)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	cmd.WaitDelay = execGrace
+
+	res := &Result{}
+	err = cmd.Run()
+	var exitErr *exec.ExitError
+	switch {
+	case errors.As(err, &exitErr):
+		res.ExitCode = exitErr.ExitCode()
+	case buildCtx.Err() != nil:
+		return nil, buildCtx.Err()
+	case err != nil && !errors.Is(err, errOutputLimitExceeded):
+		return nil, fmt.Errorf("failed to run compiler: %w", err)
+	}
+	res.Stdout, res.Stderr = stdout.String(), stderr.String()
+	if res.ExitCode != 0 {
+		return res, nil
+	}
+
+	wasm, err := os.ReadFile(bin)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read module: %w", err)
+	}
+	module, err := s.runtime.CompileModule(ctx, wasm)
+	if err != nil {
+		res.ExitCode = 1
+		res.Stderr += fmt.Sprintf("invalid WebAssembly module: %v\n", err)
+		return res, nil
+	}
+	if old, ok := w.modules[dir]; ok {
+		old.Close(ctx)
+	}
+	w.modules[dir] = module
+	return res, nil
+}
+
+func (s *wasmSandbox) Run(ctx context.Context, workerID string, req RunRequest) (*Result, error) {
+	w, err := s.worker(workerID)
+	if err != nil {
+		return nil, err
+	}
+	if req.MemoryBytes > 0 {
+		w.memory = req.MemoryBytes
+	}
+
+	module, ok := w.modules[w.programDir(req.Program)]
+	if !ok {
+		return nil, errors.New("program is not compiled")
+	}
+
+	// Judge files go to the worker of the program that reads them, which is
+	// the interactor's when it was compiled in another one.
+	filesWorker := w
+	if req.Interactor != nil && req.InteractorWorker != "" {
+		if filesWorker, err = s.worker(req.InteractorWorker); err != nil {
+			return nil, err
+		}
+	}
+	runDir := filepath.Join(filesWorker.dir, "judge", "run")
+	var args []string
+	for _, file := range req.Files {
+		if err := os.WriteFile(filepath.Join(runDir, file.Name), []byte(file.Data), 0600); err != nil {
+			return nil, fmt.Errorf("failed to write judge files: %w", err)
+		}
+		args = append(args, wasmRunDir+"/"+file.Name)
+	}
+
+	config := wazero.NewModuleConfig().
+		WithSysWalltime().
+		WithSysNanotime().
+		WithSysNanosleep()
+	if req.MemoryLimitMB > 0 {
+		config = config.WithEnv("MEMORY_LIMIT_MB", strconv.FormatInt(req.MemoryLimitMB, 10))
+	}
+	programConfig := config.WithArgs(append([]string{req.Program.SourceFile}, args...)...)
+	if req.Program.Name != "" {
+		programConfig = programConfig.WithFSConfig(wazero.NewFSConfig().WithReadOnlyDirMount(runDir, wasmRunDir))
+	}
+
+	if req.Interactor == nil {
+		stdout := &limitedBuffer{limit: req.OutputLimit}
+		stderr := &limitedBuffer{limit: req.OutputLimit}
+		run := s.runModule(ctx, module, req.Timeout, w.memory, programConfig.WithStdin(strings.NewReader(req.Stdin)), stdout, stderr)
+		return &Result{
+			Stdout:         stdout.String(),
+			Stderr:         stderr.String(),
+			ExitCode:       run.exitCode,
+			OutputExceeded: stdout.exceeded || stderr.exceeded,
+			MaxRSSKb:       run.peakKb,
+			OOMKills:       run.oomKills,
+			InteractorExit: -1,
+		}, nil
+	}
+
+	interactor, ok := filesWorker.modules[filesWorker.programDir(*req.Interactor)]
+	if !ok {
+		return nil, errors.New("interactor is not compiled")
+	}
+	interactorConfig := config.
+		WithArgs(append([]string{req.Interactor.SourceFile}, args...)...).
+		WithFSConfig(wazero.NewFSConfig().WithReadOnlyDirMount(runDir, wasmRunDir))
+	return s.interact(ctx, module, interactor, req, w.memory, programConfig, interactorConfig), nil
+}
+
+// interact wires the program and the interactor together like judge-runner
+// does: the interactor's stderr becomes Stdout, the program's stderr Stderr.
+func (s *wasmSandbox) interact(
+	ctx context.Context,
+	program, interactor wazero.CompiledModule,
+	req RunRequest,
+	memoryBytes int64,
+	programConfig, interactorConfig wazero.ModuleConfig,
+) *Result {
+	programIn, interactorOut := io.Pipe()
+	interactorIn, programOut := io.Pipe()
+	comment := &limitedBuffer{limit: req.OutputLimit}
+	stderr := &limitedBuffer{limit: req.OutputLimit}
+
+	done := make(chan moduleRun, 1)
+	go func() {
+		run := s.runModule(ctx, interactor, req.InteractorTimeout, s.memoryLimit,
+			interactorConfig.WithStdin(interactorIn).WithStdout(interactorOut), nil, comment)
+		interactorOut.Close()
+		interactorIn.Close()
+		done <- run
+	}()
+
+	// The program's output to the interactor counts against the output limit
+	// too; going past it kills the program.
+	programCtx, kill := context.WithCancel(ctx)
+	defer kill()
+	toInteractor := &limitedPipe{w: programOut, limit: req.OutputLimit, kill: kill}
+	run := s.runModule(programCtx, program, req.Timeout, memoryBytes,
+		programConfig.WithStdin(programIn).WithStdout(toInteractor), nil, stderr)
+	programOut.Close()
+	programIn.Close()
+	interactorRun := <-done
+
+	return &Result{
+		Stdout:         comment.String(),
+		Stderr:         stderr.String(),
+		ExitCode:       run.exitCode,
+		OutputExceeded: comment.exceeded || stderr.exceeded || toInteractor.exceeded,
+		MaxRSSKb:       run.peakKb,
+		OOMKills:       run.oomKills,
+		InteractorExit: int64(interactorRun.exitCode),
+	}
+}
+
+type moduleRun struct {
+	exitCode int
+	peakKb   int64
+	oomKills int64
+}
+
+// runModule runs a WASI command to completion. Exit codes follow the other
+// backends: 124 when the time limit is hit and 137 when it was stopped for
+// writing too much. stdout may be nil when config already sets it.
+func (s *wasmSandbox) runModule(
+	ctx context.Context,
+	module wazero.CompiledModule,
+	timeout time.Duration,
+	memoryBytes int64,
+	config wazero.ModuleConfig,
+	stdout, stderr *limitedBuffer,
+) moduleRun {
+	runCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if stdout != nil {
+		config = config.WithStdout(killOnExceed{stdout, cancel})
+	}
+	config = config.WithStderr(killOnExceed{stderr, cancel})
+
+	// The initial memory cannot be refused once instantiation starts.
+	for _, def := range module.ExportedMemories() {
+		if memoryBytes > 0 && uint64(def.Min())*wasmPageSize > uint64(memoryBytes) {
+			return moduleRun{exitCode: 137, peakKb: int64(def.Min()) * wasmPageSize / 1024, oomKills: 1}
+		}
+	}
+
+	memory := &linearMemory{limit: uint64(memoryBytes)}
+	allocCtx := experimental.WithMemoryAllocator(runCtx, experimental.MemoryAllocatorFunc(
+		func(cap, max uint64) experimental.LinearMemory { return memory },
+	))
+
+	// Module names must be unique within the runtime.
+	instance, err := s.runtime.InstantiateModule(allocCtx, module, config.WithName(""))
+	if instance != nil {
+		instance.Close(ctx)
+	}
+
+	run := moduleRun{peakKb: int64(memory.peak / 1024)}
+	if memory.refused {
+		run.oomKills = 1
+	}
+	var exitErr *sys.ExitError
+	switch {
+	case err == nil:
+	case errors.Is(runCtx.Err(), context.DeadlineExceeded):
+		run.exitCode = 124
+	case runCtx.Err() != nil:
+		run.exitCode = 137
+	case errors.As(err, &exitErr):
+		run.exitCode = int(exitErr.ExitCode())
+	default:
+		// A trap, such as an out-of-bounds access or a Go panic.
+		run.exitCode = 134
+		fmt.Fprintf(stderr, "\n%v\n", err)
+	}
+	return run
+}
+
+// linearMemory backs a module's memory and refuses to grow it past limit,
+// which the guest sees as a failed memory.grow.
+type linearMemory struct {
+	buf     []byte
+	limit   uint64
+	peak    uint64
+	refused bool
+}
+
+func (m *linearMemory) Reallocate(size uint64) []byte {
+	if m.limit > 0 && size > m.limit {
+		m.refused = true
+		return nil
+	}
+	if size > uint64(cap(m.buf)) {
+		capacity := max(size, 2*uint64(cap(m.buf)))
+		if m.limit > 0 {
+			capacity = min(capacity, m.limit)
+		}
+		buf := make([]byte, size, capacity)
+		copy(buf, m.buf)
+		m.buf = buf
+	} else {
+		m.buf = m.buf[:size]
+	}
+	m.peak = max(m.peak, size)
+	return m.buf
+}
+
+func (m *linearMemory) Free() {
+	m.buf = nil
+}
+
+// Cleanup forgets the submission and its files; checkers and interactors
+// stay compiled for the next one.
+func (s *wasmSandbox) Cleanup(workerID string) error {
+	w, err := s.worker(workerID)
+	if err != nil {
+		return err
+	}
+
+	sandboxDir := w.programDir(Program{})
+	if module, ok := w.modules[sandboxDir]; ok {
+		module.Close(context.Background())
+		delete(w.modules, sandboxDir)
+	}
+	for _, dir := range []string{sandboxDir, filepath.Join(w.dir, "judge", "run")} {
+		if err := os.RemoveAll(dir); err != nil {
+			return fmt.Errorf("failed to reset worker: %w", err)
+		}
+		if err := os.Mkdir(dir, 0700); err != nil {
+			return fmt.Errorf("failed to reset worker: %w", err)
+		}
+	}
+	w.memory = s.memoryLimit
+	return nil
+}
+
+func (s *wasmSandbox) Close() error {
+	ctx := context.Background()
+	if err := s.runtime.Close(ctx); err != nil {
+		return err
+	}
+	if err := s.cache.Close(ctx); err != nil {
+		return err
+	}
+	return os.RemoveAll(s.dir)
+}
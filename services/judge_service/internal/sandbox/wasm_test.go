@@ -0,0 +1,221 @@
+package sandbox
+
+import (
+	"context"
+	"os/exec"
+	"testing"
+	"time"
+)
+
+// wasmTestProgram does whatever its input asks, so one build covers every case.
+const wasmTestProgram = `package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+func main() {
+	var cmd string
+	var a, b int
+	fmt.Scan(&cmd, &a, &b)
+	switch cmd {
+	case "sum":
+		fmt.Println(a + b)
+	case "loop":
+		for {
+		}
+	case "alloc":
+		buf := make([]byte, a<<20)
+		for i := range buf {
+			buf[i] = 1
+		}
+		fmt.Println(len(buf))
+	case "flood":
+		for {
+			fmt.Print(strings.Repeat("x", 1024))
+		}
+	case "exit":
+		os.Exit(a)
+	case "args":
+		data, err := os.ReadFile(os.Args[1])
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		fmt.Print(string(data))
+	}
+}
+`
+
+func newTestWasm(t *testing.T, program Program) (Sandbox, string) {
+	t.Helper()
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go is not installed")
+	}
+
+	s, err := NewWasm(64*1024*1024, t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create sandbox: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+
+	workers, err := s.Prepare(context.Background(), DefaultImage, 1)
+	if err != nil {
+		t.Fatalf("failed to prepare workers: %v", err)
+	}
+	res, err := s.Compile(context.Background(), workers[0], CompileRequest{
+		Program:     program,
+		Source:      wasmTestProgram,
+		Timeout:     2 * time.Minute,
+		OutputLimit: 64 * 1024,
+	})
+	if err != nil || res.ExitCode != 0 {
+		t.Fatalf("failed to compile: %v %+v", err, res)
+	}
+	return s, workers[0]
+}
+
+func TestWasmRun(t *testing.T) {
+	program := Program{
+		SourceFile:  "main.go",
+		WasmCompile: `go mod init sandbox >/dev/null 2>&1; GOOS=wasip1 GOARCH=wasm go build -o "$BIN" .`,
+	}
+	s, worker := newTestWasm(t, program)
+
+	tests := []struct {
+		name         string
+		stdin        string
+		wantStdout   string
+		wantExit     int
+		wantOOM      bool
+		wantExceeded bool
+	}{
+		{name: "accepted", stdin: "sum 1 2", wantStdout: "3\n"},
+		{name: "exit code", stdin: "exit 3 0", wantExit: 3},
+		{name: "time limit", stdin: "loop 0 0", wantExit: 124},
+		{name: "memory limit", stdin: "alloc 256 0", wantOOM: true},
+		{name: "output limit", stdin: "flood 0 0", wantExit: 137, wantExceeded: true},
+		{name: "no filesystem", stdin: "args 0 0", wantExit: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := s.Run(context.Background(), worker, RunRequest{
+				Program:     program,
+				Stdin:       tt.stdin,
+				Files:       []File{{Name: "input.txt", Data: "secret"}},
+				Timeout:     time.Second,
+				OutputLimit: 64 * 1024,
+			})
+			if err != nil {
+				t.Fatalf("failed to run: %v", err)
+			}
+			if tt.wantOOM {
+				if res.OOMKills == 0 || res.ExitCode == 0 {
+					t.Fatalf("expected the allocation to fail: %+v", res)
+				}
+				return
+			}
+			if res.ExitCode != tt.wantExit || res.OutputExceeded != tt.wantExceeded {
+				t.Fatalf("unexpected result: exit %d, exceeded %v, stderr %q", res.ExitCode, res.OutputExceeded, res.Stderr)
+			}
+			if !tt.wantExceeded && res.Stdout != tt.wantStdout {
+				t.Fatalf("unexpected stdout %q", res.Stdout)
+			}
+			if res.MaxRSSKb == 0 {
+				t.Fatalf("expected memory usage to be reported")
+			}
+		})
+	}
+}
+
+func TestWasmJudgeProgramReadsFiles(t *testing.T) {
+	program := Program{
+		Name:        "checkers/test",
+		SourceFile:  "main.go",
+		WasmCompile: `go mod init checker >/dev/null 2>&1; GOOS=wasip1 GOARCH=wasm go build -o "$BIN" .`,
+	}
+	s, worker := newTestWasm(t, program)
+
+	res, err := s.Run(context.Background(), worker, RunRequest{
+		Program:     program,
+		Stdin:       "args 0 0",
+		Files:       []File{{Name: "answer.txt", Data: "42\n"}},
+		Timeout:     time.Second,
+		OutputLimit: 64 * 1024,
+	})
+	if err != nil {
+		t.Fatalf("failed to run: %v", err)
+	}
+	if res.ExitCode != 0 || res.Stdout != "42\n" {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+
+	if err := s.Cleanup(worker); err != nil {
+		t.Fatalf("failed to clean up: %v", err)
+	}
+	if _, err := s.Run(context.Background(), worker, RunRequest{Program: program, Timeout: time.Second}); err != nil {
+		t.Fatalf("judge program should survive cleanup: %v", err)
+	}
+}
+
+func TestWasmInteractOutputLimit(t *testing.T) {
+	program := Program{
+		SourceFile:  "main.go",
+		WasmCompile: `go mod init sandbox >/dev/null 2>&1; GOOS=wasip1 GOARCH=wasm go build -o "$BIN" .`,
+	}
+	s, worker := newTestWasm(t, program)
+
+	// The interactor asks for a flood and reads whatever comes.
+	interactor := Program{
+		Name:        "interactors/test",
+		SourceFile:  "main.go",
+		WasmCompile: `go mod init interactor >/dev/null 2>&1; GOOS=wasip1 GOARCH=wasm go build -o "$BIN" .`,
+	}
+	// It runs in the program's worker and in one of its own, as a judge
+	// does for an interactor of another image.
+	others, err := s.Prepare(context.Background(), "other-image", 1)
+	if err != nil {
+		t.Fatalf("failed to prepare workers: %v", err)
+	}
+	for _, interactorWorker := range []string{worker, others[0]} {
+		res, err := s.Compile(context.Background(), interactorWorker, CompileRequest{
+			Program: interactor,
+			Source: `package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+func main() {
+	fmt.Println("flood 0 0")
+	io.Copy(io.Discard, os.Stdin)
+}
+`,
+			Timeout:     2 * time.Minute,
+			OutputLimit: 64 * 1024,
+		})
+		if err != nil || res.ExitCode != 0 {
+			t.Fatalf("failed to compile: %v %+v", err, res)
+		}
+
+		res, err = s.Run(context.Background(), worker, RunRequest{
+			Program:           program,
+			Timeout:           time.Second,
+			OutputLimit:       64 * 1024,
+			Interactor:        &interactor,
+			InteractorWorker:  interactorWorker,
+			InteractorTimeout: 2 * time.Second,
+		})
+		if err != nil {
+			t.Fatalf("failed to run: %v", err)
+		}
+		if !res.OutputExceeded || res.ExitCode != 137 {
+			t.Fatalf("expected the program to be stopped at the output limit: exit %d, exceeded %v", res.ExitCode, res.OutputExceeded)
+		}
+	}
+}
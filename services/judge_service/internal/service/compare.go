@@ -0,0 +1,88 @@
+package service
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	comparatorTrim            = "trim"
+	comparatorExact           = "exact"
+	comparatorTokens          = "tokens"
+	comparatorFloat           = "float"
+	comparatorCaseInsensitive = "case_insensitive"
+	comparatorUnorderedLines  = "unordered_lines"
+)
+
+type comparator struct {
+	mode   string
+	absEps float64
+	relEps float64
+}
+
+func (c comparator) equal(expected, actual string) bool {
+	switch c.mode {
+	case comparatorExact:
+		return equalStrings(splitLines(expected), splitLines(actual), func(a, b string) bool { return a == b })
+	case comparatorTokens:
+		return equalStrings(strings.Fields(expected), strings.Fields(actual), func(a, b string) bool { return a == b })
+	case comparatorFloat:
+		return equalStrings(strings.Fields(expected), strings.Fields(actual), c.equalFloatToken)
+	case comparatorCaseInsensitive:
+		return equalStrings(strings.Fields(expected), strings.Fields(actual), strings.EqualFold)
+	case comparatorUnorderedLines:
+		return equalStrings(sortedLines(expected), sortedLines(actual), func(a, b string) bool { return a == b })
+	default:
+		return strings.TrimSpace(expected) == strings.TrimSpace(actual)
+	}
+}
+
+func (c comparator) equalFloatToken(expected, actual string) bool {
+	if expected == actual {
+		return true
+	}
+	want, err := strconv.ParseFloat(expected, 64)
+	if err != nil {
+		return false
+	}
+	got, err := strconv.ParseFloat(actual, 64)
+	if err != nil || math.IsNaN(got) || math.IsInf(got, 0) {
+		return false
+	}
+	diff := math.Abs(want - got)
+	return diff <= c.absEps || diff <= c.relEps*math.Abs(want)
+}
+
+func equalStrings(expected, actual []string, eq func(a, b string) bool) bool {
+	if len(expected) != len(actual) {
+		return false
+	}
+	for i := range expected {
+		if !eq(expected[i], actual[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func splitLines(s string) []string {
+	lines := strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+func sortedLines(s string) []string {
+	var lines []string
+	for _, line := range splitLines(s) {
+		line = strings.TrimRight(line, " \t")
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	sort.Strings(lines)
+	return lines
+}
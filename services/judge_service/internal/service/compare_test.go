@@ -0,0 +1,45 @@
+package service
+
+import "testing"
+
+func TestComparatorEqual(t *testing.T) {
+	tests := []struct {
+		name     string
+		cmp      comparator
+		expected string
+		actual   string
+		want     bool
+	}{
+		{"trim equal", comparator{mode: comparatorTrim}, "1 2\n", "  1 2  \n\n", true},
+		{"trim inner spaces", comparator{mode: comparatorTrim}, "1 2", "1  2", false},
+		{"unknown mode falls back to trim", comparator{mode: ""}, "ok\n", "ok", true},
+
+		{"exact equal", comparator{mode: comparatorExact}, "a\nb\n", "a\r\nb\r\n\n", true},
+		{"exact trailing space", comparator{mode: comparatorExact}, "a\nb", "a \nb", false},
+		{"exact leading blank line", comparator{mode: comparatorExact}, "a", "\na", false},
+
+		{"tokens equal", comparator{mode: comparatorTokens}, "1 2\n3", "1\n2   3\n", true},
+		{"tokens differ", comparator{mode: comparatorTokens}, "1 2 3", "1 2", false},
+
+		{"float within abs", comparator{mode: comparatorFloat, absEps: 1e-6}, "0.333333", "0.3333334", true},
+		{"float outside abs", comparator{mode: comparatorFloat, absEps: 1e-6}, "0.333333", "0.3334", false},
+		{"float within rel", comparator{mode: comparatorFloat, relEps: 1e-6}, "1000000000", "1000000500", true},
+		{"float words", comparator{mode: comparatorFloat, absEps: 1e-6}, "YES 1.0", "YES 1.0000001", true},
+		{"float words differ", comparator{mode: comparatorFloat, absEps: 1e-6}, "YES 1.0", "NO 1.0", false},
+		{"float nan", comparator{mode: comparatorFloat, absEps: 1}, "1", "NaN", false},
+
+		{"case insensitive", comparator{mode: comparatorCaseInsensitive}, "Yes\nNO", "yes no", true},
+		{"case insensitive differ", comparator{mode: comparatorCaseInsensitive}, "yes", "yep", false},
+
+		{"unordered lines", comparator{mode: comparatorUnorderedLines}, "a\nb\nc\n", "c\na \n\nb", true},
+		{"unordered lines duplicates", comparator{mode: comparatorUnorderedLines}, "a\na\nb", "a\nb\nb", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cmp.equal(tt.expected, tt.actual); got != tt.want {
+				t.Fatalf("equal(%q, %q) = %v, want %v", tt.expected, tt.actual, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package service
+
+import (
+	"fmt"
+	"go/parser"
+	"go/token"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/DeadlyParkour777/code-checker/services/judge_service/internal/sandbox"
+)
+
+// defaultGoModule is the module the Go compile command creates for
+// submissions that come without a go.mod.
+const defaultGoModule = "sandbox"
+
+func isGoProgram(program sandbox.Program) bool {
+	return path.Ext(program.SourceFile) == ".go"
+}
+
+// disallowedGoImports lists the imports of a Go submission that come neither
+// from the standard library, the submission's own module nor one of the
+// modules the problem allows. Workers have no network, so go mod tidy could
+// not fetch them anyway, and its error would not say why. Files that do not
+// parse are left to the compiler.
+//
+// The module path comes from the submission, so an import only counts as its
+// own when the package directory is in the submission: "module golang.org"
+// does not make golang.org/x/exp its own. Every module the problem may allow
+// sits in one proxy, so replace directives pointing elsewhere are checked
+// too.
+func disallowedGoImports(source string, files []sandbox.File, program sandbox.Program, allowed []string) []string {
+	module := defaultGoModule
+	sources := map[string]string{program.SourceFile: source}
+	var replacements []string
+	for _, f := range files {
+		switch {
+		case path.Base(f.Name) == "go.mod" || path.Base(f.Name) == "go.work":
+			if m := goModulePath(f.Data); m != "" && f.Name == "go.mod" {
+				module = m
+			}
+			replacements = append(replacements, goReplacements(f.Data)...)
+		case path.Ext(f.Name) == ".go":
+			sources[f.Name] = f.Data
+		}
+	}
+
+	packageDirs := make(map[string]bool)
+	for name := range sources {
+		packageDirs[path.Dir(name)] = true
+	}
+
+	var disallowed []string
+	add := func(importPath string) {
+		if !slices.Contains(disallowed, importPath) {
+			disallowed = append(disallowed, importPath)
+		}
+	}
+	fset := token.NewFileSet()
+	for name, src := range sources {
+		file, err := parser.ParseFile(fset, name, src, parser.ImportsOnly)
+		if err != nil {
+			continue
+		}
+		for _, spec := range file.Imports {
+			importPath, err := strconv.Unquote(spec.Path.Value)
+			if err != nil || goImportAllowed(importPath, module, packageDirs, allowed) {
+				continue
+			}
+			add(importPath)
+		}
+	}
+	for _, target := range replacements {
+		if !goImportAllowed(target, "", nil, allowed) {
+			add(target)
+		}
+	}
+	slices.Sort(disallowed)
+	return disallowed
+}
+
+func goImportAllowed(importPath, module string, packageDirs map[string]bool, allowed []string) bool {
+	// Standard library paths have no dot in their first element.
+	first, _, _ := strings.Cut(importPath, "/")
+	if !strings.Contains(first, ".") {
+		return true
+	}
+	if importPath == module {
+		return packageDirs["."]
+	}
+	if dir, ok := strings.CutPrefix(importPath, module+"/"); ok && module != "" {
+		return packageDirs[dir]
+	}
+	for _, m := range allowed {
+		if importPath == m || strings.HasPrefix(importPath, m+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func goModulePath(gomod string) string {
+	for _, line := range strings.Split(gomod, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || fields[0] != "module" {
+			continue
+		}
+		if unquoted, err := strconv.Unquote(fields[1]); err == nil {
+			return unquoted
+		}
+		return fields[1]
+	}
+	return ""
+}
+
+// goReplacements returns the module paths that replace directives of a
+// go.mod or go.work point to. Local directories are left out: their code is
+// part of the submission.
+func goReplacements(gomod string) []string {
+	var targets []string
+	inBlock := false
+	for _, line := range strings.Split(gomod, "\n") {
+		line, _, _ = strings.Cut(line, "//")
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) == 0:
+			continue
+		case inBlock && fields[0] == ")":
+			inBlock = false
+			continue
+		case fields[0] == "replace" && len(fields) == 2 && fields[1] == "(":
+			inBlock = true
+			continue
+		case fields[0] == "replace":
+			fields = fields[1:]
+		case !inBlock:
+			continue
+		}
+
+		arrow := slices.Index(fields, "=>")
+		if arrow < 0 || arrow+1 >= len(fields) {
+			continue
+		}
+		target := fields[arrow+1]
+		if unquoted, err := strconv.Unquote(target); err == nil {
+			target = unquoted
+		}
+		if !strings.HasPrefix(target, "./") && !strings.HasPrefix(target, "../") && !path.IsAbs(target) {
+			targets = append(targets, target)
+		}
+	}
+	return targets
+}
+
+func goImportsMessage(disallowed, allowed []string) string {
+	msg := fmt.Sprintf("Compilation Error: imports not allowed for this problem: %s. ", strings.Join(disallowed, ", "))
+	if len(allowed) == 0 {
+		return msg + "Only the standard library is available."
+	}
+	return msg + "Allowed modules: " + strings.Join(allowed, ", ") + "."
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/DeadlyParkour777/code-checker/services/judge_service/internal/sandbox"
+)
+
+func TestDisallowedGoImports(t *testing.T) {
+	program := sandbox.Program{SourceFile: "main.go"}
+	source := `package main
+
+import (
+	"fmt"
+
+	"example.com/app/internal/calc"
+	"github.com/google/uuid"
+	"gonum.org/v1/gonum/mat"
+)
+`
+	files := []sandbox.File{
+		{Name: "go.mod", Data: "module example.com/app\n\ngo 1.24\n"},
+		{Name: "internal/calc/calc.go", Data: "package calc\n\nimport \"golang.org/x/exp/constraints\"\n"},
+		{Name: "broken.go", Data: "package main\n\nimport \"github.com/broken/import"},
+	}
+
+	tests := []struct {
+		name    string
+		allowed []string
+		want    []string
+	}{
+		{"standard library only", nil, []string{"github.com/google/uuid", "golang.org/x/exp/constraints", "gonum.org/v1/gonum/mat"}},
+		{"allowed modules", []string{"gonum.org/v1/gonum", "golang.org/x/exp"}, []string{"github.com/google/uuid"}},
+		{"module prefix is not a path prefix", []string{"gonum.org/v1/gonu"}, []string{"github.com/google/uuid", "golang.org/x/exp/constraints", "gonum.org/v1/gonum/mat"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := disallowedGoImports(source, files, program, tt.allowed)
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	// Without a go.mod the submission's own packages live in the default module.
+	got := disallowedGoImports("package main\n\nimport \"sandbox/util\"\n", nil, program, nil)
+	if len(got) != 0 {
+		t.Fatalf("expected the default module to be allowed, got %q", got)
+	}
+
+	// The module path is the submission's to choose, so it only covers
+	// packages the submission has.
+	bypass := []sandbox.File{{Name: "go.mod", Data: "module golang.org\n\ngo 1.24\n"}, {Name: "util/util.go", Data: "package util\n"}}
+	got = disallowedGoImports("package main\n\nimport (\n\t\"golang.org/util\"\n\t\"golang.org/x/exp/slices\"\n)\n", bypass, program, nil)
+	if !slices.Equal(got, []string{"golang.org/x/exp/slices"}) {
+		t.Fatalf("expected the module path not to allow golang.org/x/exp, got %q", got)
+	}
+
+	replaced := []sandbox.File{{Name: "go.mod", Data: "module app\n\nreplace (\n\tgonum.org/v1/gonum => golang.org/x/exp v0.0.0-20231110203233-9a3e6036ecaa // swapped\n\tapp/local => ./local\n)\n"}}
+	got = disallowedGoImports("package main\n\nimport \"gonum.org/v1/gonum/mat\"\n", replaced, program, []string{"gonum.org/v1/gonum"})
+	if !slices.Equal(got, []string{"golang.org/x/exp"}) {
+		t.Fatalf("expected the replacement to be checked, got %q", got)
+	}
+	work := []sandbox.File{{Name: "go.work", Data: "go 1.24\n\nreplace gonum.org/v1/gonum v0.15.1 => github.com/google/uuid v1.6.0\n"}}
+	got = disallowedGoImports("package main\n", work, program, []string{"gonum.org/v1/gonum"})
+	if !slices.Equal(got, []string{"github.com/google/uuid"}) {
+		t.Fatalf("expected the go.work replacement to be checked, got %q", got)
+	}
+
+	msg := goImportsMessage([]string{"github.com/google/uuid"}, nil)
+	if !strings.Contains(msg, "github.com/google/uuid") || !strings.Contains(msg, "standard library") {
+		t.Fatalf("unexpected message %q", msg)
+	}
+}
@@ -0,0 +1,148 @@
+package service
+
+import (
+	problempb "github.com/DeadlyParkour777/code-checker/pkg/problem"
+	ty "github.com/DeadlyParkour777/code-checker/services/judge_service/internal/types"
+)
+
+const (
+	scoringModePoints = "points"
+
+	groupAggregationMin = "min"
+)
+
+// scoreBoard adds up the score of a submission in points mode. Tests outside
+// a group are worth their own points, scaled by the share the test earned. A
+// group is worth its score, scaled by its aggregation, and is skipped unless
+// every group it depends on was passed in full.
+type scoreBoard struct {
+	groups     map[string]*groupScore
+	order      []*groupScore
+	score      float64
+	maxScore   float64
+	testPoints map[int]float64
+}
+
+type groupScore struct {
+	group    *problempb.TestGroup
+	minRatio float64
+	skipped  bool
+}
+
+func newScoreBoard(testCases []*problempb.TestCase, groups []*problempb.TestGroup) *scoreBoard {
+	b := &scoreBoard{
+		groups:     make(map[string]*groupScore, len(groups)),
+		testPoints: make(map[int]float64),
+	}
+	for _, group := range groups {
+		gs := &groupScore{group: group, minRatio: 1}
+		b.groups[group.GetId()] = gs
+		b.order = append(b.order, gs)
+		b.maxScore += float64(group.GetScore())
+	}
+	for i, tc := range testCases {
+		if _, ok := b.groups[tc.GetGroupId()]; !ok {
+			b.testPoints[i] = float64(testPoints(tc))
+			b.maxScore += b.testPoints[i]
+		}
+	}
+	return b
+}
+
+// runOrder lists test indexes with ungrouped tests first, then the tests of
+// each group in group order, so dependencies are judged before dependants.
+func (b *scoreBoard) runOrder(testCases []*problempb.TestCase) []int {
+	byGroup := make(map[string][]int)
+	var order []int
+	for i, tc := range testCases {
+		if _, ok := b.groups[tc.GetGroupId()]; ok {
+			byGroup[tc.GetGroupId()] = append(byGroup[tc.GetGroupId()], i)
+		} else {
+			order = append(order, i)
+		}
+	}
+	for _, gs := range b.order {
+		order = append(order, byGroup[gs.group.GetId()]...)
+	}
+	return order
+}
+
+// shouldRun reports whether the test can still change the score.
+func (b *scoreBoard) shouldRun(tc *problempb.TestCase) bool {
+	gs, ok := b.groups[tc.GetGroupId()]
+	if !ok {
+		return true
+	}
+	if gs.skipped || !b.dependenciesPassed(gs) {
+		gs.skipped = true
+		return false
+	}
+	if gs.group.GetAggregation() == groupAggregationMin {
+		return gs.minRatio > 0
+	}
+	return gs.minRatio == 1
+}
+
+func (b *scoreBoard) dependenciesPassed(gs *groupScore) bool {
+	for _, dep := range gs.group.GetDependsOn() {
+		if d, ok := b.groups[dep]; ok && (d.skipped || d.minRatio < 1) {
+			return false
+		}
+	}
+	return true
+}
+
+// record adds a test that earned the given share of its points.
+func (b *scoreBoard) record(index int, tc *problempb.TestCase, ratio float64) {
+	if gs, ok := b.groups[tc.GetGroupId()]; ok {
+		if ratio < gs.minRatio {
+			gs.minRatio = ratio
+		}
+		return
+	}
+	b.score += b.testPoints[index] * ratio
+}
+
+func (b *scoreBoard) total() (score, maxScore float64) {
+	score = b.score
+	for _, gs := range b.order {
+		if !b.dependenciesPassed(gs) {
+			gs.skipped = true
+		}
+		score += gs.points()
+	}
+	return score, b.maxScore
+}
+
+func (gs *groupScore) points() float64 {
+	if gs.skipped {
+		return 0
+	}
+	if gs.group.GetAggregation() == groupAggregationMin {
+		return float64(gs.group.GetScore()) * gs.minRatio
+	}
+	if gs.minRatio < 1 {
+		return 0
+	}
+	return float64(gs.group.GetScore())
+}
+
+// testShare is the share of its points a judged test earned: all of them when
+// accepted, none on a failure, or what the checker gave for a partially
+// correct answer.
+func testShare(result *ty.TestResult) float64 {
+	switch result.Status {
+	case "AC":
+		return 1
+	case "PC":
+		return result.Share
+	}
+	return 0
+}
+
+func testPoints(testCase *problempb.TestCase) int32 {
+	if testCase.GetPoints() <= 0 {
+		return 1
+	}
+	return testCase.GetPoints()
+}
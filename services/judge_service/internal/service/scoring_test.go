@@ -0,0 +1,94 @@
+package service
+
+import (
+	"testing"
+
+	problempb "github.com/DeadlyParkour777/code-checker/pkg/problem"
+)
+
+func TestScoreBoard(t *testing.T) {
+	groups := []*problempb.TestGroup{
+		{Id: "g1", Score: 20},
+		{Id: "g2", Score: 30, Aggregation: groupAggregationMin, DependsOn: []string{"g1"}},
+		{Id: "g3", Score: 50, DependsOn: []string{"g2"}},
+	}
+	testCases := []*problempb.TestCase{
+		{Id: "t1", GroupId: "g2"},
+		{Id: "t2", GroupId: "g1"},
+		{Id: "t3", Points: 5},
+		{Id: "t4", GroupId: "g3"},
+		{Id: "t5", GroupId: "g1"},
+		{Id: "t6", GroupId: "deleted"},
+	}
+
+	tests := []struct {
+		name      string
+		shares    map[string]float64
+		wantRun   []string
+		wantScore float64
+	}{
+		{
+			name:      "all passed",
+			wantRun:   []string{"t3", "t6", "t2", "t5", "t1", "t4"},
+			wantScore: 106,
+		},
+		{
+			name:      "failed group stops and blocks dependants",
+			shares:    map[string]float64{"t2": 0},
+			wantRun:   []string{"t3", "t6", "t2"},
+			wantScore: 6,
+		},
+		{
+			name:      "failed dependency skips transitively",
+			shares:    map[string]float64{"t1": 0, "t3": 0},
+			wantRun:   []string{"t3", "t6", "t2", "t5", "t1"},
+			wantScore: 21,
+		},
+		{
+			name:      "partial points scale min groups and lone tests",
+			shares:    map[string]float64{"t1": 0.5, "t3": 0.4},
+			wantRun:   []string{"t3", "t6", "t2", "t5", "t1"},
+			wantScore: 38,
+		},
+		{
+			name:      "partial points fail all or nothing groups",
+			shares:    map[string]float64{"t5": 0.9},
+			wantRun:   []string{"t3", "t6", "t2", "t5"},
+			wantScore: 6,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			board := newScoreBoard(testCases, groups)
+
+			var ran []string
+			for _, i := range board.runOrder(testCases) {
+				tc := testCases[i]
+				if !board.shouldRun(tc) {
+					continue
+				}
+				ran = append(ran, tc.GetId())
+				share, ok := tt.shares[tc.GetId()]
+				if !ok {
+					share = 1
+				}
+				board.record(i, tc, share)
+			}
+
+			if len(ran) != len(tt.wantRun) {
+				t.Fatalf("ran %v, want %v", ran, tt.wantRun)
+			}
+			for i := range ran {
+				if ran[i] != tt.wantRun[i] {
+					t.Fatalf("ran %v, want %v", ran, tt.wantRun)
+				}
+			}
+
+			score, maxScore := board.total()
+			if score != tt.wantScore || maxScore != 106 {
+				t.Fatalf("score %g/%g, want %g/106", score, maxScore, tt.wantScore)
+			}
+		})
+	}
+}
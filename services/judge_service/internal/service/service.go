@@ -1,106 +1,120 @@
 package service
 
 import (
-	"bytes"
 	"context"
 	"crypto/sha256"
-	_ "embed"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"log"
-	"os"
-	"path/filepath"
+	"path"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
+	"unicode/utf8"
 
 	problempb "github.com/DeadlyParkour777/code-checker/pkg/problem"
+	"github.com/DeadlyParkour777/code-checker/services/judge_service/internal/deadletter"
+	"github.com/DeadlyParkour777/code-checker/services/judge_service/internal/languages"
+	"github.com/DeadlyParkour777/code-checker/services/judge_service/internal/sandbox"
+	"github.com/DeadlyParkour777/code-checker/services/judge_service/internal/testcache"
 	ty "github.com/DeadlyParkour777/code-checker/services/judge_service/internal/types"
-	"github.com/docker/docker/api/types"
-	"github.com/docker/docker/api/types/container"
-	"github.com/docker/docker/api/types/filters"
-	"github.com/docker/docker/api/types/mount"
-	"github.com/docker/docker/api/types/volume"
-	"github.com/docker/docker/client"
-	"github.com/docker/docker/errdefs"
-	"github.com/docker/docker/pkg/archive"
-	"github.com/docker/docker/pkg/stdcopy"
-	"github.com/google/uuid"
 	"github.com/segmentio/kafka-go"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 )
 
-type LanguageConfig struct {
-	CodeFileName string
-}
-
-var languageConfigs = map[string]LanguageConfig{
-	"go": {
-		CodeFileName: "main.go",
-	},
-	"python": {
-		CodeFileName: "main.py",
-	},
-}
-
 const (
-	buildTimeout = 120 * time.Second
+	buildTimeout          = 120 * time.Second
+	checkerTimeout        = 10 * time.Second
+	maxStderrBytes        = 4 * 1024
+	maxCompileOutputBytes = 1024 * 1024
+	jvmOutOfMemoryError   = "java.lang.OutOfMemoryError"
 )
 
-const runtimeImage = "code-checker-judge-runtime:latest"
-const workVolume = "submissions-data"
-const workerLabelKey = "code-checker.worker"
-const workerLabelValue = "judge"
-const runtimeLabelKey = "code-checker.runtime.sha256"
-
-//go:embed runtime/Dockerfile
-var runtimeDockerfile []byte
-
-//go:embed runtime/runner.sh
-var runtimeRunnerScript []byte
-
 type Service interface {
 	ProcessSubmission(ctx context.Context, submission *ty.SubmissionEvent) error
+	DeadLetter(ctx context.Context, msg kafka.Message, submission *ty.SubmissionEvent, cause error, attempts int) error
+	ListDeadLetters(ctx context.Context, limit int) ([]deadletter.Letter, error)
+	ReplayDeadLetter(ctx context.Context, offset int64) (*deadletter.Letter, error)
+	ListLanguages(ctx context.Context) []languages.Language
+	HandleProblemEvent(ctx context.Context, event *ty.ProblemEvent)
+}
+
+// messageWriter is the part of *kafka.Writer the service uses.
+type messageWriter interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
 }
 
 type service struct {
-	kafkaProducer *kafka.Writer
-	dockerClient  *client.Client
+	kafkaProducer      messageWriter
+	progressProducer   messageWriter
+	submissionProducer messageWriter
+	deadLetters        deadletter.Queue
+	timeout            time.Duration
+	memoryLimit        int64
+	compileMemoryLimit int64
+	outputLimit        int64
+	languages          *languages.Registry
+	workerPools        map[string]chan string
+	problemClient      problempb.ProblemServiceClient
+	testCache          *testcache.Cache
+	sandbox            sandbox.Sandbox
+	// programs remembers which checkers and interactors each worker has built.
+	programs sync.Map
+	// replayMu keeps two replays of one letter from both queuing it.
+	replayMu sync.Mutex
+}
+
+const problemTypeInteractive = "interactive"
+
+const (
+	programKindChecker    = "checker"
+	programKindInteractor = "interactor"
+)
+
+type limits struct {
 	timeout       time.Duration
-	workDir       string
-	workerPool    chan string
-	problemClient problempb.ProblemServiceClient
+	memoryBytes   int64
+	memoryLimitMB int64
+	outputBytes   int64
 }
 
 func NewService(
 	producer *kafka.Writer,
+	progressProducer *kafka.Writer,
+	submissionProducer *kafka.Writer,
+	deadLetters deadletter.Queue,
+	registry *languages.Registry,
 	timeout time.Duration,
-	workDir string,
+	memoryLimitMB int,
+	compileMemoryLimitMB int,
+	outputLimitKB int,
 	workerCount int,
+	sb sandbox.Sandbox,
 	problemServiceAddr string,
+	testCache *testcache.Cache,
 ) Service {
-	dockerCli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
-	if err != nil {
-		log.Fatalf("Failed to create docker client: %v", err)
-	}
-
-	if err := ensureRuntimeImage(dockerCli, runtimeImage); err != nil {
-		log.Fatalf("Failed to ensure runtime image: %v", err)
-	}
-
-	if err := ensureWorkVolume(dockerCli, workVolume); err != nil {
-		log.Fatalf("Failed to ensure work volume: %v", err)
-	}
-
-	if err := cleanupWorkerContainers(dockerCli); err != nil {
-		log.Printf("Failed to cleanup old worker containers: %v", err)
-	}
+	workerPools := make(map[string]chan string)
+	for _, lang := range registry.List() {
+		imageName := imageFor(lang)
+		if _, ok := workerPools[imageName]; ok {
+			continue
+		}
 
-	workerPool := make(chan string, workerCount)
-	if err := createWorkerContainers(dockerCli, runtimeImage, workVolume, workDir, workerCount, workerPool); err != nil {
-		log.Fatalf("Failed to create worker containers: %v", err)
+		workers, err := sb.Prepare(context.Background(), imageName, workerCount)
+		if err != nil {
+			log.Fatalf("Failed to create workers for %s: %v", imageName, err)
+		}
+		pool := make(chan string, workerCount)
+		for _, id := range workers {
+			pool <- id
+		}
+		workerPools[imageName] = pool
 	}
 
 	conn, err := grpc.NewClient(problemServiceAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
@@ -110,194 +124,229 @@ func NewService(
 	problemClient := problempb.NewProblemServiceClient(conn)
 
 	return &service{
-		kafkaProducer: producer,
-		dockerClient:  dockerCli,
-		timeout:       timeout,
-		workDir:       workDir,
-		workerPool:    workerPool,
-		problemClient: problemClient,
+		kafkaProducer:      producer,
+		progressProducer:   progressProducer,
+		submissionProducer: submissionProducer,
+		deadLetters:        deadLetters,
+		timeout:            timeout,
+		memoryLimit:        int64(memoryLimitMB) * 1024 * 1024,
+		compileMemoryLimit: int64(compileMemoryLimitMB) * 1024 * 1024,
+		outputLimit:        int64(outputLimitKB) * 1024,
+		languages:          registry,
+		workerPools:        workerPools,
+		problemClient:      problemClient,
+		testCache:          testCache,
+		sandbox:            sb,
 	}
 }
 
-func ensureRuntimeImage(dockerCli *client.Client, imageName string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
-	defer cancel()
-
-	dockerfileData := normalizeLineEndings(runtimeDockerfile)
-	runnerData := normalizeLineEndings(runtimeRunnerScript)
-	signature := runtimeSignature(dockerfileData, runnerData)
-
-	img, _, err := dockerCli.ImageInspectWithRaw(ctx, imageName)
-	if err == nil {
-		if img.Config != nil && img.Config.Labels != nil {
-			if img.Config.Labels[runtimeLabelKey] == signature {
-				return nil
-			}
-		}
-	} else if !errdefs.IsNotFound(err) {
-		return fmt.Errorf("image inspect failed: %w", err)
+func imageFor(lang languages.Language) string {
+	if lang.Image == "" {
+		return sandbox.DefaultImage
 	}
+	return lang.Image
+}
 
-	tempDir, err := os.MkdirTemp("", "judge-runtime-")
-	if err != nil {
-		return fmt.Errorf("failed to create temp dir: %w", err)
-	}
-	defer os.RemoveAll(tempDir)
-
-	dockerfileWithLabel := appendRuntimeLabel(dockerfileData, signature)
-	if err := os.WriteFile(filepath.Join(tempDir, "Dockerfile"), dockerfileWithLabel, 0644); err != nil {
-		return fmt.Errorf("failed to write runtime Dockerfile: %w", err)
-	}
-	if err := os.WriteFile(filepath.Join(tempDir, "runner.sh"), runnerData, 0755); err != nil {
-		return fmt.Errorf("failed to write runtime runner: %w", err)
+// programFor describes the submission, or a checker or interactor kept under
+// name, written in lang.
+func programFor(lang languages.Language, name string) sandbox.Program {
+	return sandbox.Program{
+		Name:        name,
+		SourceFile:  lang.SourceFile,
+		Compile:     lang.Compile,
+		Run:         lang.Run,
+		WasmCompile: lang.WasmCompile,
 	}
+}
 
-	buildContext, err := archive.TarWithOptions(tempDir, &archive.TarOptions{})
-	if err != nil {
-		return fmt.Errorf("failed to create tar archive: %w", err)
-	}
-	defer buildContext.Close()
+func (s *service) ProcessSubmission(ctx context.Context, submission *ty.SubmissionEvent) error {
+	log.Printf("Started processing submission %s", submission.SubmissionID)
 
-	resp, err := dockerCli.ImageBuild(ctx, buildContext, types.ImageBuildOptions{
-		Dockerfile:  "Dockerfile",
-		Tags:        []string{imageName},
-		Remove:      true,
-		ForceRemove: true,
-	})
-	if err != nil {
-		return fmt.Errorf("failed to build runtime image: %w", err)
+	var result *ty.ResultEvent
+	lang, ok := s.languages.Get(submission.Language)
+	if !ok {
+		result = &ty.ResultEvent{
+			SubmissionID: submission.SubmissionID,
+			Status:       "RE",
+			Message:      "Unsupported language",
+		}
+	} else {
+		image := imageFor(lang)
+		workerID := s.acquireWorker(image)
+		defer s.releaseWorker(image, workerID)
+
+		var err error
+		result, err = s.judge(ctx, submission, lang, workerID)
+		if ctx.Err() != nil {
+			// Judging was aborted by a shutdown; the submission stays
+			// uncommitted and is judged again after a restart.
+			return fmt.Errorf("judging of submission %s aborted: %w", submission.SubmissionID, ctx.Err())
+		}
+		if err != nil {
+			// Not the submission's fault: the caller retries it and, if
+			// that keeps failing, gives up with DeadLetter.
+			return fmt.Errorf("failed to judge submission %s: %w", submission.SubmissionID, err)
+		}
 	}
-	defer resp.Body.Close()
 
-	if err := parseBuildErrors(resp.Body); err != nil {
+	if err := s.publishResult(ctx, result); err != nil {
 		return err
 	}
 
+	log.Printf("Finished processing submission %s with status %s", submission.SubmissionID, result.Status)
 	return nil
 }
 
-func ensureWorkVolume(dockerCli *client.Client, volumeName string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
+// acquireWorker takes a worker for image from its pool.
+func (s *service) acquireWorker(image string) string {
+	return <-s.workerPools[image]
+}
 
-	_, err := dockerCli.VolumeInspect(ctx, volumeName)
-	if err == nil {
-		return nil
-	}
-	if !errdefs.IsNotFound(err) {
-		return fmt.Errorf("volume inspect failed: %w", err)
+// releaseWorker resets a worker and puts it back into its pool.
+func (s *service) releaseWorker(image, workerID string) {
+	if err := s.sandbox.Cleanup(workerID); err != nil {
+		log.Printf("Failed to reset worker %s: %v", workerID, err)
 	}
+	s.workerPools[image] <- workerID
+}
 
-	_, err = dockerCli.VolumeCreate(ctx, volume.CreateOptions{Name: volumeName})
+func (s *service) publishResult(ctx context.Context, result *ty.ResultEvent) error {
+	err := s.kafkaProducer.WriteMessages(ctx, kafka.Message{Key: []byte(result.SubmissionID), Value: result.Marshal()})
 	if err != nil {
-		return fmt.Errorf("volume create failed: %w", err)
+		log.Printf("Failed to write result for submission %s: %v", result.SubmissionID, err)
+		return err
 	}
 	return nil
 }
 
-func createWorkerContainers(
-	dockerCli *client.Client,
-	imageName string,
-	volumeName string,
-	workDir string,
-	workerCount int,
-	pool chan<- string,
-) error {
-	for i := 0; i < workerCount; i++ {
-		name := fmt.Sprintf("judge-worker-%s-%d", uuid.New().String(), i)
-		cont, err := dockerCli.ContainerCreate(context.Background(), &container.Config{
-			Image: imageName,
-			Cmd:   []string{"sh", "-c", "trap : TERM INT; sleep infinity & wait"},
-			Labels: map[string]string{
-				workerLabelKey: workerLabelValue,
-			},
-		}, &container.HostConfig{
-			Resources:   container.Resources{Memory: 128 * 1024 * 1024, NanoCPUs: int64(0.5 * 1e9)},
-			NetworkMode: "none",
-			Mounts: []mount.Mount{
-				{Type: mount.TypeVolume, Source: volumeName, Target: workDir},
-			},
-		}, nil, nil, name)
-		if err != nil {
-			return fmt.Errorf("failed to create worker container: %w", err)
-		}
-
-		if err := dockerCli.ContainerStart(context.Background(), cont.ID, container.StartOptions{}); err != nil {
-			return fmt.Errorf("failed to start worker container: %w", err)
-		}
-
-		pool <- cont.ID
+// DeadLetter parks a message the judge could not process and, when it is a
+// submission, gives it the SE verdict so it does not stay pending forever.
+func (s *service) DeadLetter(ctx context.Context, msg kafka.Message, submission *ty.SubmissionEvent, cause error, attempts int) error {
+	if err := s.deadLetters.Push(ctx, msg, cause, attempts); err != nil {
+		return err
 	}
-	return nil
+	if submission == nil {
+		return nil
+	}
+	log.Printf("Giving up on submission %s after %d attempts: %v", submission.SubmissionID, attempts, cause)
+	return s.publishResult(ctx, &ty.ResultEvent{
+		SubmissionID: submission.SubmissionID,
+		Status:       ty.StatusSystemError,
+		Message:      fmt.Sprintf("System error: %v", cause),
+	})
 }
 
-func cleanupWorkerContainers(dockerCli *client.Client) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
+func (s *service) ListDeadLetters(ctx context.Context, limit int) ([]deadletter.Letter, error) {
+	return s.deadLetters.List(ctx, limit)
+}
 
-	args := filters.NewArgs()
-	args.Add("label", fmt.Sprintf("%s=%s", workerLabelKey, workerLabelValue))
+// ReplayDeadLetter queues the submission again. Its result is reset to
+// Pending first, which also lets result_service accept progress again. A
+// letter is replayed once; after that it is marked and refused.
+func (s *service) ReplayDeadLetter(ctx context.Context, offset int64) (*deadletter.Letter, error) {
+	s.replayMu.Lock()
+	defer s.replayMu.Unlock()
 
-	containers, err := dockerCli.ContainerList(ctx, container.ListOptions{All: true, Filters: args})
+	letter, err := s.deadLetters.Get(ctx, offset)
 	if err != nil {
-		return fmt.Errorf("list worker containers: %w", err)
+		return nil, err
+	}
+	if letter.Replayed {
+		return nil, deadletter.ErrAlreadyReplayed
 	}
 
-	for _, c := range containers {
-		if err := dockerCli.ContainerRemove(ctx, c.ID, container.RemoveOptions{Force: true}); err != nil {
-			return fmt.Errorf("remove worker container %s: %w", c.ID, err)
-		}
+	var submission ty.SubmissionEvent
+	if err := json.Unmarshal(letter.Value, &submission); err != nil {
+		return nil, fmt.Errorf("dead letter %d is not a submission: %w", offset, err)
+	}
+	if err := s.publishResult(ctx, &ty.ResultEvent{SubmissionID: submission.SubmissionID, Status: ty.StatusPending}); err != nil {
+		return nil, fmt.Errorf("failed to reset result: %w", err)
+	}
+	err = s.submissionProducer.WriteMessages(ctx, kafka.Message{
+		Key:     letter.Key,
+		Value:   letter.Value,
+		Headers: []kafka.Header{{Key: "replay-of", Value: []byte(strconv.FormatInt(offset, 10))}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to requeue submission: %w", err)
+	}
+	if err := s.deadLetters.MarkReplayed(ctx, offset); err != nil {
+		return nil, fmt.Errorf("submission %s was queued again, but: %w", submission.SubmissionID, err)
 	}
+	letter.Replayed = true
 
-	return nil
+	log.Printf("Replayed dead letter %d (submission %s)", offset, submission.SubmissionID)
+	return letter, nil
 }
 
-func (s *service) ProcessSubmission(ctx context.Context, submission *ty.SubmissionEvent) error {
-	log.Printf("Started processing submission %s", submission.SubmissionID)
+func (s *service) ListLanguages(ctx context.Context) []languages.Language {
+	return s.languages.List()
+}
+
+// HandleProblemEvent drops cached tests as soon as they change, instead of
+// keeping them until the next submission sees the new version.
+func (s *service) HandleProblemEvent(ctx context.Context, event *ty.ProblemEvent) {
+	if event.EventType == ty.ProblemEventTestsUpdated && event.ProblemID != "" {
+		s.testCache.Invalidate(event.ProblemID)
+		log.Printf("Invalidated cached tests of problem %s", event.ProblemID)
+	}
+}
 
-	workerID := <-s.workerPool
-	defer func() { s.workerPool <- workerID }()
+func (s *service) testsFor(ctx context.Context, problem *problempb.Problem) (*problempb.GetTestCasesResponse, error) {
+	if tests, ok := s.testCache.Get(problem.GetId(), problem.GetTestsVersion()); ok {
+		return tests, nil
+	}
 
-	result, err := s.judge(ctx, submission, workerID)
+	log.Printf("Fetching test cases for problem %s", problem.GetId())
+	resp, err := s.problemClient.GetTestCases(ctx, &problempb.GetTestCasesRequest{ProblemId: problem.GetId()})
 	if err != nil {
-		result = &ty.ResultEvent{
-			SubmissionID: submission.SubmissionID,
-			Status:       "RE",
-			Message:      err.Error(),
-		}
+		return nil, fmt.Errorf("failed to get test cases: %w", err)
 	}
+	log.Printf("Received %d test cases", len(resp.GetTestCases()))
+	if err := s.loadTestFiles(ctx, resp.GetTestCases()); err != nil {
+		return nil, err
+	}
+	s.testCache.Put(problem.GetId(), problem.GetTestsVersion(), resp)
+	return resp, nil
+}
 
-	err = s.kafkaProducer.WriteMessages(ctx, kafka.Message{Value: result.Marshal()})
+func (s *service) judge(ctx context.Context, submission *ty.SubmissionEvent, lang languages.Language, workerID string) (*ty.ResultEvent, error) {
+	problem, err := s.problemClient.GetProblem(ctx, &problempb.GetProblemRequest{Id: submission.ProblemID})
 	if err != nil {
-		log.Printf("Failed to write result for submission %s: %v", submission.SubmissionID, err)
-		return err
+		return nil, fmt.Errorf("failed to get problem: %w", err)
+	}
+	runLimits := s.limitsFor(problem, lang)
+	cmp := comparator{
+		mode:   problem.GetComparator(),
+		absEps: problem.GetFloatAbsEps(),
+		relEps: problem.GetFloatRelEps(),
 	}
 
-	log.Printf("Finished processing submission %s with status %s", submission.SubmissionID, result.Status)
-	return nil
-}
+	checkerSource, err := s.problemClient.GetChecker(ctx, &problempb.GetProgramRequest{ProblemId: submission.ProblemID})
+	if err != nil && status.Code(err) != codes.NotFound {
+		return nil, fmt.Errorf("failed to get checker: %w", err)
+	}
 
-func (s *service) judge(ctx context.Context, submission *ty.SubmissionEvent, workerID string) (*ty.ResultEvent, error) {
-	langConfig, ok := languageConfigs[submission.Language]
-	if !ok {
-		return &ty.ResultEvent{
-			SubmissionID: submission.SubmissionID,
-			Status:       "RE",
-			Message:      "Unsupported language",
-		}, nil
+	var interactorSource *problempb.Program
+	if problem.GetType() == problemTypeInteractive {
+		interactorSource, err = s.problemClient.GetInteractor(ctx, &problempb.GetProgramRequest{ProblemId: submission.ProblemID})
+		if status.Code(err) == codes.NotFound {
+			return &ty.ResultEvent{
+				SubmissionID: submission.SubmissionID,
+				Status:       "RE",
+				Message:      "Interactive problem has no interactor",
+			}, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get interactor: %w", err)
+		}
 	}
 
-	log.Printf("Fetching test cases for problem %s", submission.ProblemID)
-	resp, err := s.problemClient.GetTestCases(ctx, &problempb.GetTestCasesRequest{ProblemId: submission.ProblemID})
+	resp, err := s.testsFor(ctx, problem)
 	if err != nil {
-		return &ty.ResultEvent{
-			SubmissionID: submission.SubmissionID,
-			Status:       "RE",
-			Message:      fmt.Sprintf("Failed to get test cases: %v", err),
-		}, err
+		return nil, err
 	}
 	testCases := resp.GetTestCases()
-	log.Printf("Received %d test cases", len(testCases))
 	if len(testCases) == 0 {
 		return &ty.ResultEvent{
 			SubmissionID: submission.SubmissionID,
@@ -306,239 +355,538 @@ func (s *service) judge(ctx context.Context, submission *ty.SubmissionEvent, wor
 		}, nil
 	}
 
-	if err := os.MkdirAll(s.workDir, 0755); err != nil {
-		return &ty.ResultEvent{
-			SubmissionID: submission.SubmissionID,
-			Status:       "RE",
-			Message:      "Failed to ensure work dir",
-		}, err
+	// A worker is reset after every submission; doing it again here keeps a
+	// failed reset from leaking files into this one.
+	if err := s.sandbox.Cleanup(workerID); err != nil {
+		return nil, fmt.Errorf("failed to prepare sandbox: %w", err)
 	}
 
-	subDir, err := os.MkdirTemp(s.workDir, "sub-*")
+	s.reportProgress(ctx, submission.SubmissionID, ty.StageCompiling, 0, len(testCases))
+	program := programFor(lang, "")
+	source, files, err := submissionSources(submission, program)
 	if err != nil {
 		return &ty.ResultEvent{
 			SubmissionID: submission.SubmissionID,
-			Status:       "RE",
-			Message:      "Failed to create temp dir",
-		}, err
-	}
-	defer os.RemoveAll(subDir)
-
-	if err := os.WriteFile(filepath.Join(subDir, langConfig.CodeFileName), []byte(submission.Code), 0644); err != nil {
-		return &ty.ResultEvent{SubmissionID: submission.SubmissionID,
-			Status:  "RE",
-			Message: "Failed to write code to file",
+			Status:       "CE",
+			Message:      fmt.Sprintf("Compilation Error: %v", err),
 		}, nil
 	}
-
-	binPath := filepath.Join(subDir, "app.bin")
-	if submission.Language == "go" {
-		buildCtx, cancelBuild := context.WithTimeout(ctx, buildTimeout+5*time.Second)
-		defer cancelBuild()
-		stdout, stderr, exitCode, err := s.execInWorker(buildCtx, workerID, []string{
-			"judge-runner",
-			"--phase", "compile",
-			"--lang", submission.Language,
-			"--workdir", subDir,
-			"--outbin", binPath,
-			"--timeout", fmt.Sprintf("%d", int(buildTimeout.Seconds())),
-		}, "")
-		if err != nil {
+	if isGoProgram(program) {
+		if disallowed := disallowedGoImports(source, files, program, problem.GetGoModules()); len(disallowed) > 0 {
 			return &ty.ResultEvent{
 				SubmissionID: submission.SubmissionID,
 				Status:       "CE",
-				Message:      fmt.Sprintf("Compilation Error: %v", err),
+				Message:      goImportsMessage(disallowed, problem.GetGoModules()),
 			}, nil
 		}
-		if exitCode != 0 {
-			msg := strings.TrimSpace(stderr)
-			if msg == "" {
-				msg = strings.TrimSpace(stdout)
+	}
+	res, err := s.sandbox.Compile(ctx, workerID, sandbox.CompileRequest{
+		Program:     program,
+		Source:      source,
+		Files:       files,
+		Timeout:     buildTimeout,
+		MemoryBytes: s.compileMemoryLimit,
+		OutputLimit: maxCompileOutputBytes,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile submission: %w", err)
+	}
+	if res.ExitCode != 0 {
+		msg := strings.TrimSpace(res.Stderr)
+		if msg == "" {
+			msg = strings.TrimSpace(res.Stdout)
+		}
+		return &ty.ResultEvent{
+			SubmissionID: submission.SubmissionID,
+			Status:       "CE",
+			Message:      fmt.Sprintf("Compilation Error: %s", truncateOutput(msg, maxCompileOutputBytes)),
+		}, nil
+	}
+
+	// A checker or interactor runs in a worker of its own language's image,
+	// which is the submission's one or a worker borrowed from another pool.
+	// Every pool has a worker per consumer and a submission takes at most one
+	// of each, so a borrowed worker is always free.
+	workers := map[string]string{imageFor(lang): workerID}
+	defer func() {
+		for image, id := range workers {
+			if id != workerID {
+				s.releaseWorker(image, id)
 			}
-			return &ty.ResultEvent{
-				SubmissionID: submission.SubmissionID,
-				Status:       "CE",
-				Message:      fmt.Sprintf("Compilation Error: %s", msg),
-			}, nil
+		}
+	}()
+	var checker, interactor *setterProgram
+	if checkerSource != nil {
+		checker, err = s.prepareProgram(ctx, workers, programKindChecker, checkerSource)
+		if err != nil {
+			return programFailure(submission.SubmissionID, err, nil)
+		}
+	}
+	if interactorSource != nil {
+		interactor, err = s.prepareProgram(ctx, workers, programKindInteractor, interactorSource)
+		if err != nil {
+			return programFailure(submission.SubmissionID, err, nil)
 		}
 	}
 
-	for i, testCase := range testCases {
+	scoring := problem.GetScoringMode() == scoringModePoints
+	board := newScoreBoard(testCases, resp.GetGroups())
+	order := make([]int, len(testCases))
+	for i := range order {
+		order[i] = i
+	}
+	if scoring {
+		order = board.runOrder(testCases)
+	}
+	var failed *ty.TestResult
+	var failedMessage string
+
+	// Progress counts the tests actually run; a test skipped in points mode
+	// drops out of the total instead of making the count jump.
+	executed, runnable := 0, len(testCases)
+	tests := make([]ty.TestResult, 0, len(testCases))
+	for _, i := range order {
+		testCase := testCases[i]
+		if scoring && !board.shouldRun(testCase) {
+			runnable--
+			continue
+		}
+		executed++
 		log.Printf("Running test case %d for submission %s", i+1, submission.SubmissionID)
+		s.reportProgress(ctx, submission.SubmissionID, ty.StageRunning, executed, runnable)
 
 		internalTC := &ty.TestCase{
 			Input:  testCase.GetInputData(),
 			Output: testCase.GetOutputData(),
 		}
 
-		runCtx, cancelRun := context.WithTimeout(ctx, s.timeout+5*time.Second)
-		status, output, err := s.runTestCase(runCtx, workerID, submission.Language, subDir, binPath, internalTC)
-		cancelRun()
+		var testResult *ty.TestResult
+		var output string
+		if interactor != nil {
+			testResult, output, err = s.runInteractiveTestCase(ctx, workerID, program, runLimits, interactor, internalTC)
+		} else {
+			testResult, output, err = s.runTestCase(ctx, workerID, program, runLimits, cmp, checker, internalTC)
+		}
 		if err != nil {
-			return &ty.ResultEvent{
-				SubmissionID: submission.SubmissionID,
-				Status:       "RE",
-				Message:      err.Error(),
-			}, nil
+			return programFailure(submission.SubmissionID, err, tests)
 		}
-		if status != "AC" {
-			return &ty.ResultEvent{
-				SubmissionID: submission.SubmissionID,
-				Status:       status,
-				Message:      output,
-			}, nil
+		testResult.Index = i + 1
+		tests = append(tests, *testResult)
+
+		board.record(i, testCase, testShare(testResult))
+		if testResult.Status == "AC" {
+			continue
+		}
+		if !scoring {
+			return newResult(submission.SubmissionID, testResult.Status, fmt.Sprintf("Test %d: %s", testResult.Index, output), tests), nil
+		}
+		if failed == nil {
+			failed = testResult
+			failedMessage = output
 		}
 	}
 
-	return &ty.ResultEvent{SubmissionID: submission.SubmissionID,
-		Status:  "AC",
-		Message: "All tests passed",
-	}, nil
+	if !scoring {
+		return newResult(submission.SubmissionID, "AC", "All tests passed", tests), nil
+	}
+
+	// In points mode every test that can still change the score runs; the
+	// verdict is that of the first failed one.
+	score, maxScore := board.total()
+	result := newResult(submission.SubmissionID, "AC", fmt.Sprintf("All tests passed. Score: %g/%g", score, maxScore), tests)
+	if failed != nil {
+		result.Status = failed.Status
+		result.Message = fmt.Sprintf("Score: %g/%g. Test %d: %s", score, maxScore, failed.Index, failedMessage)
+	}
+	result.Score = score
+	result.MaxScore = maxScore
+	return result, nil
+}
+
+// reportProgress tells result_service how far judging got. It is best effort:
+// a lost event only delays what the user sees until the next one.
+func (s *service) reportProgress(ctx context.Context, submissionID, stage string, current, total int) {
+	event := &ty.ResultEvent{
+		SubmissionID: submissionID,
+		Status:       ty.StatusJudging,
+		Stage:        stage,
+		CurrentTest:  current,
+		TotalTests:   total,
+		Message:      stage,
+	}
+	if stage == ty.StageRunning {
+		event.Message = fmt.Sprintf("Running test %d/%d", current, total)
+	}
+	if err := s.progressProducer.WriteMessages(ctx, kafka.Message{Key: []byte(submissionID), Value: event.Marshal()}); err != nil {
+		log.Printf("Failed to report progress of submission %s: %v", submissionID, err)
+	}
+}
+
+// programError is a fault of the problem's checker or interactor, such as one
+// that does not compile or exits with an unknown code. Unlike a sandbox
+// failure it would happen again on a retry, so it becomes the RE verdict.
+type programError struct {
+	msg string
+}
+
+func (e *programError) Error() string {
+	return e.msg
+}
+
+func newProgramError(format string, args ...any) error {
+	return &programError{msg: fmt.Sprintf(format, args...)}
+}
+
+// programFailure turns a programError into the RE verdict and hands any other
+// error back to ProcessSubmission, which retries the submission.
+func programFailure(submissionID string, err error, tests []ty.TestResult) (*ty.ResultEvent, error) {
+	var perr *programError
+	if errors.As(err, &perr) {
+		return newResult(submissionID, "RE", perr.Error(), tests), nil
+	}
+	return nil, err
+}
+
+func newResult(submissionID, status, message string, tests []ty.TestResult) *ty.ResultEvent {
+	result := &ty.ResultEvent{
+		SubmissionID: submissionID,
+		Status:       status,
+		Message:      message,
+		Tests:        tests,
+	}
+	for _, tr := range tests {
+		if tr.MemoryKb > result.MaxMemoryKb {
+			result.MaxMemoryKb = tr.MemoryKb
+		}
+	}
+	return result
 }
 
 func (s *service) runTestCase(
 	ctx context.Context,
 	workerID string,
-	lang string,
-	workDir string,
-	binPath string,
+	program sandbox.Program,
+	runLimits limits,
+	cmp comparator,
+	checker *setterProgram,
 	tc *ty.TestCase,
-) (string, string, error) {
-	stdout, stderr, exitCode, err := s.execInWorker(ctx, workerID, []string{
-		"judge-runner",
-		"--phase", "run",
-		"--lang", lang,
-		"--workdir", workDir,
-		"--outbin", binPath,
-		"--timeout", fmt.Sprintf("%d", int(s.timeout.Seconds())),
-	}, tc.Input)
+) (*ty.TestResult, string, error) {
+	started := time.Now()
+	res, err := s.sandbox.Run(ctx, workerID, sandbox.RunRequest{
+		Program:       program,
+		Stdin:         tc.Input,
+		Timeout:       runLimits.timeout,
+		MemoryBytes:   runLimits.memoryBytes,
+		MemoryLimitMB: runLimits.memoryLimitMB,
+		OutputLimit:   runLimits.outputBytes,
+	})
 	if err != nil {
-		return "RE", err.Error(), nil
+		return nil, "", fmt.Errorf("failed to run submission: %w", err)
+	}
+
+	result := &ty.TestResult{
+		TimeMs:   time.Since(started).Milliseconds(),
+		MemoryKb: res.MaxRSSKb,
+		ExitCode: res.ExitCode,
+		Stderr:   truncateOutput(res.Stderr, maxStderrBytes),
 	}
 
-	if exitCode == 124 || exitCode == 137 {
-		return "TLE", "Time Limit Exceeded", nil
+	if res.OutputExceeded {
+		result.Status = "OLE"
+		return result, "Output Limit Exceeded", nil
 	}
 
-	if exitCode != 0 {
-		msg := strings.TrimSpace(stderr)
+	if memoryExceeded(res, runLimits) {
+		result.Status = "MLE"
+		return result, "Memory Limit Exceeded", nil
+	}
+
+	if res.ExitCode == 124 || res.ExitCode == 137 {
+		result.Status = "TLE"
+		return result, "Time Limit Exceeded", nil
+	}
+
+	if res.ExitCode != 0 {
+		msg := strings.TrimSpace(res.Stderr)
 		if msg == "" {
-			msg = strings.TrimSpace(stdout)
+			msg = strings.TrimSpace(res.Stdout)
 		}
-		log.Printf("Runtime error (exit %d). Stderr: %s", exitCode, strings.TrimSpace(stderr))
-		return "RE", fmt.Sprintf("Runtime Error (Exit Code: %d)\n%s", exitCode, msg), nil
+		log.Printf("Runtime error (exit %d). Stderr: %s", res.ExitCode, strings.TrimSpace(res.Stderr))
+		result.Status = "RE"
+		return result, fmt.Sprintf("Runtime Error (Exit Code: %d)\n%s", res.ExitCode, truncateOutput(msg, maxStderrBytes)), nil
 	}
 
-	programOutput := stdout
-	if strings.TrimSpace(programOutput) != strings.TrimSpace(tc.Output) {
-		return "WA", fmt.Sprintf("Wrong Answer.\nExpected:\n%s\nGot:\n%s", tc.Output, programOutput), nil
+	programOutput := res.Stdout
+	if checker != nil {
+		message, err := s.runChecker(ctx, checker, tc, programOutput, result)
+		if err != nil {
+			return nil, "", err
+		}
+		return result, message, nil
 	}
 
-	return "AC", "", nil
+	if !cmp.equal(tc.Output, programOutput) {
+		result.Status = "WA"
+		return result, fmt.Sprintf("Wrong Answer.\nExpected:\n%s\nGot:\n%s",
+			truncateOutput(tc.Output, maxStderrBytes), truncateOutput(programOutput, maxStderrBytes)), nil
+	}
+
+	result.Status = "AC"
+	return result, "", nil
+}
+
+// setterProgram is a compiled checker or interactor and the worker it was
+// compiled in.
+type setterProgram struct {
+	sandbox.Program
+	workerID string
 }
 
-func (s *service) execInWorker(
+// prepareProgram compiles a checker or interactor into a worker directory
+// keyed by its source hash, so each worker builds it once and reuses it until
+// the setter replaces it. It runs in the worker of its image in workers,
+// borrowing one if there is none yet; the caller releases it.
+func (s *service) prepareProgram(
 	ctx context.Context,
-	workerID string,
-	cmd []string,
-	stdin string,
-) (string, string, int, error) {
-	execResp, err := s.dockerClient.ContainerExecCreate(ctx, workerID, container.ExecOptions{
-		AttachStdout: true,
-		AttachStderr: true,
-		AttachStdin:  true,
-		Cmd:          cmd,
-	})
-	if err != nil {
-		return "", "", 0, fmt.Errorf("exec create failed: %w", err)
+	workers map[string]string,
+	kind string,
+	source *problempb.Program,
+) (*setterProgram, error) {
+	lang, ok := s.languages.Get(source.GetLanguage())
+	if !ok {
+		return nil, newProgramError("unsupported %s language: %s", kind, source.GetLanguage())
+	}
+	image := imageFor(lang)
+	workerID, ok := workers[image]
+	if !ok {
+		workerID = s.acquireWorker(image)
+		workers[image] = workerID
 	}
 
-	attachResp, err := s.dockerClient.ContainerExecAttach(ctx, execResp.ID, container.ExecStartOptions{})
-	if err != nil {
-		return "", "", 0, fmt.Errorf("exec attach failed: %w", err)
+	hash := sha256.Sum256([]byte(source.GetLanguage() + "\x00" + source.GetSourceCode()))
+	name := path.Join(kind+"s", fmt.Sprintf("%s-%s", source.GetProblemId(), hex.EncodeToString(hash[:8])))
+	program := &setterProgram{Program: programFor(lang, name), workerID: workerID}
+
+	key := workerID + ":" + name
+	if _, ok := s.programs.Load(key); ok {
+		return program, nil
 	}
-	defer attachResp.Close()
 
-	if stdin != "" {
-		if _, err := attachResp.Conn.Write([]byte(stdin)); err != nil {
-			return "", "", 0, fmt.Errorf("failed to write to stdin: %w", err)
+	res, err := s.sandbox.Compile(ctx, workerID, sandbox.CompileRequest{
+		Program:     program.Program,
+		Source:      source.GetSourceCode(),
+		Timeout:     buildTimeout,
+		MemoryBytes: s.compileMemoryLimit,
+		OutputLimit: maxCompileOutputBytes,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s compilation failed: %w", kind, err)
+	}
+	if res.ExitCode != 0 {
+		msg := strings.TrimSpace(res.Stderr)
+		if msg == "" {
+			msg = strings.TrimSpace(res.Stdout)
 		}
+		return nil, newProgramError("%s compilation failed: %s", kind, truncateOutput(msg, maxStderrBytes))
 	}
-	attachResp.CloseWrite()
 
-	stdoutBuf := new(bytes.Buffer)
-	stderrBuf := new(bytes.Buffer)
-	done := make(chan struct{})
-	go func() {
-		_, _ = stdcopy.StdCopy(stdoutBuf, stderrBuf, attachResp.Reader)
-		close(done)
-	}()
+	s.programs.Store(key, struct{}{})
+	return program, nil
+}
 
-	select {
-	case <-ctx.Done():
-		return "", "", 0, ctx.Err()
-	case <-done:
+// runChecker follows the testlib convention: the checker is called as
+// `checker <input> <output> <answer>` and reports the verdict by exit code.
+func (s *service) runChecker(
+	ctx context.Context,
+	checker *setterProgram,
+	tc *ty.TestCase,
+	programOutput string,
+	result *ty.TestResult,
+) (string, error) {
+	files := []sandbox.File{
+		{Name: "checker.in", Data: tc.Input},
+		{Name: "checker.out", Data: programOutput},
+		{Name: "checker.ans", Data: tc.Output},
+	}
+	// The checker gets the build limit rather than the problem's, plus room
+	// for its files: they live on the worker's tmpfs and count against the
+	// same cgroup.
+	memoryBytes := s.compileMemoryLimit
+	for _, file := range files {
+		memoryBytes += int64(len(file.Data))
+	}
+	res, err := s.sandbox.Run(ctx, checker.workerID, sandbox.RunRequest{
+		Program:     checker.Program,
+		Files:       files,
+		Timeout:     checkerTimeout,
+		MemoryBytes: memoryBytes,
+		OutputLimit: maxCompileOutputBytes,
+	})
+	if err != nil {
+		return "", fmt.Errorf("checker failed: %w", err)
 	}
 
-	for {
-		inspect, err := s.dockerClient.ContainerExecInspect(ctx, execResp.ID)
-		if err != nil {
-			return "", "", 0, fmt.Errorf("exec inspect failed: %w", err)
+	return programVerdict(programKindChecker, res.ExitCode, res.Stderr, result)
+}
+
+// programVerdict sets the status of the test from the exit code of a checker
+// or interactor and returns the verdict message. Exit code 7 is testlib's
+// quitp: its comment, after the "points" outcome name, starts with the share
+// of the test's points earned, from 0 to 1.
+func programVerdict(kind string, exitCode int, comment string, result *ty.TestResult) (string, error) {
+	comment = truncateOutput(strings.TrimSpace(comment), maxStderrBytes)
+	switch exitCode {
+	case 0:
+		result.Status = "AC"
+		return "", nil
+	case 1:
+		result.Status = "WA"
+		return fmt.Sprintf("Wrong Answer.\n%s", comment), nil
+	case 2:
+		result.Status = "WA"
+		return fmt.Sprintf("Presentation Error.\n%s", comment), nil
+	case 7:
+		points, rest, _ := strings.Cut(strings.TrimPrefix(comment, "points "), " ")
+		share, err := strconv.ParseFloat(points, 64)
+		if err != nil || !(share >= 0 && share <= 1) {
+			return "", newProgramError("%s reported invalid points %q", kind, points)
 		}
-		if !inspect.Running {
-			return stdoutBuf.String(), stderrBuf.String(), inspect.ExitCode, nil
+		rest = strings.TrimSpace(rest)
+		switch share {
+		case 0:
+			result.Status = "WA"
+			return fmt.Sprintf("Wrong Answer.\n%s", rest), nil
+		case 1:
+			result.Status = "AC"
+			return "", nil
 		}
-		time.Sleep(50 * time.Millisecond)
+		result.Status = "PC"
+		result.Share = share
+		return fmt.Sprintf("Partially Correct (%g).\n%s", share, rest), nil
+	default:
+		return "", newProgramError("%s failed (exit code %d): %s", kind, exitCode, comment)
 	}
 }
 
-func parseBuildErrors(r io.Reader) error {
-	dec := json.NewDecoder(r)
-	var out bytes.Buffer
-	for dec.More() {
-		var msg struct {
-			Stream string `json:"stream"`
-			Error  string `json:"error"`
-		}
-		if err := dec.Decode(&msg); err != nil {
-			return fmt.Errorf("build output parse error: %w", err)
-		}
-		if msg.Stream != "" {
-			if out.Len() < 64*1024 {
-				_, _ = out.WriteString(msg.Stream)
-			}
-		}
-		if msg.Error != "" {
-			tail := strings.TrimSpace(out.String())
-			if tail == "" {
-				return fmt.Errorf("build failed: %s", msg.Error)
-			}
-			return fmt.Errorf("build failed: %s\n%s", msg.Error, tail)
-		}
+// runInteractiveTestCase connects the program to the interactor, which gets
+// `<input> <answer>` as arguments and decides the verdict by its exit code.
+func (s *service) runInteractiveTestCase(
+	ctx context.Context,
+	workerID string,
+	program sandbox.Program,
+	runLimits limits,
+	interactor *setterProgram,
+	tc *ty.TestCase,
+) (*ty.TestResult, string, error) {
+	started := time.Now()
+	res, err := s.sandbox.Run(ctx, workerID, sandbox.RunRequest{
+		Program: program,
+		Files: []sandbox.File{
+			{Name: "interactor.in", Data: tc.Input},
+			{Name: "interactor.ans", Data: tc.Output},
+		},
+		Timeout:           runLimits.timeout,
+		MemoryBytes:       runLimits.memoryBytes,
+		MemoryLimitMB:     runLimits.memoryLimitMB,
+		OutputLimit:       runLimits.outputBytes,
+		Interactor:        &interactor.Program,
+		InteractorWorker:  interactor.workerID,
+		InteractorTimeout: runLimits.timeout + checkerTimeout,
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to run submission: %w", err)
 	}
-	return nil
+
+	result := &ty.TestResult{
+		TimeMs:   time.Since(started).Milliseconds(),
+		MemoryKb: res.MaxRSSKb,
+		ExitCode: res.ExitCode,
+		Stderr:   truncateOutput(res.Stderr, maxStderrBytes),
+	}
+
+	if res.OutputExceeded {
+		result.Status = "OLE"
+		return result, "Output Limit Exceeded", nil
+	}
+
+	if memoryExceeded(res, runLimits) {
+		result.Status = "MLE"
+		return result, "Memory Limit Exceeded", nil
+	}
+
+	if res.ExitCode == 124 || res.ExitCode == 137 {
+		result.Status = "TLE"
+		return result, "Time Limit Exceeded", nil
+	}
+
+	if res.InteractorExit < 0 {
+		return nil, "", newProgramError("interactor did not report a verdict: %s", truncateOutput(strings.TrimSpace(res.Stderr), maxStderrBytes))
+	}
+
+	// The interactor gives up first on a wrong answer, which usually makes the
+	// program fail on a closed pipe, so its verdict wins over a runtime error.
+	if res.InteractorExit == 1 || res.InteractorExit == 2 {
+		message, err := programVerdict(programKindInteractor, int(res.InteractorExit), res.Stdout, result)
+		return result, message, err
+	}
+
+	if res.ExitCode != 0 {
+		log.Printf("Runtime error (exit %d). Stderr: %s", res.ExitCode, strings.TrimSpace(res.Stderr))
+		result.Status = "RE"
+		return result, fmt.Sprintf("Runtime Error (Exit Code: %d)\n%s", res.ExitCode, truncateOutput(strings.TrimSpace(res.Stderr), maxStderrBytes)), nil
+	}
+
+	message, err := programVerdict(programKindInteractor, int(res.InteractorExit), res.Stdout, result)
+	if err != nil {
+		return nil, "", err
+	}
+	return result, message, nil
 }
 
-func normalizeLineEndings(data []byte) []byte {
-	out := bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
-	out = bytes.ReplaceAll(out, []byte("\r"), []byte("\n"))
-	return out
+// memoryExceeded reports a cgroup OOM kill, a SIGKILL at the memory limit, or
+// a JVM that ran out of the heap it was given via $MEMORY_LIMIT_MB.
+func memoryExceeded(res *sandbox.Result, runLimits limits) bool {
+	if res.OOMKills > 0 || (res.ExitCode == 137 && res.MaxRSSKb*1024 >= runLimits.memoryBytes*95/100) {
+		return true
+	}
+	return res.ExitCode != 0 && strings.Contains(res.Stderr, jvmOutOfMemoryError)
 }
 
-func runtimeSignature(dockerfileData []byte, runnerData []byte) string {
-	h := sha256.New()
-	_, _ = h.Write(dockerfileData)
-	_, _ = h.Write([]byte{0})
-	_, _ = h.Write(runnerData)
-	return hex.EncodeToString(h.Sum(nil))
+func (s *service) limitsFor(problem *problempb.Problem, lang languages.Language) limits {
+	timeout := s.timeout
+	if problem.GetTimeLimitMs() > 0 {
+		timeout = time.Duration(problem.GetTimeLimitMs()) * time.Millisecond
+	}
+	if lang.TimeMultiplier > 0 {
+		timeout = time.Duration(float64(timeout) * lang.TimeMultiplier)
+	}
+
+	memoryBytes := s.memoryLimit
+	if problem.GetMemoryLimitMb() > 0 {
+		memoryBytes = int64(problem.GetMemoryLimitMb()) * 1024 * 1024
+	}
+	memoryLimitMB := memoryBytes / (1024 * 1024)
+	// Runtimes like the JVM need room beyond the heap the program is allowed.
+	memoryBytes += int64(lang.MemoryOverheadMB) * 1024 * 1024
+
+	outputBytes := s.outputLimit
+	if problem.GetOutputLimitKb() > 0 {
+		outputBytes = int64(problem.GetOutputLimitKb()) * 1024
+	}
+
+	return limits{timeout: timeout, memoryBytes: memoryBytes, memoryLimitMB: memoryLimitMB, outputBytes: outputBytes}
 }
 
-func appendRuntimeLabel(dockerfileData []byte, signature string) []byte {
-	out := dockerfileData
-	if len(out) > 0 && out[len(out)-1] != '\n' {
-		out = append(out, '\n')
+// truncateOutput cuts s to limit bytes on a rune boundary. Program output ends
+// up in Postgres text columns, which take neither NUL bytes nor invalid UTF-8,
+// so it drops those as well.
+func truncateOutput(s string, limit int) string {
+	s = strings.ToValidUTF8(strings.ReplaceAll(s, "\x00", ""), "\uFFFD")
+	if len(s) <= limit {
+		return s
+	}
+	for limit > 0 && !utf8.RuneStart(s[limit]) {
+		limit--
 	}
-	labelLine := fmt.Sprintf("LABEL %s=%s\n", runtimeLabelKey, signature)
-	out = append(out, []byte(labelLine)...)
-	return out
+	return s[:limit] + "\n... (truncated)"
 }
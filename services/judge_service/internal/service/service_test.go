@@ -0,0 +1,675 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	problempb "github.com/DeadlyParkour777/code-checker/pkg/problem"
+	"github.com/DeadlyParkour777/code-checker/services/judge_service/internal/deadletter"
+	"github.com/DeadlyParkour777/code-checker/services/judge_service/internal/languages"
+	"github.com/DeadlyParkour777/code-checker/services/judge_service/internal/sandbox"
+	"github.com/DeadlyParkour777/code-checker/services/judge_service/internal/testcache"
+	ty "github.com/DeadlyParkour777/code-checker/services/judge_service/internal/types"
+	"github.com/segmentio/kafka-go"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeSandbox "runs" a program by answering each test input from outputs.
+type fakeSandbox struct {
+	sandbox.Sandbox
+	compile  sandbox.Result
+	outputs  map[string]string
+	stderr   string
+	runErr   error
+	compiled []sandbox.Program
+	runs     []sandbox.RunRequest
+	// checker is what the checker reports; by default it accepts every
+	// answer.
+	checker sandbox.Result
+	// workers has the worker of every compile and run, in order.
+	workers []string
+}
+
+func (f *fakeSandbox) Compile(ctx context.Context, workerID string, req sandbox.CompileRequest) (*sandbox.Result, error) {
+	f.compiled = append(f.compiled, req.Program)
+	f.workers = append(f.workers, workerID)
+	res := f.compile
+	return &res, nil
+}
+
+func (f *fakeSandbox) Run(ctx context.Context, workerID string, req sandbox.RunRequest) (*sandbox.Result, error) {
+	if f.runErr != nil {
+		return nil, f.runErr
+	}
+	f.runs = append(f.runs, req)
+	f.workers = append(f.workers, workerID)
+	if req.Program.Name != "" {
+		res := f.checker
+		res.InteractorExit = -1
+		return &res, nil
+	}
+	return &sandbox.Result{Stdout: f.outputs[req.Stdin], Stderr: f.stderr, MaxRSSKb: 1024, InteractorExit: -1}, nil
+}
+
+func (f *fakeSandbox) Cleanup(workerID string) error {
+	return nil
+}
+
+type fakeWriter struct {
+	events []ty.ResultEvent
+}
+
+func (w *fakeWriter) WriteMessages(ctx context.Context, msgs ...kafka.Message) error {
+	for _, msg := range msgs {
+		var event ty.ResultEvent
+		if err := json.Unmarshal(msg.Value, &event); err != nil {
+			return err
+		}
+		w.events = append(w.events, event)
+	}
+	return nil
+}
+
+func (w *fakeWriter) messages() []string {
+	var messages []string
+	for _, event := range w.events {
+		messages = append(messages, event.Message)
+	}
+	return messages
+}
+
+type fakeProblemClient struct {
+	problempb.ProblemServiceClient
+	problem   *problempb.Problem
+	checker   *problempb.Program
+	testCases []*problempb.TestCase
+	groups    []*problempb.TestGroup
+	files     map[string]string
+	fetches   int
+	// interactorErr is what GetInteractor fails with; there is never an
+	// interactor otherwise.
+	interactorErr error
+}
+
+func (c *fakeProblemClient) GetProblem(ctx context.Context, in *problempb.GetProblemRequest, opts ...grpc.CallOption) (*problempb.Problem, error) {
+	return c.problem, nil
+}
+
+func (c *fakeProblemClient) GetChecker(ctx context.Context, in *problempb.GetProgramRequest, opts ...grpc.CallOption) (*problempb.Program, error) {
+	if c.checker == nil {
+		return nil, status.Error(codes.NotFound, "no checker")
+	}
+	return c.checker, nil
+}
+
+func (c *fakeProblemClient) GetInteractor(ctx context.Context, in *problempb.GetProgramRequest, opts ...grpc.CallOption) (*problempb.Program, error) {
+	if c.interactorErr != nil {
+		return nil, c.interactorErr
+	}
+	return nil, status.Error(codes.NotFound, "no interactor")
+}
+
+func (c *fakeProblemClient) GetTestCases(ctx context.Context, in *problempb.GetTestCasesRequest, opts ...grpc.CallOption) (*problempb.GetTestCasesResponse, error) {
+	c.fetches++
+	return &problempb.GetTestCasesResponse{TestCases: c.testCases, Groups: c.groups}, nil
+}
+
+func (c *fakeProblemClient) ReadTestFile(ctx context.Context, in *problempb.ReadTestFileRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[problempb.TestFileChunk], error) {
+	data, ok := c.files[in.GetSha256()]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "no file")
+	}
+	return &fakeTestFileStream{data: data}, nil
+}
+
+// fakeTestFileStream sends a file two bytes at a time.
+type fakeTestFileStream struct {
+	grpc.ServerStreamingClient[problempb.TestFileChunk]
+	data string
+}
+
+func (s *fakeTestFileStream) Recv() (*problempb.TestFileChunk, error) {
+	if s.data == "" {
+		return nil, io.EOF
+	}
+	n := min(2, len(s.data))
+	chunk := &problempb.TestFileChunk{Data: []byte(s.data[:n])}
+	s.data = s.data[n:]
+	return chunk, nil
+}
+
+func checksum(data string) string {
+	sum := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestJudge(t *testing.T) {
+	registry, err := languages.Load("")
+	if err != nil {
+		t.Fatalf("failed to load languages: %v", err)
+	}
+	lang, ok := registry.Get("cpp17")
+	if !ok {
+		t.Fatalf("cpp17 is not a default language")
+	}
+	problems := &fakeProblemClient{
+		problem: &problempb.Problem{Id: "p1"},
+		testCases: []*problempb.TestCase{
+			{Id: "t1", InputData: "1 2\n", OutputData: "3\n"},
+			{Id: "t2", InputData: "2 2\n", OutputData: "4\n"},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		compile     sandbox.Result
+		outputs     map[string]string
+		wantStatus  string
+		wantTests   int
+		wantMessage string
+		wantEvents  []string
+	}{
+		{
+			name:       "accepted",
+			outputs:    map[string]string{"1 2\n": "3\n", "2 2\n": "4\n"},
+			wantStatus: "AC",
+			wantTests:  2,
+			wantEvents: []string{"Compiling", "Running test 1/2", "Running test 2/2"},
+		},
+		{
+			name:        "wrong answer stops at the failed test",
+			outputs:     map[string]string{"1 2\n": "3\n", "2 2\n": "5\n"},
+			wantStatus:  "WA",
+			wantTests:   2,
+			wantMessage: "Test 2",
+			wantEvents:  []string{"Compiling", "Running test 1/2", "Running test 2/2"},
+		},
+		{
+			name:        "compilation error",
+			compile:     sandbox.Result{ExitCode: 1, Stderr: "main.cpp:1: error"},
+			wantStatus:  "CE",
+			wantMessage: "main.cpp:1: error",
+			wantEvents:  []string{"Compiling"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sb := &fakeSandbox{compile: tt.compile, outputs: tt.outputs}
+			progress := &fakeWriter{}
+			s := &service{
+				progressProducer: progress,
+				timeout:          time.Second,
+				memoryLimit:      64 * 1024 * 1024,
+				outputLimit:      1024,
+				languages:        registry,
+				problemClient:    problems,
+				testCache:        testcache.New(0),
+				sandbox:          sb,
+			}
+
+			result, err := s.judge(context.Background(), &ty.SubmissionEvent{SubmissionID: "s1", ProblemID: "p1", Code: "code"}, lang, "worker")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Status != tt.wantStatus || len(result.Tests) != tt.wantTests || !strings.Contains(result.Message, tt.wantMessage) {
+				t.Fatalf("unexpected result: %+v", result)
+			}
+			if len(sb.compiled) != 1 || sb.compiled[0].Name != "" || sb.compiled[0].Compile != lang.Compile {
+				t.Fatalf("unexpected compiled programs: %+v", sb.compiled)
+			}
+			if got := progress.messages(); strings.Join(got, ";") != strings.Join(tt.wantEvents, ";") {
+				t.Fatalf("unexpected progress events: %q", got)
+			}
+			for _, event := range progress.events {
+				if event.Status != ty.StatusJudging || event.SubmissionID != "s1" || event.TotalTests != 2 {
+					t.Fatalf("unexpected progress event: %+v", event)
+				}
+			}
+		})
+	}
+}
+
+func TestJudgeCleansProgramOutput(t *testing.T) {
+	registry, err := languages.Load("")
+	if err != nil {
+		t.Fatalf("failed to load languages: %v", err)
+	}
+	lang, _ := registry.Get("cpp17")
+	s := &service{
+		progressProducer: &fakeWriter{},
+		timeout:          time.Second,
+		memoryLimit:      64 * 1024 * 1024,
+		outputLimit:      1024,
+		languages:        registry,
+		problemClient: &fakeProblemClient{
+			problem:   &problempb.Problem{Id: "p1"},
+			testCases: []*problempb.TestCase{{Id: "t1", InputData: "1 2\n", OutputData: "3\n"}},
+		},
+		testCache: testcache.New(0),
+		sandbox: &fakeSandbox{
+			outputs: map[string]string{"1 2\n": "4\x00\n"},
+			stderr:  "debug:\x00\xff" + strings.Repeat("я", maxStderrBytes),
+		},
+	}
+
+	result, err := s.judge(context.Background(), &ty.SubmissionEvent{SubmissionID: "s1", ProblemID: "p1", Code: "code"}, lang, "worker")
+	if err != nil || result.Status != "WA" || len(result.Tests) != 1 {
+		t.Fatalf("unexpected result: %+v %v", result, err)
+	}
+	for _, text := range []string{result.Message, result.Tests[0].Stderr} {
+		if strings.Contains(text, "\x00") || !utf8.ValidString(text) {
+			t.Fatalf("expected text a Postgres column takes, got %q", text)
+		}
+	}
+	if !strings.HasPrefix(result.Tests[0].Stderr, "debug:\uFFFDя") || !strings.HasSuffix(result.Tests[0].Stderr, "я\n... (truncated)") {
+		t.Fatalf("unexpected stderr %q", result.Tests[0].Stderr)
+	}
+}
+
+func TestJudgeProgressSkipsTestsInPointsMode(t *testing.T) {
+	registry, err := languages.Load("")
+	if err != nil {
+		t.Fatalf("failed to load languages: %v", err)
+	}
+	lang, _ := registry.Get("cpp17")
+	progress := &fakeWriter{}
+	s := &service{
+		progressProducer: progress,
+		timeout:          time.Second,
+		memoryLimit:      64 * 1024 * 1024,
+		outputLimit:      1024,
+		languages:        registry,
+		problemClient: &fakeProblemClient{
+			problem: &problempb.Problem{Id: "p1", ScoringMode: scoringModePoints},
+			testCases: []*problempb.TestCase{
+				{Id: "t1", GroupId: "g1", InputData: "1\n", OutputData: "1\n"},
+				{Id: "t2", GroupId: "g1", InputData: "2\n", OutputData: "2\n"},
+				{Id: "t3", GroupId: "g2", InputData: "3\n", OutputData: "3\n"},
+				{Id: "t4", InputData: "4\n", OutputData: "4\n"},
+				{Id: "t5", GroupId: "g3", InputData: "5\n", OutputData: "5\n"},
+			},
+			groups: []*problempb.TestGroup{
+				{Id: "g1", Score: 50},
+				{Id: "g2", Score: 40, DependsOn: []string{"g1"}},
+				{Id: "g3", Score: 10},
+			},
+		},
+		testCache: testcache.New(0),
+		// t1 fails, so t2 and t3 cannot change the score and are skipped.
+		sandbox: &fakeSandbox{outputs: map[string]string{"1\n": "0\n", "4\n": "4\n", "5\n": "5\n"}},
+	}
+
+	result, err := s.judge(context.Background(), &ty.SubmissionEvent{SubmissionID: "s1", ProblemID: "p1", Code: "code"}, lang, "worker")
+	if err != nil || result.Status != "WA" || len(result.Tests) != 3 {
+		t.Fatalf("unexpected result: %+v %v", result, err)
+	}
+	want := []string{"Compiling", "Running test 1/5", "Running test 2/5", "Running test 3/3"}
+	if got := progress.messages(); strings.Join(got, ";") != strings.Join(want, ";") {
+		t.Fatalf("unexpected progress events: %q", got)
+	}
+}
+
+func TestJudgeScoresPartialPoints(t *testing.T) {
+	registry, err := languages.Load("")
+	if err != nil {
+		t.Fatalf("failed to load languages: %v", err)
+	}
+	lang, _ := registry.Get("cpp17")
+	s := &service{
+		progressProducer: &fakeWriter{},
+		timeout:          time.Second,
+		memoryLimit:      64 * 1024 * 1024,
+		outputLimit:      1024,
+		languages:        registry,
+		problemClient: &fakeProblemClient{
+			problem: &problempb.Problem{Id: "p1", ScoringMode: scoringModePoints},
+			checker: &problempb.Program{ProblemId: "p1", Language: "cpp17", SourceCode: "checker"},
+			testCases: []*problempb.TestCase{
+				{Id: "t1", GroupId: "g1", InputData: "1\n", OutputData: "1\n"},
+				{Id: "t2", GroupId: "g1", InputData: "2\n", OutputData: "2\n"},
+			},
+			groups: []*problempb.TestGroup{{Id: "g1", Score: 40, Aggregation: groupAggregationMin}},
+		},
+		testCache: testcache.New(0),
+		// The checker gives half the points on every test, as testlib's
+		// quitp(0.5, ...) reports it.
+		sandbox: &fakeSandbox{checker: sandbox.Result{ExitCode: 7, Stderr: "points 0.5 suboptimal answer"}},
+	}
+
+	result, err := s.judge(context.Background(), &ty.SubmissionEvent{SubmissionID: "s1", ProblemID: "p1", Code: "code"}, lang, "worker")
+	if err != nil || result.Status != "PC" || len(result.Tests) != 2 {
+		t.Fatalf("unexpected result: %+v %v", result, err)
+	}
+	if result.Score != 20 || result.MaxScore != 40 || !strings.Contains(result.Message, "suboptimal answer") {
+		t.Fatalf("unexpected score: %+v", result)
+	}
+}
+
+func TestProgramVerdict(t *testing.T) {
+	tests := []struct {
+		name        string
+		exitCode    int
+		comment     string
+		wantStatus  string
+		wantShare   float64
+		wantMessage string
+		wantErr     bool
+	}{
+		{name: "accepted", exitCode: 0, wantStatus: "AC"},
+		{name: "wrong answer", exitCode: 1, comment: "expected 3", wantStatus: "WA", wantMessage: "Wrong Answer.\nexpected 3"},
+		{name: "presentation error", exitCode: 2, wantStatus: "WA", wantMessage: "Presentation Error."},
+		{name: "partial points", exitCode: 7, comment: "points 0.25 too slow", wantStatus: "PC", wantShare: 0.25, wantMessage: "Partially Correct (0.25).\ntoo slow"},
+		{name: "partial points without outcome name", exitCode: 7, comment: "0.75", wantStatus: "PC", wantShare: 0.75},
+		{name: "full points", exitCode: 7, comment: "points 1", wantStatus: "AC"},
+		{name: "no points", exitCode: 7, comment: "points 0 nothing", wantStatus: "WA", wantMessage: "Wrong Answer.\nnothing"},
+		{name: "points over the test", exitCode: 7, comment: "points 5", wantErr: true},
+		{name: "missing points", exitCode: 7, comment: "points", wantErr: true},
+		{name: "checker failure", exitCode: 3, comment: "bad answer file", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var result ty.TestResult
+			message, err := programVerdict(programKindChecker, tt.exitCode, tt.comment, &result)
+			if tt.wantErr {
+				var programErr *programError
+				if !errors.As(err, &programErr) {
+					t.Fatalf("expected a checker fault, got %v", err)
+				}
+				return
+			}
+			if err != nil || result.Status != tt.wantStatus || result.Share != tt.wantShare || !strings.HasPrefix(message, tt.wantMessage) {
+				t.Fatalf("unexpected verdict: %+v %q %v", result, message, err)
+			}
+		})
+	}
+}
+
+func TestJudgeReturnsSandboxFailures(t *testing.T) {
+	registry, err := languages.Load("")
+	if err != nil {
+		t.Fatalf("failed to load languages: %v", err)
+	}
+	lang, _ := registry.Get("cpp17")
+	s := &service{
+		progressProducer: &fakeWriter{},
+		timeout:          time.Second,
+		memoryLimit:      64 * 1024 * 1024,
+		outputLimit:      1024,
+		languages:        registry,
+		problemClient: &fakeProblemClient{
+			problem:   &problempb.Problem{Id: "p1"},
+			testCases: []*problempb.TestCase{{Id: "t1", InputData: "1 2\n", OutputData: "3\n"}},
+		},
+		testCache: testcache.New(0),
+		sandbox:   &fakeSandbox{runErr: errors.New("exec attach failed")},
+	}
+
+	// A broken sandbox is not the submission's fault: no verdict, so the
+	// caller retries it.
+	result, err := s.judge(context.Background(), &ty.SubmissionEvent{SubmissionID: "s1", ProblemID: "p1", Code: "code"}, lang, "worker")
+	if err == nil || !strings.Contains(err.Error(), "exec attach failed") {
+		t.Fatalf("expected the sandbox failure, got %+v %v", result, err)
+	}
+}
+
+func TestJudgeInteractorLookupFailures(t *testing.T) {
+	registry, err := languages.Load("")
+	if err != nil {
+		t.Fatalf("failed to load languages: %v", err)
+	}
+	lang, _ := registry.Get("cpp17")
+
+	tests := []struct {
+		name          string
+		interactorErr error
+		wantRE        bool
+	}{
+		{name: "not set", wantRE: true},
+		{name: "problem service failure", interactorErr: status.Error(codes.Internal, "connection refused")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &service{
+				progressProducer: &fakeWriter{},
+				timeout:          time.Second,
+				memoryLimit:      64 * 1024 * 1024,
+				outputLimit:      1024,
+				languages:        registry,
+				problemClient: &fakeProblemClient{
+					problem:       &problempb.Problem{Id: "p1", Type: problemTypeInteractive},
+					interactorErr: tt.interactorErr,
+					testCases:     []*problempb.TestCase{{Id: "t1", InputData: "1 2\n", OutputData: "3\n"}},
+				},
+				testCache: testcache.New(0),
+				sandbox:   &fakeSandbox{},
+			}
+
+			// Only a missing interactor is the problem's fault; a failed
+			// lookup is returned so the caller retries the submission.
+			result, err := s.judge(context.Background(), &ty.SubmissionEvent{SubmissionID: "s1", ProblemID: "p1", Code: "code"}, lang, "worker")
+			if tt.wantRE {
+				if err != nil || result.Status != "RE" {
+					t.Fatalf("expected a runtime error verdict, got %+v %v", result, err)
+				}
+				return
+			}
+			if err == nil || result != nil {
+				t.Fatalf("expected the lookup failure, got %+v %v", result, err)
+			}
+		})
+	}
+}
+
+func TestJudgeGivesCheckerItsOwnMemoryLimit(t *testing.T) {
+	registry, err := languages.Load("")
+	if err != nil {
+		t.Fatalf("failed to load languages: %v", err)
+	}
+	lang, _ := registry.Get("cpp17")
+	sb := &fakeSandbox{outputs: map[string]string{"1 2\n": "3\n"}}
+	s := &service{
+		progressProducer:   &fakeWriter{},
+		timeout:            time.Second,
+		memoryLimit:        64 * 1024 * 1024,
+		compileMemoryLimit: 512 * 1024 * 1024,
+		outputLimit:        1024,
+		languages:          registry,
+		problemClient: &fakeProblemClient{
+			problem:   &problempb.Problem{Id: "p1", MemoryLimitMb: 64},
+			checker:   &problempb.Program{ProblemId: "p1", Language: "cpp17", SourceCode: "checker"},
+			testCases: []*problempb.TestCase{{Id: "t1", InputData: "1 2\n", OutputData: "3\n"}},
+		},
+		testCache: testcache.New(0),
+		sandbox:   sb,
+	}
+
+	result, err := s.judge(context.Background(), &ty.SubmissionEvent{SubmissionID: "s1", ProblemID: "p1", Code: "code"}, lang, "worker")
+	if err != nil || result.Status != "AC" {
+		t.Fatalf("unexpected result: %+v %v", result, err)
+	}
+	if len(sb.runs) != 2 {
+		t.Fatalf("expected the program and the checker to run, got %d runs", len(sb.runs))
+	}
+	// The checker's files are on the worker's tmpfs, so they are added to
+	// its limit.
+	files := int64(len("1 2\n") + len("3\n") + len("3\n"))
+	if program, checker := sb.runs[0], sb.runs[1]; program.MemoryBytes != 64*1024*1024 || checker.MemoryBytes != 512*1024*1024+files {
+		t.Fatalf("unexpected memory limits: program %d, checker %d", program.MemoryBytes, checker.MemoryBytes)
+	}
+}
+
+func TestJudgeRunsCheckerInItsOwnImage(t *testing.T) {
+	registry, err := languages.Load("")
+	if err != nil {
+		t.Fatalf("failed to load languages: %v", err)
+	}
+	lang, _ := registry.Get("java")
+	setters := make(chan string, 1)
+	setters <- "setter"
+	sb := &fakeSandbox{outputs: map[string]string{"1 2\n": "3\n"}}
+	s := &service{
+		progressProducer: &fakeWriter{},
+		timeout:          time.Second,
+		memoryLimit:      64 * 1024 * 1024,
+		outputLimit:      1024,
+		languages:        registry,
+		workerPools:      map[string]chan string{sandbox.DefaultImage: setters},
+		problemClient: &fakeProblemClient{
+			problem:   &problempb.Problem{Id: "p1"},
+			checker:   &problempb.Program{ProblemId: "p1", Language: "cpp17", SourceCode: "checker"},
+			testCases: []*problempb.TestCase{{Id: "t1", InputData: "1 2\n", OutputData: "3\n"}},
+		},
+		testCache: testcache.New(0),
+		sandbox:   sb,
+	}
+
+	// The JVM runtime has no C++ compiler, so the checker is built and run
+	// in a worker of the default runtime, which goes back afterwards.
+	result, err := s.judge(context.Background(), &ty.SubmissionEvent{SubmissionID: "s1", ProblemID: "p1", Language: "java", Code: "code"}, lang, "worker")
+	if err != nil || result.Status != "AC" {
+		t.Fatalf("unexpected result: %+v %v", result, err)
+	}
+	want := []string{"worker", "setter", "worker", "setter"}
+	if strings.Join(sb.workers, " ") != strings.Join(want, " ") {
+		t.Fatalf("expected compiles and runs in %v, got %v", want, sb.workers)
+	}
+	if len(setters) != 1 {
+		t.Fatalf("expected the borrowed worker to be released")
+	}
+}
+
+func TestJudgeCachesTests(t *testing.T) {
+	registry, err := languages.Load("")
+	if err != nil {
+		t.Fatalf("failed to load languages: %v", err)
+	}
+	lang, _ := registry.Get("cpp17")
+	problems := &fakeProblemClient{
+		problem:   &problempb.Problem{Id: "p1", TestsVersion: 1},
+		testCases: []*problempb.TestCase{{Id: "t1", InputData: "1 2\n", OutputData: "3\n"}},
+	}
+	s := &service{
+		progressProducer: &fakeWriter{},
+		timeout:          time.Second,
+		memoryLimit:      64 * 1024 * 1024,
+		outputLimit:      1024,
+		languages:        registry,
+		problemClient:    problems,
+		testCache:        testcache.New(1024),
+		sandbox:          &fakeSandbox{outputs: map[string]string{"1 2\n": "3\n"}},
+	}
+	judge := func() {
+		t.Helper()
+		result, err := s.judge(context.Background(), &ty.SubmissionEvent{SubmissionID: "s1", ProblemID: "p1"}, lang, "worker")
+		if err != nil || result.Status != "AC" {
+			t.Fatalf("unexpected result: %+v %v", result, err)
+		}
+	}
+
+	judge()
+	judge()
+	if problems.fetches != 1 {
+		t.Fatalf("expected tests to be fetched once, got %d", problems.fetches)
+	}
+
+	s.HandleProblemEvent(context.Background(), &ty.ProblemEvent{EventType: ty.ProblemEventTestsUpdated, ProblemID: "p1"})
+	judge()
+	problems.problem.TestsVersion = 2
+	judge()
+	if problems.fetches != 3 {
+		t.Fatalf("expected tests to be fetched after invalidation and a new version, got %d", problems.fetches)
+	}
+}
+
+func TestJudgeReadsTestFiles(t *testing.T) {
+	registry, err := languages.Load("")
+	if err != nil {
+		t.Fatalf("failed to load languages: %v", err)
+	}
+	lang, _ := registry.Get("cpp17")
+	problems := &fakeProblemClient{
+		problem: &problempb.Problem{Id: "p1"},
+		testCases: []*problempb.TestCase{
+			{Id: "t1", InputSha256: checksum("1 2\n"), InputSize: 4, OutputSha256: checksum("3\n"), OutputSize: 2},
+			{Id: "t2", InputData: "2 2\n", OutputData: "4\n"},
+		},
+		files: map[string]string{checksum("1 2\n"): "1 2\n", checksum("3\n"): "3\n"},
+	}
+	s := &service{
+		progressProducer: &fakeWriter{},
+		timeout:          time.Second,
+		memoryLimit:      64 * 1024 * 1024,
+		outputLimit:      1024,
+		languages:        registry,
+		problemClient:    problems,
+		testCache:        testcache.New(0),
+		sandbox:          &fakeSandbox{outputs: map[string]string{"1 2\n": "3\n", "2 2\n": "4\n"}},
+	}
+	submission := &ty.SubmissionEvent{SubmissionID: "s1", ProblemID: "p1"}
+
+	result, err := s.judge(context.Background(), submission, lang, "worker")
+	if err != nil || result.Status != "AC" || len(result.Tests) != 2 {
+		t.Fatalf("unexpected result: %+v %v", result, err)
+	}
+
+	problems.files[checksum("3\n")] = "corrupted"
+	if _, err := s.judge(context.Background(), submission, lang, "worker"); err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Fatalf("expected a checksum mismatch, got %v", err)
+	}
+}
+
+// fakeQueue holds dead letters in memory.
+type fakeQueue struct {
+	deadletter.Queue
+	letters map[int64]*deadletter.Letter
+}
+
+func (q *fakeQueue) Get(ctx context.Context, offset int64) (*deadletter.Letter, error) {
+	letter, ok := q.letters[offset]
+	if !ok {
+		return nil, deadletter.ErrNotFound
+	}
+	copied := *letter
+	return &copied, nil
+}
+
+func (q *fakeQueue) MarkReplayed(ctx context.Context, offset int64) error {
+	q.letters[offset].Replayed = true
+	return nil
+}
+
+func TestReplayDeadLetterOnce(t *testing.T) {
+	results := &fakeWriter{}
+	submissions := &fakeWriter{}
+	s := &service{
+		kafkaProducer:      results,
+		submissionProducer: submissions,
+		deadLetters: &fakeQueue{letters: map[int64]*deadletter.Letter{
+			7: {Offset: 7, Key: []byte("s1"), Value: []byte(`{"submission_id": "s1"}`)},
+		}},
+	}
+
+	letter, err := s.ReplayDeadLetter(context.Background(), 7)
+	if err != nil || !letter.Replayed {
+		t.Fatalf("unexpected replay: %+v %v", letter, err)
+	}
+	if _, err := s.ReplayDeadLetter(context.Background(), 7); !errors.Is(err, deadletter.ErrAlreadyReplayed) {
+		t.Fatalf("expected the second replay to be refused, got %v", err)
+	}
+	if len(submissions.events) != 1 || len(results.events) != 1 || results.events[0].Status != ty.StatusPending {
+		t.Fatalf("expected the submission to be queued once, got %d submissions, results %+v", len(submissions.events), results.events)
+	}
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/DeadlyParkour777/code-checker/services/judge_service/internal/sandbox"
+	ty "github.com/DeadlyParkour777/code-checker/services/judge_service/internal/types"
+)
+
+// The submission service enforces its own, usually tighter, limits on the file
+// tree; these keep a malformed event from filling a worker's disk.
+const (
+	maxSubmissionFiles = 1000
+	maxSubmissionBytes = 16 * 1024 * 1024
+)
+
+// submissionSources splits a submission into the program's source file and
+// the rest of its files. A multi-file submission must have the language's
+// source file at its root: it is what gets built or run.
+func submissionSources(submission *ty.SubmissionEvent, program sandbox.Program) (string, []sandbox.File, error) {
+	if len(submission.Files) == 0 {
+		return submission.Code, nil, nil
+	}
+	if len(submission.Files) > maxSubmissionFiles {
+		return "", nil, fmt.Errorf("submission has %d files, at most %d are allowed", len(submission.Files), maxSubmissionFiles)
+	}
+
+	var source string
+	var files []sandbox.File
+	size := 0
+	seen := make(map[string]bool, len(submission.Files))
+	for _, f := range submission.Files {
+		if err := sandbox.CheckPath(f.Path); err != nil {
+			return "", nil, err
+		}
+		if seen[f.Path] {
+			return "", nil, fmt.Errorf("duplicate file %q", f.Path)
+		}
+		seen[f.Path] = true
+		size += len(f.Content)
+
+		if f.Path == program.SourceFile {
+			source = f.Content
+			continue
+		}
+		files = append(files, sandbox.File{Name: f.Path, Data: f.Content})
+	}
+	if size > maxSubmissionBytes {
+		return "", nil, fmt.Errorf("submission files take %d bytes, at most %d are allowed", size, maxSubmissionBytes)
+	}
+	if !seen[program.SourceFile] {
+		return "", nil, fmt.Errorf("submission has no %s", program.SourceFile)
+	}
+	return source, files, nil
+}
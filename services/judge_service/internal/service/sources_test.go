@@ -0,0 +1,45 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/DeadlyParkour777/code-checker/services/judge_service/internal/sandbox"
+	ty "github.com/DeadlyParkour777/code-checker/services/judge_service/internal/types"
+)
+
+func TestSubmissionSources(t *testing.T) {
+	program := sandbox.Program{SourceFile: "main.go"}
+
+	source, files, err := submissionSources(&ty.SubmissionEvent{Code: "package main"}, program)
+	if err != nil || source != "package main" || files != nil {
+		t.Fatalf("unexpected single-file sources: %q %v %v", source, files, err)
+	}
+
+	source, files, err = submissionSources(&ty.SubmissionEvent{Files: []ty.File{
+		{Path: "go.mod", Content: "module app"},
+		{Path: "main.go", Content: "package main"},
+		{Path: "internal/calc/calc.go", Content: "package calc"},
+	}}, program)
+	if err != nil || source != "package main" || len(files) != 2 || files[1] != (sandbox.File{Name: "internal/calc/calc.go", Data: "package calc"}) {
+		t.Fatalf("unexpected multi-file sources: %q %v %v", source, files, err)
+	}
+
+	tests := []struct {
+		name  string
+		files []ty.File
+		want  string
+	}{
+		{"no entry file", []ty.File{{Path: "calc/calc.go"}}, "has no main.go"},
+		{"traversal", []ty.File{{Path: "main.go"}, {Path: "../../etc/profile"}}, "invalid file path"},
+		{"absolute", []ty.File{{Path: "main.go"}, {Path: "/tmp/x"}}, "invalid file path"},
+		{"too large", []ty.File{{Path: "main.go", Content: strings.Repeat("x", maxSubmissionBytes+1)}}, "at most"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := submissionSources(&ty.SubmissionEvent{Files: tt.files}, program); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("expected %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	problempb "github.com/DeadlyParkour777/code-checker/pkg/problem"
+)
+
+// loadTestFiles fills in the data of test cases kept in blob storage. Files
+// arrive in chunks, so their size is not bound by the gRPC message limit, and
+// are checked against their checksums. Equal files are fetched once.
+func (s *service) loadTestFiles(ctx context.Context, testCases []*problempb.TestCase) error {
+	files := make(map[string]string)
+	load := func(sha256 string, size int64) (string, error) {
+		if data, ok := files[sha256]; ok {
+			return data, nil
+		}
+		data, err := s.readTestFile(ctx, sha256, size)
+		if err != nil {
+			return "", err
+		}
+		files[sha256] = data
+		return data, nil
+	}
+
+	for _, tc := range testCases {
+		if tc.GetInputSha256() != "" {
+			data, err := load(tc.GetInputSha256(), tc.GetInputSize())
+			if err != nil {
+				return fmt.Errorf("failed to read input of test %s: %w", tc.GetId(), err)
+			}
+			tc.InputData = data
+		}
+		if tc.GetOutputSha256() != "" {
+			data, err := load(tc.GetOutputSha256(), tc.GetOutputSize())
+			if err != nil {
+				return fmt.Errorf("failed to read output of test %s: %w", tc.GetId(), err)
+			}
+			tc.OutputData = data
+		}
+	}
+	return nil
+}
+
+func (s *service) readTestFile(ctx context.Context, checksum string, size int64) (string, error) {
+	stream, err := s.problemClient.ReadTestFile(ctx, &problempb.ReadTestFileRequest{Sha256: checksum})
+	if err != nil {
+		return "", err
+	}
+
+	var data strings.Builder
+	data.Grow(int(size))
+	hash := sha256.New()
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		hash.Write(chunk.GetData())
+		data.Write(chunk.GetData())
+	}
+
+	if got := hex.EncodeToString(hash.Sum(nil)); got != checksum || int64(data.Len()) != size {
+		return "", fmt.Errorf("checksum mismatch: got %s (%d bytes), want %s (%d bytes)", got, data.Len(), checksum, size)
+	}
+	return data.String(), nil
+}
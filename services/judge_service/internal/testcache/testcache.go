@@ -0,0 +1,102 @@
+package testcache
+
+import (
+	"container/list"
+	"sync"
+
+	problempb "github.com/DeadlyParkour777/code-checker/pkg/problem"
+)
+
+// Cache keeps the test sets of recently judged problems in memory, least
+// recently used first out once their total size exceeds the limit. An entry
+// only matches the tests version it was fetched for, so a judge never uses
+// tests older than the problem it got from problem_service.
+type Cache struct {
+	mu       sync.Mutex
+	maxBytes int64
+	size     int64
+	lru      *list.List
+	entries  map[string]*list.Element
+}
+
+type entry struct {
+	problemID string
+	version   int64
+	tests     *problempb.GetTestCasesResponse
+	size      int64
+}
+
+// New returns a cache holding up to maxBytes of test data. With maxBytes <= 0
+// nothing is cached.
+func New(maxBytes int64) *Cache {
+	return &Cache{
+		maxBytes: maxBytes,
+		lru:      list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+func (c *Cache) Get(problemID string, version int64) (*problempb.GetTestCasesResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[problemID]
+	if !ok {
+		return nil, false
+	}
+	e := el.Value.(*entry)
+	if e.version != version {
+		return nil, false
+	}
+	c.lru.MoveToFront(el)
+	return e.tests, true
+}
+
+// Put stores tests fetched for the given version. A newer version already in
+// the cache is kept.
+func (c *Cache) Put(problemID string, version int64, tests *problempb.GetTestCasesResponse) {
+	size := sizeOf(tests)
+	if size > c.maxBytes {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[problemID]; ok {
+		if el.Value.(*entry).version > version {
+			return
+		}
+		c.remove(el)
+	}
+	c.entries[problemID] = c.lru.PushFront(&entry{problemID: problemID, version: version, tests: tests, size: size})
+	c.size += size
+
+	for c.size > c.maxBytes {
+		c.remove(c.lru.Back())
+	}
+}
+
+// Invalidate drops the test set of a problem.
+func (c *Cache) Invalidate(problemID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[problemID]; ok {
+		c.remove(el)
+	}
+}
+
+func (c *Cache) remove(el *list.Element) {
+	e := c.lru.Remove(el).(*entry)
+	delete(c.entries, e.problemID)
+	c.size -= e.size
+}
+
+func sizeOf(tests *problempb.GetTestCasesResponse) int64 {
+	var size int64
+	for _, tc := range tests.GetTestCases() {
+		size += int64(len(tc.GetInputData()) + len(tc.GetOutputData()))
+	}
+	return size
+}
@@ -0,0 +1,58 @@
+package testcache
+
+import (
+	"testing"
+
+	problempb "github.com/DeadlyParkour777/code-checker/pkg/problem"
+)
+
+func testSet(input string) *problempb.GetTestCasesResponse {
+	return &problempb.GetTestCasesResponse{
+		TestCases: []*problempb.TestCase{{InputData: input, OutputData: "ok"}},
+	}
+}
+
+func TestCacheVersions(t *testing.T) {
+	c := New(1024)
+	c.Put("p1", 1, testSet("old"))
+
+	if _, ok := c.Get("p1", 2); ok {
+		t.Fatalf("expected a newer version to miss")
+	}
+	c.Put("p1", 2, testSet("new"))
+	c.Put("p1", 1, testSet("stale"))
+
+	tests, ok := c.Get("p1", 2)
+	if !ok || tests.GetTestCases()[0].GetInputData() != "new" {
+		t.Fatalf("expected the newest test set, got %v", tests)
+	}
+
+	c.Invalidate("p1")
+	if _, ok := c.Get("p1", 2); ok {
+		t.Fatalf("expected an invalidated test set to miss")
+	}
+}
+
+func TestCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	// Every test set below takes 6 bytes.
+	c := New(12)
+	c.Put("p1", 0, testSet("1111"))
+	c.Put("p2", 0, testSet("2222"))
+	c.Get("p1", 0)
+	c.Put("p3", 0, testSet("3333"))
+
+	if _, ok := c.Get("p2", 0); ok {
+		t.Fatalf("expected p2 to be evicted")
+	}
+	if _, ok := c.Get("p1", 0); !ok {
+		t.Fatalf("expected p1 to stay cached")
+	}
+
+	c.Put("p4", 0, testSet("too large for the cache"))
+	if _, ok := c.Get("p4", 0); ok {
+		t.Fatalf("expected an oversized test set not to be cached")
+	}
+	if _, ok := c.Get("p3", 0); !ok {
+		t.Fatalf("expected p3 to stay cached")
+	}
+}
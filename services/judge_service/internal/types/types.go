@@ -11,13 +11,65 @@ type SubmissionEvent struct {
 	SubmissionID string `json:"submission_id"`
 	ProblemID    string `json:"problem_id"`
 	Code         string `json:"code"`
+	Files        []File `json:"files,omitempty"`
 	Language     string `json:"language"`
 }
 
+// File is a source file of a multi-file submission, with a slash-separated
+// path relative to the submission directory.
+type File struct {
+	Path    string `json:"path"`
+	Content string `json:"content"`
+}
+
+// ProblemEventTestsUpdated is published by problem_service when the test
+// cases or groups of a problem change.
+const ProblemEventTestsUpdated = "tests_updated"
+
+type ProblemEvent struct {
+	EventType string `json:"event_type"`
+	ProblemID string `json:"problem_id,omitempty"`
+}
+
+type TestResult struct {
+	Index    int    `json:"index"`
+	Status   string `json:"status"`
+	TimeMs   int64  `json:"time_ms"`
+	MemoryKb int64  `json:"memory_kb"`
+	ExitCode int    `json:"exit_code"`
+	Stderr   string `json:"stderr,omitempty"`
+	// Share is the share of the test's points a checker gave to a partially
+	// correct (PC) answer. It only counts towards the score.
+	Share float64 `json:"-"`
+}
+
+const (
+	StatusPending = "Pending"
+	// StatusSystemError is the verdict of a submission the judge gave up on
+	// after retrying.
+	StatusSystemError = "SE"
+)
+
+// StatusJudging marks a progress event sent while the submission is still
+// being judged; Stage, CurrentTest and TotalTests describe how far it got.
+const StatusJudging = "Judging"
+
+const (
+	StageCompiling = "Compiling"
+	StageRunning   = "Running"
+)
+
 type ResultEvent struct {
-	SubmissionID string `json:"submission_id"`
-	Status       string `json:"status"`
-	Message      string `json:"message,omitempty"`
+	SubmissionID string       `json:"submission_id"`
+	Status       string       `json:"status"`
+	Message      string       `json:"message,omitempty"`
+	MaxMemoryKb  int64        `json:"max_memory_kb,omitempty"`
+	Score        float64      `json:"score,omitempty"`
+	MaxScore     float64      `json:"max_score,omitempty"`
+	Tests        []TestResult `json:"tests,omitempty"`
+	Stage        string       `json:"stage,omitempty"`
+	CurrentTest  int          `json:"current_test,omitempty"`
+	TotalTests   int          `json:"total_tests,omitempty"`
 }
 
 func (r *ResultEvent) Marshal() []byte {
@@ -1,12 +1,14 @@
 package app
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"log"
 	"net"
 
 	problem_service "github.com/DeadlyParkour777/code-checker/pkg/problem"
+	"github.com/DeadlyParkour777/code-checker/services/problem_service/internal/blob"
 	"github.com/DeadlyParkour777/code-checker/services/problem_service/internal/config"
 	"github.com/DeadlyParkour777/code-checker/services/problem_service/internal/handler"
 	"github.com/DeadlyParkour777/code-checker/services/problem_service/internal/service"
@@ -46,8 +48,28 @@ func New(cfg config.Config) (*App, error) {
 	})
 	log.Println("Kafka producer initialized")
 
+	var blobs blob.Store
+	switch cfg.BlobBackend {
+	case "local":
+		blobs, err = blob.NewLocal(cfg.BlobDir)
+	case "s3":
+		blobs, err = blob.NewS3(context.Background(), blob.S3Config{
+			Endpoint:  cfg.S3Endpoint,
+			Bucket:    cfg.S3Bucket,
+			AccessKey: cfg.S3AccessKey,
+			SecretKey: cfg.S3SecretKey,
+			UseSSL:    cfg.S3UseSSL,
+		})
+	default:
+		err = fmt.Errorf("unknown backend %q", cfg.BlobBackend)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create blob store: %w", err)
+	}
+	log.Printf("Blob store initialized (%s)", cfg.BlobBackend)
+
 	appStore := store.NewStore(db)
-	appService := service.NewService(appStore, cfg.ProblemEventsTopic, kafkaProducer)
+	appService := service.NewService(appStore, cfg.ProblemEventsTopic, kafkaProducer, blobs)
 	grpcHandler := handler.NewGrpcHandler(appService)
 
 	grpcServer := grpc.NewServer()
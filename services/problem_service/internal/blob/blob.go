@@ -0,0 +1,55 @@
+package blob
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"io"
+	"regexp"
+)
+
+var ErrNotFound = errors.New("blob not found")
+
+var checksumPattern = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// Object is a stored blob. Blobs are addressed by the SHA-256 of their
+// content, so equal test files are stored once and a reader can verify what
+// it got.
+type Object struct {
+	SHA256 string
+	Size   int64
+}
+
+type Store interface {
+	Put(ctx context.Context, r io.Reader) (*Object, error)
+	Open(ctx context.Context, sha256 string) (io.ReadCloser, error)
+	Stat(ctx context.Context, sha256 string) (*Object, error)
+}
+
+func ValidChecksum(sha256 string) bool {
+	return checksumPattern.MatchString(sha256)
+}
+
+// hashingReader hashes and counts everything read through it.
+type hashingReader struct {
+	r    io.Reader
+	hash hash.Hash
+	size int64
+}
+
+func newHashingReader(r io.Reader) *hashingReader {
+	return &hashingReader{r: r, hash: sha256.New()}
+}
+
+func (h *hashingReader) Read(p []byte) (int, error) {
+	n, err := h.r.Read(p)
+	h.hash.Write(p[:n])
+	h.size += int64(n)
+	return n, err
+}
+
+func (h *hashingReader) object() *Object {
+	return &Object{SHA256: hex.EncodeToString(h.hash.Sum(nil)), Size: h.size}
+}
@@ -0,0 +1,88 @@
+package blob
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/testcontainers/testcontainers-go/modules/minio"
+)
+
+func testStore(t *testing.T, s Store) {
+	t.Helper()
+	ctx := context.Background()
+	data := strings.Repeat("1 2\n", 1<<20)
+	sum := sha256.Sum256([]byte(data))
+
+	obj, err := s.Put(ctx, strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	if obj.SHA256 != hex.EncodeToString(sum[:]) || obj.Size != int64(len(data)) {
+		t.Fatalf("unexpected object: %+v", obj)
+	}
+
+	stat, err := s.Stat(ctx, obj.SHA256)
+	if err != nil || stat.Size != obj.Size {
+		t.Fatalf("unexpected stat: %+v %v", stat, err)
+	}
+
+	r, err := s.Open(ctx, obj.SHA256)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	got, err := io.ReadAll(r)
+	r.Close()
+	if err != nil || string(got) != data {
+		t.Fatalf("unexpected content (%d bytes): %v", len(got), err)
+	}
+
+	missing := strings.Repeat("0", 64)
+	if _, err := s.Open(ctx, missing); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+	if _, err := s.Stat(ctx, "../../etc/passwd"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected an invalid checksum to be not found, got %v", err)
+	}
+}
+
+func TestLocalStore(t *testing.T) {
+	s, err := NewLocal(t.TempDir())
+	if err != nil {
+		t.Fatalf("new local store: %v", err)
+	}
+	testStore(t, s)
+}
+
+func TestS3Store(t *testing.T) {
+	if _, err := os.Stat("/var/run/docker.sock"); err != nil && os.Getenv("DOCKER_HOST") == "" {
+		t.Skip("MinIO runs in Docker, which is not available")
+	}
+	ctx := context.Background()
+
+	container, err := minio.Run(ctx, "minio/minio:RELEASE.2024-01-16T16-07-38Z")
+	if err != nil {
+		t.Fatalf("start minio: %v", err)
+	}
+	t.Cleanup(func() { _ = container.Terminate(context.Background()) })
+
+	endpoint, err := container.ConnectionString(ctx)
+	if err != nil {
+		t.Fatalf("minio endpoint: %v", err)
+	}
+	s, err := NewS3(ctx, S3Config{
+		Endpoint:  endpoint,
+		Bucket:    "tests",
+		AccessKey: container.Username,
+		SecretKey: container.Password,
+	})
+	if err != nil {
+		t.Fatalf("new s3 store: %v", err)
+	}
+	testStore(t, s)
+}
@@ -0,0 +1,82 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+type localStore struct {
+	dir string
+}
+
+// NewLocal keeps blobs as files under dir, fanned out by the first two
+// characters of their checksum.
+func NewLocal(dir string) (Store, error) {
+	if err := os.MkdirAll(filepath.Join(dir, "tmp"), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create blob directory: %w", err)
+	}
+	return &localStore{dir: dir}, nil
+}
+
+func (s *localStore) Put(ctx context.Context, r io.Reader) (*Object, error) {
+	tmp, err := os.CreateTemp(filepath.Join(s.dir, "tmp"), "upload-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create blob file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	hr := newHashingReader(r)
+	_, err = io.Copy(tmp, hr)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to write blob: %w", err)
+	}
+
+	obj := hr.object()
+	path := s.path(obj.SHA256)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create blob directory: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return nil, fmt.Errorf("failed to store blob: %w", err)
+	}
+	return obj, nil
+}
+
+func (s *localStore) Open(ctx context.Context, sha256 string) (io.ReadCloser, error) {
+	if !ValidChecksum(sha256) {
+		return nil, ErrNotFound
+	}
+	f, err := os.Open(s.path(sha256))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open blob: %w", err)
+	}
+	return f, nil
+}
+
+func (s *localStore) Stat(ctx context.Context, sha256 string) (*Object, error) {
+	if !ValidChecksum(sha256) {
+		return nil, ErrNotFound
+	}
+	info, err := os.Stat(s.path(sha256))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat blob: %w", err)
+	}
+	return &Object{SHA256: sha256, Size: info.Size()}, nil
+}
+
+func (s *localStore) path(sha256 string) string {
+	return filepath.Join(s.dir, sha256[:2], sha256)
+}
@@ -0,0 +1,99 @@
+package blob
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/google/uuid"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+const s3PartSize = 16 * 1024 * 1024
+
+type S3Config struct {
+	Endpoint  string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	UseSSL    bool
+}
+
+type s3Store struct {
+	client *minio.Client
+	bucket string
+}
+
+// NewS3 keeps blobs in an S3-compatible bucket (AWS S3, MinIO), creating the
+// bucket if it does not exist.
+func NewS3(ctx context.Context, cfg S3Config) (Store, error) {
+	client, err := minio.New(cfg.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(cfg.AccessKey, cfg.SecretKey, ""),
+		Secure: cfg.UseSSL,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create S3 client: %w", err)
+	}
+
+	exists, err := client.BucketExists(ctx, cfg.Bucket)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check bucket %s: %w", cfg.Bucket, err)
+	}
+	if !exists {
+		if err := client.MakeBucket(ctx, cfg.Bucket, minio.MakeBucketOptions{}); err != nil {
+			return nil, fmt.Errorf("failed to create bucket %s: %w", cfg.Bucket, err)
+		}
+	}
+	return &s3Store{client: client, bucket: cfg.Bucket}, nil
+}
+
+// Put uploads under a temporary key first: the final key is the checksum,
+// known only once the whole stream has been read.
+func (s *s3Store) Put(ctx context.Context, r io.Reader) (*Object, error) {
+	tmpKey := "tmp/" + uuid.New().String()
+	hr := newHashingReader(r)
+	if _, err := s.client.PutObject(ctx, s.bucket, tmpKey, hr, -1, minio.PutObjectOptions{PartSize: s3PartSize}); err != nil {
+		return nil, fmt.Errorf("failed to upload blob: %w", err)
+	}
+	defer s.client.RemoveObject(context.Background(), s.bucket, tmpKey, minio.RemoveObjectOptions{})
+
+	obj := hr.object()
+	_, err := s.client.CopyObject(ctx,
+		minio.CopyDestOptions{Bucket: s.bucket, Object: s.key(obj.SHA256)},
+		minio.CopySrcOptions{Bucket: s.bucket, Object: tmpKey},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to store blob: %w", err)
+	}
+	return obj, nil
+}
+
+func (s *s3Store) Open(ctx context.Context, sha256 string) (io.ReadCloser, error) {
+	if _, err := s.Stat(ctx, sha256); err != nil {
+		return nil, err
+	}
+	obj, err := s.client.GetObject(ctx, s.bucket, s.key(sha256), minio.GetObjectOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open blob: %w", err)
+	}
+	return obj, nil
+}
+
+func (s *s3Store) Stat(ctx context.Context, sha256 string) (*Object, error) {
+	if !ValidChecksum(sha256) {
+		return nil, ErrNotFound
+	}
+	info, err := s.client.StatObject(ctx, s.bucket, s.key(sha256), minio.StatObjectOptions{})
+	if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat blob: %w", err)
+	}
+	return &Object{SHA256: sha256, Size: info.Size}, nil
+}
+
+func (s *s3Store) key(sha256 string) string {
+	return "sha256/" + sha256
+}
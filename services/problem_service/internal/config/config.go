@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strconv"
 	"strings"
 
 	"github.com/joho/godotenv"
@@ -20,6 +21,14 @@ type Config struct {
 	DBUser     string
 	DBPassword string
 	DBName     string
+
+	BlobBackend string
+	BlobDir     string
+	S3Endpoint  string
+	S3Bucket    string
+	S3AccessKey string
+	S3SecretKey string
+	S3UseSSL    bool
 }
 
 func ConfigInit() Config {
@@ -27,6 +36,8 @@ func ConfigInit() Config {
 		log.Println("No .env file found, relying on environment variables")
 	}
 
+	s3UseSSL, _ := strconv.ParseBool(getEnv("S3_USE_SSL", "false"))
+
 	return Config{
 		GRPCPort:           getEnv("GRPC_PORT", "8002"),
 		KafkaBrokers:       strings.Split(getEnv("KAFKA_BROKERS", "kafka:9092"), ","),
@@ -36,6 +47,13 @@ func ConfigInit() Config {
 		DBUser:             getEnv("DB_USER", "postgres"),
 		DBPassword:         getEnv("DB_PASSWORD", "admin"),
 		DBName:             getEnv("DB_NAME", "authdb"),
+		BlobBackend:        getEnv("BLOB_BACKEND", "local"),
+		BlobDir:            getEnv("BLOB_DIR", "/var/lib/code-checker/blobs"),
+		S3Endpoint:         getEnv("S3_ENDPOINT", "minio:9000"),
+		S3Bucket:           getEnv("S3_BUCKET", "test-files"),
+		S3AccessKey:        getEnv("S3_ACCESS_KEY", ""),
+		S3SecretKey:        getEnv("S3_SECRET_KEY", ""),
+		S3UseSSL:           s3UseSSL,
 	}
 }
 
@@ -2,10 +2,13 @@ package handler
 
 import (
 	"context"
+	"errors"
+	"io"
 	"time"
 
 	problem_service "github.com/DeadlyParkour777/code-checker/pkg/problem"
 	"github.com/DeadlyParkour777/code-checker/services/problem_service/internal/service"
+	"github.com/DeadlyParkour777/code-checker/services/problem_service/internal/types"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -20,17 +23,49 @@ func NewGrpcHandler(service service.Service) *GrpcHandler {
 }
 
 func (h *GrpcHandler) CreateProblem(ctx context.Context, req *problem_service.CreateProblemRequest) (*problem_service.Problem, error) {
-	problem, err := h.service.CreateProblem(ctx, req.GetTitle(), req.GetDescription())
+	if req.GetTimeLimitMs() < 0 || req.GetMemoryLimitMb() < 0 || req.GetOutputLimitKb() < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "limits must not be negative")
+	}
+	if req.GetComparator() != "" && !types.IsValidComparator(req.GetComparator()) {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown comparator: %s", req.GetComparator())
+	}
+	if req.GetFloatAbsEps() < 0 || req.GetFloatRelEps() < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "float tolerances must not be negative")
+	}
+	switch req.GetType() {
+	case "", types.ProblemTypeStandard, types.ProblemTypeInteractive:
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unknown problem type: %s", req.GetType())
+	}
+	switch req.GetScoringMode() {
+	case "", types.ScoringModeICPC, types.ScoringModePoints:
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unknown scoring mode: %s", req.GetScoringMode())
+	}
+	for _, module := range req.GetGoModules() {
+		if !types.IsValidGoModule(module) {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid go module path: %s", module)
+		}
+	}
+
+	problem, err := h.service.CreateProblem(ctx, &types.Problem{
+		Title:         req.GetTitle(),
+		Description:   req.GetDescription(),
+		TimeLimitMs:   int(req.GetTimeLimitMs()),
+		MemoryLimitMB: int(req.GetMemoryLimitMb()),
+		OutputLimitKB: int(req.GetOutputLimitKb()),
+		Comparator:    req.GetComparator(),
+		FloatAbsEps:   req.GetFloatAbsEps(),
+		FloatRelEps:   req.GetFloatRelEps(),
+		Type:          req.GetType(),
+		ScoringMode:   req.GetScoringMode(),
+		GoModules:     req.GetGoModules(),
+	})
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to create problem: %v", err)
 	}
 
-	return &problem_service.Problem{
-		Id:          problem.ID,
-		Title:       problem.Title,
-		Description: problem.Description,
-		CreatedAt:   problem.CreatedAt.Format(time.RFC3339),
-	}, nil
+	return toProblemPB(problem), nil
 }
 
 func (h *GrpcHandler) GetProblem(ctx context.Context, req *problem_service.GetProblemRequest) (*problem_service.Problem, error) {
@@ -39,12 +74,7 @@ func (h *GrpcHandler) GetProblem(ctx context.Context, req *problem_service.GetPr
 		return nil, status.Errorf(codes.NotFound, "problem not found: %v", err)
 	}
 
-	return &problem_service.Problem{
-		Id:          problem.ID,
-		Title:       problem.Title,
-		Description: problem.Description,
-		CreatedAt:   problem.CreatedAt.Format(time.RFC3339),
-	}, nil
+	return toProblemPB(problem), nil
 }
 
 func (h *GrpcHandler) ListProblems(ctx context.Context, req *problem_service.ListProblemsRequest) (*problem_service.ListProblemsResponse, error) {
@@ -55,29 +85,34 @@ func (h *GrpcHandler) ListProblems(ctx context.Context, req *problem_service.Lis
 
 	var pbProblems []*problem_service.Problem
 	for _, problem := range problems {
-		pbProblems = append(pbProblems, &problem_service.Problem{
-			Id:          problem.ID,
-			Title:       problem.Title,
-			Description: problem.Description,
-			CreatedAt:   problem.CreatedAt.Format(time.RFC3339),
-		})
+		pbProblems = append(pbProblems, toProblemPB(problem))
 	}
 
 	return &problem_service.ListProblemsResponse{Problems: pbProblems}, nil
 }
 
 func (h *GrpcHandler) CreateTestCase(ctx context.Context, req *problem_service.CreateTestCaseRequest) (*problem_service.TestCase, error) {
-	testCase, err := h.service.CreateTestCase(ctx, req.GetProblemId(), req.GetInputData(), req.GetOutputData())
+	if req.GetPoints() < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "points must not be negative")
+	}
+
+	testCase, err := h.service.CreateTestCase(ctx, &types.TestCase{
+		ProblemID:    req.GetProblemId(),
+		Input:        req.GetInputData(),
+		Output:       req.GetOutputData(),
+		Points:       int(req.GetPoints()),
+		GroupID:      req.GetGroupId(),
+		InputSHA256:  req.GetInputSha256(),
+		OutputSHA256: req.GetOutputSha256(),
+	})
 	if err != nil {
+		if errors.Is(err, service.ErrUnknownTestGroup) || errors.Is(err, service.ErrUnknownTestFile) {
+			return nil, status.Errorf(codes.InvalidArgument, "failed to create test case: %v", err)
+		}
 		return nil, status.Errorf(codes.Internal, "failed to create test case: %v", err)
 	}
 
-	return &problem_service.TestCase{
-		Id:         testCase.ID,
-		ProblemId:  testCase.ProblemID,
-		InputData:  testCase.Input,
-		OutputData: testCase.Output,
-	}, nil
+	return toTestCasePB(testCase), nil
 }
 
 func (h *GrpcHandler) GetTestCases(ctx context.Context, req *problem_service.GetTestCasesRequest) (*problem_service.GetTestCasesResponse, error) {
@@ -86,15 +121,223 @@ func (h *GrpcHandler) GetTestCases(ctx context.Context, req *problem_service.Get
 		return nil, status.Errorf(codes.Internal, "failed to get test cases: %v", err)
 	}
 
+	groups, err := h.service.GetTestGroups(ctx, req.GetProblemId())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get test groups: %v", err)
+	}
+
 	var pbTestCases []*problem_service.TestCase
 	for _, tc := range testCases {
-		pbTestCases = append(pbTestCases, &problem_service.TestCase{
-			Id:         tc.ID,
-			ProblemId:  tc.ProblemID,
-			InputData:  tc.Input,
-			OutputData: tc.Output,
-		})
+		pbTestCases = append(pbTestCases, toTestCasePB(tc))
 	}
 
-	return &problem_service.GetTestCasesResponse{TestCases: pbTestCases}, nil
+	return &problem_service.GetTestCasesResponse{TestCases: pbTestCases, Groups: toTestGroupsPB(groups)}, nil
+}
+
+// testFileChunkSize keeps chunks well below the default 4 MiB gRPC message limit.
+const testFileChunkSize = 1024 * 1024
+
+func (h *GrpcHandler) UploadTestFile(stream problem_service.ProblemService_UploadTestFileServer) error {
+	obj, err := h.service.UploadTestFile(stream.Context(), &chunkReader{stream: stream})
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to upload test file: %v", err)
+	}
+	return stream.SendAndClose(&problem_service.TestFile{Sha256: obj.SHA256, Size: obj.Size})
+}
+
+func (h *GrpcHandler) ReadTestFile(req *problem_service.ReadTestFileRequest, stream problem_service.ProblemService_ReadTestFileServer) error {
+	r, err := h.service.OpenTestFile(stream.Context(), req.GetSha256())
+	if errors.Is(err, service.ErrUnknownTestFile) {
+		return status.Errorf(codes.NotFound, "test file not found: %v", err)
+	}
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to open test file: %v", err)
+	}
+	defer r.Close()
+
+	buf := make([]byte, testFileChunkSize)
+	for {
+		n, err := io.ReadFull(r, buf)
+		if n > 0 {
+			if err := stream.Send(&problem_service.TestFileChunk{Data: buf[:n]}); err != nil {
+				return err
+			}
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil
+		}
+		if err != nil {
+			return status.Errorf(codes.Internal, "failed to read test file: %v", err)
+		}
+	}
+}
+
+// chunkReader reads an uploaded test file from the stream it arrives in.
+type chunkReader struct {
+	stream problem_service.ProblemService_UploadTestFileServer
+	buf    []byte
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		chunk, err := r.stream.Recv()
+		if err != nil {
+			return 0, err
+		}
+		r.buf = chunk.GetData()
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+func (h *GrpcHandler) CreateTestGroup(ctx context.Context, req *problem_service.CreateTestGroupRequest) (*problem_service.TestGroup, error) {
+	if req.GetProblemId() == "" || req.GetName() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "problem_id and name are required")
+	}
+	if req.GetScore() < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "score must not be negative")
+	}
+	switch req.GetAggregation() {
+	case "", types.GroupAggregationAllOrNothing, types.GroupAggregationMin:
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unknown aggregation: %s", req.GetAggregation())
+	}
+
+	group, err := h.service.CreateTestGroup(ctx, &types.TestGroup{
+		ProblemID:   req.GetProblemId(),
+		Name:        req.GetName(),
+		Score:       int(req.GetScore()),
+		Aggregation: req.GetAggregation(),
+		DependsOn:   req.GetDependsOn(),
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrUnknownTestGroup) {
+			return nil, status.Errorf(codes.InvalidArgument, "failed to create test group: %v", err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to create test group: %v", err)
+	}
+
+	return toTestGroupPB(group), nil
+}
+
+func (h *GrpcHandler) ListTestGroups(ctx context.Context, req *problem_service.ListTestGroupsRequest) (*problem_service.ListTestGroupsResponse, error) {
+	groups, err := h.service.GetTestGroups(ctx, req.GetProblemId())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list test groups: %v", err)
+	}
+
+	return &problem_service.ListTestGroupsResponse{Groups: toTestGroupsPB(groups)}, nil
+}
+
+func (h *GrpcHandler) SetChecker(ctx context.Context, req *problem_service.SetProgramRequest) (*problem_service.Program, error) {
+	if req.GetProblemId() == "" || req.GetLanguage() == "" || req.GetSourceCode() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "problem_id, language and source_code are required")
+	}
+
+	checker, err := h.service.SetChecker(ctx, req.GetProblemId(), req.GetLanguage(), req.GetSourceCode())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to set checker: %v", err)
+	}
+
+	return toProgramPB(checker), nil
+}
+
+func (h *GrpcHandler) GetChecker(ctx context.Context, req *problem_service.GetProgramRequest) (*problem_service.Program, error) {
+	checker, err := h.service.GetChecker(ctx, req.GetProblemId())
+	if errors.Is(err, service.ErrProgramNotSet) {
+		return nil, status.Errorf(codes.NotFound, "checker not found: %v", err)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get checker: %v", err)
+	}
+
+	return toProgramPB(checker), nil
+}
+
+func (h *GrpcHandler) SetInteractor(ctx context.Context, req *problem_service.SetProgramRequest) (*problem_service.Program, error) {
+	if req.GetProblemId() == "" || req.GetLanguage() == "" || req.GetSourceCode() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "problem_id, language and source_code are required")
+	}
+
+	interactor, err := h.service.SetInteractor(ctx, req.GetProblemId(), req.GetLanguage(), req.GetSourceCode())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to set interactor: %v", err)
+	}
+
+	return toProgramPB(interactor), nil
+}
+
+func (h *GrpcHandler) GetInteractor(ctx context.Context, req *problem_service.GetProgramRequest) (*problem_service.Program, error) {
+	interactor, err := h.service.GetInteractor(ctx, req.GetProblemId())
+	if errors.Is(err, service.ErrProgramNotSet) {
+		return nil, status.Errorf(codes.NotFound, "interactor not found: %v", err)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get interactor: %v", err)
+	}
+
+	return toProgramPB(interactor), nil
+}
+
+func toProblemPB(problem *types.Problem) *problem_service.Problem {
+	return &problem_service.Problem{
+		Id:            problem.ID,
+		Title:         problem.Title,
+		Description:   problem.Description,
+		CreatedAt:     problem.CreatedAt.Format(time.RFC3339),
+		TimeLimitMs:   int32(problem.TimeLimitMs),
+		MemoryLimitMb: int32(problem.MemoryLimitMB),
+		OutputLimitKb: int32(problem.OutputLimitKB),
+		Comparator:    problem.Comparator,
+		FloatAbsEps:   problem.FloatAbsEps,
+		FloatRelEps:   problem.FloatRelEps,
+		Type:          problem.Type,
+		ScoringMode:   problem.ScoringMode,
+		TestsVersion:  problem.TestsVersion,
+		GoModules:     problem.GoModules,
+	}
+}
+
+func toTestCasePB(testCase *types.TestCase) *problem_service.TestCase {
+	return &problem_service.TestCase{
+		Id:           testCase.ID,
+		ProblemId:    testCase.ProblemID,
+		InputData:    testCase.Input,
+		OutputData:   testCase.Output,
+		Points:       int32(testCase.Points),
+		GroupId:      testCase.GroupID,
+		InputSize:    testCase.InputSize,
+		InputSha256:  testCase.InputSHA256,
+		OutputSize:   testCase.OutputSize,
+		OutputSha256: testCase.OutputSHA256,
+	}
+}
+
+func toTestGroupPB(group *types.TestGroup) *problem_service.TestGroup {
+	return &problem_service.TestGroup{
+		Id:          group.ID,
+		ProblemId:   group.ProblemID,
+		Name:        group.Name,
+		Score:       int32(group.Score),
+		Aggregation: group.Aggregation,
+		DependsOn:   group.DependsOn,
+	}
+}
+
+func toTestGroupsPB(groups []*types.TestGroup) []*problem_service.TestGroup {
+	var pbGroups []*problem_service.TestGroup
+	for _, group := range groups {
+		pbGroups = append(pbGroups, toTestGroupPB(group))
+	}
+	return pbGroups
+}
+
+func toProgramPB(program *types.Program) *problem_service.Program {
+	return &problem_service.Program{
+		ProblemId:  program.ProblemID,
+		Language:   program.Language,
+		SourceCode: program.SourceCode,
+		UpdatedAt:  program.UpdatedAt.Format(time.RFC3339),
+	}
 }
@@ -1,30 +1,43 @@
 package handler
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"fmt"
+	"io"
+	"strings"
 	"testing"
 	"time"
 
 	problem_service "github.com/DeadlyParkour777/code-checker/pkg/problem"
+	"github.com/DeadlyParkour777/code-checker/services/problem_service/internal/blob"
+	svc "github.com/DeadlyParkour777/code-checker/services/problem_service/internal/service"
 	"github.com/DeadlyParkour777/code-checker/services/problem_service/internal/types"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
 type fakeService struct {
-	createProblemFn  func(ctx context.Context, title, description string) (*types.Problem, error)
-	getProblemFn     func(ctx context.Context, id string) (*types.Problem, error)
-	listProblemsFn   func(ctx context.Context) ([]*types.Problem, error)
-	createTestCaseFn func(ctx context.Context, problemID, input, output string) (*types.TestCase, error)
-	getTestCasesFn   func(ctx context.Context, problemID string) ([]*types.TestCase, error)
+	createProblemFn   func(ctx context.Context, problem *types.Problem) (*types.Problem, error)
+	getProblemFn      func(ctx context.Context, id string) (*types.Problem, error)
+	listProblemsFn    func(ctx context.Context) ([]*types.Problem, error)
+	createTestCaseFn  func(ctx context.Context, testCase *types.TestCase) (*types.TestCase, error)
+	getTestCasesFn    func(ctx context.Context, problemID string) ([]*types.TestCase, error)
+	openTestFileFn    func(ctx context.Context, sha256 string) (io.ReadCloser, error)
+	createTestGroupFn func(ctx context.Context, group *types.TestGroup) (*types.TestGroup, error)
+	getTestGroupsFn   func(ctx context.Context, problemID string) ([]*types.TestGroup, error)
+	setCheckerFn      func(ctx context.Context, problemID, language, sourceCode string) (*types.Program, error)
+	getCheckerFn      func(ctx context.Context, problemID string) (*types.Program, error)
+	setInteractorFn   func(ctx context.Context, problemID, language, sourceCode string) (*types.Program, error)
+	getInteractorFn   func(ctx context.Context, problemID string) (*types.Program, error)
 }
 
-func (f *fakeService) CreateProblem(ctx context.Context, title, description string) (*types.Problem, error) {
+func (f *fakeService) CreateProblem(ctx context.Context, problem *types.Problem) (*types.Problem, error) {
 	if f.createProblemFn == nil {
 		return nil, errors.New("CreateProblem not implemented")
 	}
-	return f.createProblemFn(ctx, title, description)
+	return f.createProblemFn(ctx, problem)
 }
 
 func (f *fakeService) GetProblem(ctx context.Context, id string) (*types.Problem, error) {
@@ -41,11 +54,11 @@ func (f *fakeService) ListProblems(ctx context.Context) ([]*types.Problem, error
 	return f.listProblemsFn(ctx)
 }
 
-func (f *fakeService) CreateTestCase(ctx context.Context, problemID, input, output string) (*types.TestCase, error) {
+func (f *fakeService) CreateTestCase(ctx context.Context, testCase *types.TestCase) (*types.TestCase, error) {
 	if f.createTestCaseFn == nil {
 		return nil, errors.New("CreateTestCase not implemented")
 	}
-	return f.createTestCaseFn(ctx, problemID, input, output)
+	return f.createTestCaseFn(ctx, testCase)
 }
 
 func (f *fakeService) GetTestCases(ctx context.Context, problemID string) ([]*types.TestCase, error) {
@@ -55,30 +68,97 @@ func (f *fakeService) GetTestCases(ctx context.Context, problemID string) ([]*ty
 	return f.getTestCasesFn(ctx, problemID)
 }
 
+func (f *fakeService) UploadTestFile(ctx context.Context, r io.Reader) (*blob.Object, error) {
+	return nil, errors.New("UploadTestFile not implemented")
+}
+
+func (f *fakeService) OpenTestFile(ctx context.Context, sha256 string) (io.ReadCloser, error) {
+	if f.openTestFileFn == nil {
+		return nil, errors.New("OpenTestFile not implemented")
+	}
+	return f.openTestFileFn(ctx, sha256)
+}
+
+func (f *fakeService) CreateTestGroup(ctx context.Context, group *types.TestGroup) (*types.TestGroup, error) {
+	if f.createTestGroupFn == nil {
+		return nil, errors.New("CreateTestGroup not implemented")
+	}
+	return f.createTestGroupFn(ctx, group)
+}
+
+func (f *fakeService) GetTestGroups(ctx context.Context, problemID string) ([]*types.TestGroup, error) {
+	if f.getTestGroupsFn == nil {
+		return nil, errors.New("GetTestGroups not implemented")
+	}
+	return f.getTestGroupsFn(ctx, problemID)
+}
+
+func (f *fakeService) SetChecker(ctx context.Context, problemID, language, sourceCode string) (*types.Program, error) {
+	if f.setCheckerFn == nil {
+		return nil, errors.New("SetChecker not implemented")
+	}
+	return f.setCheckerFn(ctx, problemID, language, sourceCode)
+}
+
+func (f *fakeService) GetChecker(ctx context.Context, problemID string) (*types.Program, error) {
+	if f.getCheckerFn == nil {
+		return nil, errors.New("GetChecker not implemented")
+	}
+	return f.getCheckerFn(ctx, problemID)
+}
+
+func (f *fakeService) SetInteractor(ctx context.Context, problemID, language, sourceCode string) (*types.Program, error) {
+	if f.setInteractorFn == nil {
+		return nil, errors.New("SetInteractor not implemented")
+	}
+	return f.setInteractorFn(ctx, problemID, language, sourceCode)
+}
+
+func (f *fakeService) GetInteractor(ctx context.Context, problemID string) (*types.Program, error) {
+	if f.getInteractorFn == nil {
+		return nil, errors.New("GetInteractor not implemented")
+	}
+	return f.getInteractorFn(ctx, problemID)
+}
+
 func TestCreateProblem(t *testing.T) {
 	fixedTime := time.Date(2024, 11, 1, 9, 0, 0, 0, time.UTC)
 	service := &fakeService{
-		createProblemFn: func(_ context.Context, title, description string) (*types.Problem, error) {
-			return &types.Problem{ID: "p1", Title: title, Description: description, CreatedAt: fixedTime}, nil
+		createProblemFn: func(_ context.Context, problem *types.Problem) (*types.Problem, error) {
+			problem.ID = "p1"
+			problem.CreatedAt = fixedTime
+			return problem, nil
 		},
 	}
 	handler := NewGrpcHandler(service)
 
-	resp, err := handler.CreateProblem(context.Background(), &problem_service.CreateProblemRequest{Title: "T", Description: "D"})
+	resp, err := handler.CreateProblem(context.Background(), &problem_service.CreateProblemRequest{
+		Title:         "T",
+		Description:   "D",
+		TimeLimitMs:   1000,
+		MemoryLimitMb: 256,
+		GoModules:     []string{"gonum.org/v1/gonum"},
+	})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 	if resp.GetId() != "p1" {
 		t.Fatalf("unexpected id: %s", resp.GetId())
 	}
+	if resp.GetTimeLimitMs() != 1000 || resp.GetMemoryLimitMb() != 256 {
+		t.Fatalf("unexpected limits: %d ms, %d MB", resp.GetTimeLimitMs(), resp.GetMemoryLimitMb())
+	}
 	if resp.GetCreatedAt() != fixedTime.Format(time.RFC3339) {
 		t.Fatalf("unexpected created_at: %s", resp.GetCreatedAt())
 	}
+	if len(resp.GetGoModules()) != 1 || resp.GetGoModules()[0] != "gonum.org/v1/gonum" {
+		t.Fatalf("unexpected go modules: %v", resp.GetGoModules())
+	}
 }
 
 func TestCreateProblem_Error(t *testing.T) {
 	service := &fakeService{
-		createProblemFn: func(_ context.Context, _, _ string) (*types.Problem, error) {
+		createProblemFn: func(_ context.Context, _ *types.Problem) (*types.Problem, error) {
 			return nil, errors.New("boom")
 		},
 	}
@@ -90,6 +170,53 @@ func TestCreateProblem_Error(t *testing.T) {
 	}
 }
 
+func TestCreateProblem_NegativeLimits(t *testing.T) {
+	handler := NewGrpcHandler(&fakeService{})
+
+	_, err := handler.CreateProblem(context.Background(), &problem_service.CreateProblemRequest{Title: "T", TimeLimitMs: -1})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected invalid argument, got %v", status.Code(err))
+	}
+}
+
+func TestCreateProblem_UnknownComparator(t *testing.T) {
+	handler := NewGrpcHandler(&fakeService{})
+
+	_, err := handler.CreateProblem(context.Background(), &problem_service.CreateProblemRequest{Title: "T", Comparator: "fuzzy"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected invalid argument, got %v", status.Code(err))
+	}
+}
+
+func TestCreateProblem_UnknownType(t *testing.T) {
+	handler := NewGrpcHandler(&fakeService{})
+
+	_, err := handler.CreateProblem(context.Background(), &problem_service.CreateProblemRequest{Title: "T", Type: "output-only"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected invalid argument, got %v", status.Code(err))
+	}
+}
+
+func TestCreateProblem_UnknownScoringMode(t *testing.T) {
+	handler := NewGrpcHandler(&fakeService{})
+
+	_, err := handler.CreateProblem(context.Background(), &problem_service.CreateProblemRequest{Title: "T", ScoringMode: "best-of"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected invalid argument, got %v", status.Code(err))
+	}
+}
+
+func TestCreateProblem_InvalidGoModule(t *testing.T) {
+	handler := NewGrpcHandler(&fakeService{})
+
+	for _, module := range []string{"gonum.org/v1/gonum@v0.15.1", "fmt", "../gonum.org/v1/gonum", ""} {
+		_, err := handler.CreateProblem(context.Background(), &problem_service.CreateProblemRequest{Title: "T", GoModules: []string{module}})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Fatalf("expected invalid argument for %q, got %v", module, status.Code(err))
+		}
+	}
+}
+
 func TestGetProblem(t *testing.T) {
 	fixedTime := time.Date(2024, 11, 2, 9, 0, 0, 0, time.UTC)
 	service := &fakeService{
@@ -159,8 +286,9 @@ func TestListProblems_Error(t *testing.T) {
 
 func TestCreateTestCase(t *testing.T) {
 	service := &fakeService{
-		createTestCaseFn: func(_ context.Context, problemID, input, output string) (*types.TestCase, error) {
-			return &types.TestCase{ID: "tc-1", ProblemID: problemID, Input: input, Output: output}, nil
+		createTestCaseFn: func(_ context.Context, testCase *types.TestCase) (*types.TestCase, error) {
+			testCase.ID = "tc-1"
+			return testCase, nil
 		},
 	}
 	handler := NewGrpcHandler(service)
@@ -169,6 +297,8 @@ func TestCreateTestCase(t *testing.T) {
 		ProblemId:  "p1",
 		InputData:  "1 2",
 		OutputData: "3",
+		Points:     5,
+		GroupId:    "g1",
 	})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -176,11 +306,37 @@ func TestCreateTestCase(t *testing.T) {
 	if resp.GetId() != "tc-1" {
 		t.Fatalf("unexpected id: %s", resp.GetId())
 	}
+	if resp.GetPoints() != 5 || resp.GetGroupId() != "g1" {
+		t.Fatalf("unexpected points or group: %d %s", resp.GetPoints(), resp.GetGroupId())
+	}
+}
+
+func TestCreateTestCase_UnknownGroup(t *testing.T) {
+	service := &fakeService{
+		createTestCaseFn: func(_ context.Context, _ *types.TestCase) (*types.TestCase, error) {
+			return nil, fmt.Errorf("%w: g9", svc.ErrUnknownTestGroup)
+		},
+	}
+	handler := NewGrpcHandler(service)
+
+	_, err := handler.CreateTestCase(context.Background(), &problem_service.CreateTestCaseRequest{ProblemId: "p1", GroupId: "g9"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected invalid argument, got %v", status.Code(err))
+	}
+}
+
+func TestCreateTestCase_NegativePoints(t *testing.T) {
+	handler := NewGrpcHandler(&fakeService{})
+
+	_, err := handler.CreateTestCase(context.Background(), &problem_service.CreateTestCaseRequest{ProblemId: "p1", Points: -1})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected invalid argument, got %v", status.Code(err))
+	}
 }
 
 func TestCreateTestCase_Error(t *testing.T) {
 	service := &fakeService{
-		createTestCaseFn: func(_ context.Context, _, _, _ string) (*types.TestCase, error) {
+		createTestCaseFn: func(_ context.Context, _ *types.TestCase) (*types.TestCase, error) {
 			return nil, errors.New("db")
 		},
 	}
@@ -195,7 +351,10 @@ func TestCreateTestCase_Error(t *testing.T) {
 func TestGetTestCases(t *testing.T) {
 	service := &fakeService{
 		getTestCasesFn: func(_ context.Context, problemID string) ([]*types.TestCase, error) {
-			return []*types.TestCase{{ID: "tc-1", ProblemID: problemID}}, nil
+			return []*types.TestCase{{ID: "tc-1", ProblemID: problemID, GroupID: "g1"}}, nil
+		},
+		getTestGroupsFn: func(_ context.Context, problemID string) ([]*types.TestGroup, error) {
+			return []*types.TestGroup{{ID: "g1", ProblemID: problemID, Score: 30}}, nil
 		},
 	}
 	handler := NewGrpcHandler(service)
@@ -207,6 +366,9 @@ func TestGetTestCases(t *testing.T) {
 	if len(resp.GetTestCases()) != 1 {
 		t.Fatalf("expected 1 test case, got %d", len(resp.GetTestCases()))
 	}
+	if len(resp.GetGroups()) != 1 || resp.GetGroups()[0].GetScore() != 30 {
+		t.Fatalf("unexpected groups: %v", resp.GetGroups())
+	}
 }
 
 func TestGetTestCases_Error(t *testing.T) {
@@ -222,3 +384,214 @@ func TestGetTestCases_Error(t *testing.T) {
 		t.Fatalf("expected internal, got %v", status.Code(err))
 	}
 }
+
+func TestCreateTestGroup(t *testing.T) {
+	service := &fakeService{
+		createTestGroupFn: func(_ context.Context, group *types.TestGroup) (*types.TestGroup, error) {
+			group.ID = "g2"
+			return group, nil
+		},
+	}
+	handler := NewGrpcHandler(service)
+
+	resp, err := handler.CreateTestGroup(context.Background(), &problem_service.CreateTestGroupRequest{
+		ProblemId:   "p1",
+		Name:        "n <= 1000",
+		Score:       40,
+		Aggregation: "min",
+		DependsOn:   []string{"g1"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.GetId() != "g2" || resp.GetScore() != 40 || len(resp.GetDependsOn()) != 1 {
+		t.Fatalf("unexpected group: %v", resp)
+	}
+}
+
+func TestCreateTestGroup_InvalidArgument(t *testing.T) {
+	handler := NewGrpcHandler(&fakeService{})
+
+	requests := []*problem_service.CreateTestGroupRequest{
+		{Name: "g"},
+		{ProblemId: "p1", Name: "g", Score: -1},
+		{ProblemId: "p1", Name: "g", Aggregation: "sum"},
+	}
+	for _, req := range requests {
+		_, err := handler.CreateTestGroup(context.Background(), req)
+		if status.Code(err) != codes.InvalidArgument {
+			t.Fatalf("expected invalid argument for %v, got %v", req, status.Code(err))
+		}
+	}
+}
+
+func TestCreateTestGroup_UnknownDependency(t *testing.T) {
+	service := &fakeService{
+		createTestGroupFn: func(_ context.Context, _ *types.TestGroup) (*types.TestGroup, error) {
+			return nil, fmt.Errorf("%w: g9", svc.ErrUnknownTestGroup)
+		},
+	}
+	handler := NewGrpcHandler(service)
+
+	_, err := handler.CreateTestGroup(context.Background(), &problem_service.CreateTestGroupRequest{ProblemId: "p1", Name: "g", DependsOn: []string{"g9"}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected invalid argument, got %v", status.Code(err))
+	}
+}
+
+func TestSetChecker(t *testing.T) {
+	fixedTime := time.Date(2024, 11, 2, 9, 0, 0, 0, time.UTC)
+	service := &fakeService{
+		setCheckerFn: func(_ context.Context, problemID, language, sourceCode string) (*types.Program, error) {
+			return &types.Program{
+				ProblemID:  problemID,
+				Kind:       types.ProgramKindChecker,
+				Language:   language,
+				SourceCode: sourceCode,
+				UpdatedAt:  fixedTime,
+			}, nil
+		},
+	}
+	handler := NewGrpcHandler(service)
+
+	resp, err := handler.SetChecker(context.Background(), &problem_service.SetProgramRequest{
+		ProblemId:  "p1",
+		Language:   "python",
+		SourceCode: "print('ok')",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.GetProblemId() != "p1" || resp.GetLanguage() != "python" {
+		t.Fatalf("unexpected checker: %v", resp)
+	}
+	if resp.GetUpdatedAt() != fixedTime.Format(time.RFC3339) {
+		t.Fatalf("unexpected updated_at: %s", resp.GetUpdatedAt())
+	}
+}
+
+func TestSetChecker_MissingFields(t *testing.T) {
+	handler := NewGrpcHandler(&fakeService{})
+
+	_, err := handler.SetChecker(context.Background(), &problem_service.SetProgramRequest{ProblemId: "p1"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected invalid argument, got %v", status.Code(err))
+	}
+}
+
+func TestGetChecker_NotFound(t *testing.T) {
+	service := &fakeService{
+		getCheckerFn: func(_ context.Context, _ string) (*types.Program, error) {
+			return nil, fmt.Errorf("%w: checker", svc.ErrProgramNotSet)
+		},
+	}
+	handler := NewGrpcHandler(service)
+
+	_, err := handler.GetChecker(context.Background(), &problem_service.GetProgramRequest{ProblemId: "p1"})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("expected not found, got %v", status.Code(err))
+	}
+}
+
+func TestGetChecker_Error(t *testing.T) {
+	service := &fakeService{
+		getCheckerFn: func(_ context.Context, _ string) (*types.Program, error) {
+			return nil, errors.New("db")
+		},
+	}
+	handler := NewGrpcHandler(service)
+
+	_, err := handler.GetChecker(context.Background(), &problem_service.GetProgramRequest{ProblemId: "p1"})
+	if status.Code(err) != codes.Internal {
+		t.Fatalf("expected internal, got %v", status.Code(err))
+	}
+}
+
+func TestSetInteractor(t *testing.T) {
+	service := &fakeService{
+		setInteractorFn: func(_ context.Context, problemID, language, sourceCode string) (*types.Program, error) {
+			return &types.Program{ProblemID: problemID, Kind: types.ProgramKindInteractor, Language: language, SourceCode: sourceCode}, nil
+		},
+	}
+	handler := NewGrpcHandler(service)
+
+	resp, err := handler.SetInteractor(context.Background(), &problem_service.SetProgramRequest{
+		ProblemId:  "p1",
+		Language:   "go",
+		SourceCode: "package main",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.GetLanguage() != "go" || resp.GetSourceCode() != "package main" {
+		t.Fatalf("unexpected interactor: %v", resp)
+	}
+}
+
+func TestGetInteractor_NotFound(t *testing.T) {
+	service := &fakeService{
+		getInteractorFn: func(_ context.Context, _ string) (*types.Program, error) {
+			return nil, fmt.Errorf("%w: interactor", svc.ErrProgramNotSet)
+		},
+	}
+	handler := NewGrpcHandler(service)
+
+	_, err := handler.GetInteractor(context.Background(), &problem_service.GetProgramRequest{ProblemId: "p1"})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("expected not found, got %v", status.Code(err))
+	}
+}
+
+func TestGetInteractor_Error(t *testing.T) {
+	service := &fakeService{
+		getInteractorFn: func(_ context.Context, _ string) (*types.Program, error) {
+			return nil, errors.New("db")
+		},
+	}
+	handler := NewGrpcHandler(service)
+
+	_, err := handler.GetInteractor(context.Background(), &problem_service.GetProgramRequest{ProblemId: "p1"})
+	if status.Code(err) != codes.Internal {
+		t.Fatalf("expected internal, got %v", status.Code(err))
+	}
+}
+
+type fakeReadTestFileStream struct {
+	problem_service.ProblemService_ReadTestFileServer
+	chunks [][]byte
+}
+
+func (s *fakeReadTestFileStream) Context() context.Context {
+	return context.Background()
+}
+
+func (s *fakeReadTestFileStream) Send(chunk *problem_service.TestFileChunk) error {
+	s.chunks = append(s.chunks, append([]byte(nil), chunk.GetData()...))
+	return nil
+}
+
+func TestReadTestFile(t *testing.T) {
+	data := strings.Repeat("x", testFileChunkSize*2+10)
+	service := &fakeService{
+		openTestFileFn: func(_ context.Context, sha256 string) (io.ReadCloser, error) {
+			if sha256 == "missing" {
+				return nil, svc.ErrUnknownTestFile
+			}
+			return io.NopCloser(strings.NewReader(data)), nil
+		},
+	}
+	handler := NewGrpcHandler(service)
+
+	stream := &fakeReadTestFileStream{}
+	if err := handler.ReadTestFile(&problem_service.ReadTestFileRequest{Sha256: "abc"}, stream); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(stream.chunks) != 3 || string(bytes.Join(stream.chunks, nil)) != data {
+		t.Fatalf("unexpected chunks: %d", len(stream.chunks))
+	}
+
+	err := handler.ReadTestFile(&problem_service.ReadTestFileRequest{Sha256: "missing"}, &fakeReadTestFileStream{})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("expected not found, got %v", status.Code(err))
+	}
+}
@@ -3,23 +3,49 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
+	"strings"
 	"time"
 
+	"github.com/DeadlyParkour777/code-checker/services/problem_service/internal/blob"
 	"github.com/DeadlyParkour777/code-checker/services/problem_service/internal/store"
 	"github.com/DeadlyParkour777/code-checker/services/problem_service/internal/types"
 	"github.com/segmentio/kafka-go"
 )
 
 type Service interface {
-	CreateProblem(ctx context.Context, title, description string) (*types.Problem, error)
+	CreateProblem(ctx context.Context, problem *types.Problem) (*types.Problem, error)
 	GetProblem(ctx context.Context, id string) (*types.Problem, error)
 	ListProblems(ctx context.Context) ([]*types.Problem, error)
-	CreateTestCase(ctx context.Context, problemID, input, output string) (*types.TestCase, error)
+	CreateTestCase(ctx context.Context, testCase *types.TestCase) (*types.TestCase, error)
 	GetTestCases(ctx context.Context, problemID string) ([]*types.TestCase, error)
+	UploadTestFile(ctx context.Context, r io.Reader) (*blob.Object, error)
+	OpenTestFile(ctx context.Context, sha256 string) (io.ReadCloser, error)
+	CreateTestGroup(ctx context.Context, group *types.TestGroup) (*types.TestGroup, error)
+	GetTestGroups(ctx context.Context, problemID string) ([]*types.TestGroup, error)
+	SetChecker(ctx context.Context, problemID, language, sourceCode string) (*types.Program, error)
+	GetChecker(ctx context.Context, problemID string) (*types.Program, error)
+	SetInteractor(ctx context.Context, problemID, language, sourceCode string) (*types.Program, error)
+	GetInteractor(ctx context.Context, problemID string) (*types.Program, error)
 }
 
+const (
+	DefaultTimeLimitMs   = 2000
+	DefaultMemoryLimitMB = 128
+	DefaultOutputLimitKB = 16 * 1024
+	DefaultFloatEps      = 1e-6
+	DefaultTestPoints    = 1
+)
+
+var (
+	ErrUnknownTestGroup = errors.New("unknown test group")
+	ErrUnknownTestFile  = errors.New("unknown test file")
+	ErrProgramNotSet    = errors.New("program not set")
+)
+
 type KafkaWriter interface {
 	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
 }
@@ -28,20 +54,40 @@ type service struct {
 	store         store.Store
 	kafkaTopic    string
 	kafkaProducer KafkaWriter
+	blobs         blob.Store
 }
 
-func NewService(store store.Store, kafkaTopic string, kafkaProducer KafkaWriter) Service {
+func NewService(store store.Store, kafkaTopic string, kafkaProducer KafkaWriter, blobs blob.Store) Service {
 	return &service{
 		store:         store,
 		kafkaTopic:    kafkaTopic,
 		kafkaProducer: kafkaProducer,
+		blobs:         blobs,
 	}
 }
 
-func (s *service) CreateProblem(ctx context.Context, title, description string) (*types.Problem, error) {
-	problem := &types.Problem{
-		Title:       title,
-		Description: description,
+func (s *service) CreateProblem(ctx context.Context, problem *types.Problem) (*types.Problem, error) {
+	if problem.TimeLimitMs == 0 {
+		problem.TimeLimitMs = DefaultTimeLimitMs
+	}
+	if problem.MemoryLimitMB == 0 {
+		problem.MemoryLimitMB = DefaultMemoryLimitMB
+	}
+	if problem.OutputLimitKB == 0 {
+		problem.OutputLimitKB = DefaultOutputLimitKB
+	}
+	if problem.Type == "" {
+		problem.Type = types.ProblemTypeStandard
+	}
+	if problem.ScoringMode == "" {
+		problem.ScoringMode = types.ScoringModeICPC
+	}
+	if problem.Comparator == "" {
+		problem.Comparator = types.ComparatorTrim
+	}
+	if problem.Comparator == types.ComparatorFloat && problem.FloatAbsEps == 0 && problem.FloatRelEps == 0 {
+		problem.FloatAbsEps = DefaultFloatEps
+		problem.FloatRelEps = DefaultFloatEps
 	}
 
 	createdProblem, err := s.store.CreateProblem(problem)
@@ -49,13 +95,19 @@ func (s *service) CreateProblem(ctx context.Context, title, description string)
 		return nil, fmt.Errorf("failed to create problem: %w", err)
 	}
 
-	event := types.ProblemEvent{
-		EventType: "created",
+	s.publishEvent(ctx, types.ProblemEvent{
+		EventType: types.ProblemEventCreated,
 		Problem:   createdProblem,
-	}
+	})
+	return createdProblem, nil
+}
+
+// publishEvent is best effort: the change is already stored.
+func (s *service) publishEvent(ctx context.Context, event types.ProblemEvent) {
 	message, err := json.Marshal(event)
 	if err != nil {
 		log.Printf("Failed to marshal problem event: %v", err)
+		return
 	}
 
 	err = s.kafkaProducer.WriteMessages(ctx, kafka.Message{
@@ -66,7 +118,6 @@ func (s *service) CreateProblem(ctx context.Context, title, description string)
 	if err != nil {
 		log.Printf("Failed to produce problem event: %v", err)
 	}
-	return createdProblem, nil
 }
 
 func (s *service) GetProblem(ctx context.Context, id string) (*types.Problem, error) {
@@ -77,15 +128,160 @@ func (s *service) ListProblems(ctx context.Context) ([]*types.Problem, error) {
 	return s.store.ListProblems()
 }
 
-func (s *service) CreateTestCase(ctx context.Context, problemID, input, output string) (*types.TestCase, error) {
-	testCase := &types.TestCase{
-		ProblemID: problemID,
-		Input:     input,
-		Output:    output,
+func (s *service) CreateTestCase(ctx context.Context, testCase *types.TestCase) (*types.TestCase, error) {
+	if testCase.Points == 0 {
+		testCase.Points = DefaultTestPoints
 	}
-	return s.store.CreateTestCase(testCase)
+	if testCase.GroupID != "" {
+		if err := s.checkGroupsExist(testCase.ProblemID, []string{testCase.GroupID}); err != nil {
+			return nil, err
+		}
+	}
+	input, err := s.storeTestFile(ctx, testCase.Input, testCase.InputSHA256)
+	if err != nil {
+		return nil, err
+	}
+	output, err := s.storeTestFile(ctx, testCase.Output, testCase.OutputSHA256)
+	if err != nil {
+		return nil, err
+	}
+	testCase.Input, testCase.InputSHA256, testCase.InputSize = "", input.SHA256, input.Size
+	testCase.Output, testCase.OutputSHA256, testCase.OutputSize = "", output.SHA256, output.Size
+
+	created, err := s.store.CreateTestCase(testCase)
+	if err != nil {
+		return nil, err
+	}
+	s.publishEvent(ctx, types.ProblemEvent{EventType: types.ProblemEventTestsUpdated, ProblemID: created.ProblemID})
+	return created, nil
 }
 
 func (s *service) GetTestCases(ctx context.Context, problemID string) ([]*types.TestCase, error) {
 	return s.store.GetTestCasesByProblemID(problemID)
 }
+
+// storeTestFile puts inline test data into blob storage, or checks that a
+// file referenced by checksum has been uploaded.
+func (s *service) storeTestFile(ctx context.Context, data, sha256 string) (*blob.Object, error) {
+	if sha256 == "" {
+		obj, err := s.blobs.Put(ctx, strings.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to store test file: %w", err)
+		}
+		return obj, nil
+	}
+
+	obj, err := s.blobs.Stat(ctx, sha256)
+	if errors.Is(err, blob.ErrNotFound) {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownTestFile, sha256)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find test file: %w", err)
+	}
+	return obj, nil
+}
+
+func (s *service) UploadTestFile(ctx context.Context, r io.Reader) (*blob.Object, error) {
+	obj, err := s.blobs.Put(ctx, r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to store test file: %w", err)
+	}
+	return obj, nil
+}
+
+func (s *service) OpenTestFile(ctx context.Context, sha256 string) (io.ReadCloser, error) {
+	r, err := s.blobs.Open(ctx, sha256)
+	if errors.Is(err, blob.ErrNotFound) {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownTestFile, sha256)
+	}
+	return r, err
+}
+
+// CreateTestGroup only accepts dependencies on existing groups of the same
+// problem, so the dependency graph stays acyclic and follows creation order.
+func (s *service) CreateTestGroup(ctx context.Context, group *types.TestGroup) (*types.TestGroup, error) {
+	if group.Aggregation == "" {
+		group.Aggregation = types.GroupAggregationAllOrNothing
+	}
+
+	seen := make(map[string]bool, len(group.DependsOn))
+	dependsOn := make([]string, 0, len(group.DependsOn))
+	for _, id := range group.DependsOn {
+		if !seen[id] {
+			seen[id] = true
+			dependsOn = append(dependsOn, id)
+		}
+	}
+	group.DependsOn = dependsOn
+
+	if err := s.checkGroupsExist(group.ProblemID, group.DependsOn); err != nil {
+		return nil, err
+	}
+	created, err := s.store.CreateTestGroup(group)
+	if err != nil {
+		return nil, err
+	}
+	s.publishEvent(ctx, types.ProblemEvent{EventType: types.ProblemEventTestsUpdated, ProblemID: created.ProblemID})
+	return created, nil
+}
+
+func (s *service) GetTestGroups(ctx context.Context, problemID string) ([]*types.TestGroup, error) {
+	return s.store.GetTestGroupsByProblemID(problemID)
+}
+
+func (s *service) checkGroupsExist(problemID string, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	groups, err := s.store.GetTestGroupsByProblemID(problemID)
+	if err != nil {
+		return fmt.Errorf("failed to get test groups: %w", err)
+	}
+	known := make(map[string]bool, len(groups))
+	for _, group := range groups {
+		known[group.ID] = true
+	}
+	for _, id := range ids {
+		if !known[id] {
+			return fmt.Errorf("%w: %s", ErrUnknownTestGroup, id)
+		}
+	}
+	return nil
+}
+
+func (s *service) SetChecker(ctx context.Context, problemID, language, sourceCode string) (*types.Program, error) {
+	checker := &types.Program{
+		ProblemID:  problemID,
+		Kind:       types.ProgramKindChecker,
+		Language:   language,
+		SourceCode: sourceCode,
+	}
+	return s.store.SaveProgram(checker)
+}
+
+func (s *service) GetChecker(ctx context.Context, problemID string) (*types.Program, error) {
+	return s.getProgram(problemID, types.ProgramKindChecker)
+}
+
+func (s *service) SetInteractor(ctx context.Context, problemID, language, sourceCode string) (*types.Program, error) {
+	interactor := &types.Program{
+		ProblemID:  problemID,
+		Kind:       types.ProgramKindInteractor,
+		Language:   language,
+		SourceCode: sourceCode,
+	}
+	return s.store.SaveProgram(interactor)
+}
+
+func (s *service) getProgram(problemID, kind string) (*types.Program, error) {
+	program, err := s.store.GetProgram(problemID, kind)
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, fmt.Errorf("%w: %s", ErrProgramNotSet, kind)
+	}
+	return program, err
+}
+
+func (s *service) GetInteractor(ctx context.Context, problemID string) (*types.Program, error) {
+	return s.store.GetProgram(problemID, types.ProgramKindInteractor)
+}
@@ -4,9 +4,14 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/DeadlyParkour777/code-checker/services/problem_service/internal/blob"
+	"github.com/DeadlyParkour777/code-checker/services/problem_service/internal/store"
 	"github.com/DeadlyParkour777/code-checker/services/problem_service/internal/types"
 	"github.com/segmentio/kafka-go"
 )
@@ -17,6 +22,10 @@ type fakeStore struct {
 	listProblemsFn          func() ([]*types.Problem, error)
 	createTestCaseFn        func(testCase *types.TestCase) (*types.TestCase, error)
 	getTestCasesByProblemFn func(problemID string) ([]*types.TestCase, error)
+	createTestGroupFn       func(group *types.TestGroup) (*types.TestGroup, error)
+	getTestGroupsFn         func(problemID string) ([]*types.TestGroup, error)
+	saveProgramFn           func(program *types.Program) (*types.Program, error)
+	getProgramFn            func(problemID, kind string) (*types.Program, error)
 }
 
 func (f *fakeStore) CreateProblem(problem *types.Problem) (*types.Problem, error) {
@@ -54,6 +63,43 @@ func (f *fakeStore) GetTestCasesByProblemID(problemID string) ([]*types.TestCase
 	return f.getTestCasesByProblemFn(problemID)
 }
 
+func (f *fakeStore) CreateTestGroup(group *types.TestGroup) (*types.TestGroup, error) {
+	if f.createTestGroupFn == nil {
+		return nil, errors.New("CreateTestGroup not implemented")
+	}
+	return f.createTestGroupFn(group)
+}
+
+func (f *fakeStore) GetTestGroupsByProblemID(problemID string) ([]*types.TestGroup, error) {
+	if f.getTestGroupsFn == nil {
+		return nil, errors.New("GetTestGroupsByProblemID not implemented")
+	}
+	return f.getTestGroupsFn(problemID)
+}
+
+func (f *fakeStore) SaveProgram(program *types.Program) (*types.Program, error) {
+	if f.saveProgramFn == nil {
+		return nil, errors.New("SaveProgram not implemented")
+	}
+	return f.saveProgramFn(program)
+}
+
+func (f *fakeStore) GetProgram(problemID, kind string) (*types.Program, error) {
+	if f.getProgramFn == nil {
+		return nil, errors.New("GetProgram not implemented")
+	}
+	return f.getProgramFn(problemID, kind)
+}
+
+func newTestBlobs(t *testing.T) blob.Store {
+	t.Helper()
+	blobs, err := blob.NewLocal(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create blob store: %v", err)
+	}
+	return blobs
+}
+
 type fakeWriter struct {
 	messages []kafka.Message
 	err      error
@@ -80,9 +126,9 @@ func TestCreateProblem_SuccessEmitsEvent(t *testing.T) {
 		},
 	}
 	writer := &fakeWriter{}
-	service := NewService(store, "problem_events", writer)
+	service := NewService(store, "problem_events", writer, nil)
 
-	created, err := service.CreateProblem(context.Background(), "Two Sum", "Find indices")
+	created, err := service.CreateProblem(context.Background(), &types.Problem{Title: "Two Sum", Description: "Find indices"})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -108,6 +154,56 @@ func TestCreateProblem_SuccessEmitsEvent(t *testing.T) {
 	}
 }
 
+func TestCreateProblem_DefaultLimits(t *testing.T) {
+	store := &fakeStore{
+		createProblemFn: func(problem *types.Problem) (*types.Problem, error) {
+			if problem.TimeLimitMs != DefaultTimeLimitMs {
+				t.Fatalf("unexpected time limit: %d", problem.TimeLimitMs)
+			}
+			if problem.MemoryLimitMB != 512 {
+				t.Fatalf("unexpected memory limit: %d", problem.MemoryLimitMB)
+			}
+			if problem.OutputLimitKB != DefaultOutputLimitKB {
+				t.Fatalf("unexpected output limit: %d", problem.OutputLimitKB)
+			}
+			if problem.Comparator != types.ComparatorTrim {
+				t.Fatalf("unexpected comparator: %s", problem.Comparator)
+			}
+			if problem.Type != types.ProblemTypeStandard {
+				t.Fatalf("unexpected type: %s", problem.Type)
+			}
+			if problem.ScoringMode != types.ScoringModeICPC {
+				t.Fatalf("unexpected scoring mode: %s", problem.ScoringMode)
+			}
+			problem.ID = "problem-1"
+			return problem, nil
+		},
+	}
+	service := NewService(store, "problem_events", &fakeWriter{}, nil)
+
+	_, err := service.CreateProblem(context.Background(), &types.Problem{Title: "Title", MemoryLimitMB: 512})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestCreateProblem_DefaultFloatEps(t *testing.T) {
+	store := &fakeStore{
+		createProblemFn: func(problem *types.Problem) (*types.Problem, error) {
+			if problem.FloatAbsEps != DefaultFloatEps || problem.FloatRelEps != DefaultFloatEps {
+				t.Fatalf("unexpected tolerances: %g %g", problem.FloatAbsEps, problem.FloatRelEps)
+			}
+			return problem, nil
+		},
+	}
+	service := NewService(store, "problem_events", &fakeWriter{}, nil)
+
+	_, err := service.CreateProblem(context.Background(), &types.Problem{Title: "Title", Comparator: types.ComparatorFloat})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
 func TestCreateProblem_StoreError(t *testing.T) {
 	store := &fakeStore{
 		createProblemFn: func(problem *types.Problem) (*types.Problem, error) {
@@ -115,9 +211,9 @@ func TestCreateProblem_StoreError(t *testing.T) {
 		},
 	}
 	writer := &fakeWriter{}
-	service := NewService(store, "problem_events", writer)
+	service := NewService(store, "problem_events", writer, nil)
 
-	_, err := service.CreateProblem(context.Background(), "Title", "Desc")
+	_, err := service.CreateProblem(context.Background(), &types.Problem{Title: "Title", Description: "Desc"})
 	if err == nil {
 		t.Fatalf("expected error")
 	}
@@ -135,9 +231,9 @@ func TestCreateProblem_KafkaErrorIgnored(t *testing.T) {
 		},
 	}
 	writer := &fakeWriter{err: errors.New("kafka down")}
-	service := NewService(store, "problem_events", writer)
+	service := NewService(store, "problem_events", writer, nil)
 
-	created, err := service.CreateProblem(context.Background(), "Title", "Desc")
+	created, err := service.CreateProblem(context.Background(), &types.Problem{Title: "Title", Description: "Desc"})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -158,7 +254,7 @@ func TestGetProblem(t *testing.T) {
 			return &types.Problem{ID: id}, nil
 		},
 	}
-	service := NewService(store, "topic", &fakeWriter{})
+	service := NewService(store, "topic", &fakeWriter{}, nil)
 
 	problem, err := service.GetProblem(context.Background(), "problem-3")
 	if err != nil {
@@ -175,7 +271,7 @@ func TestListProblems(t *testing.T) {
 			return []*types.Problem{{ID: "p1"}, {ID: "p2"}}, nil
 		},
 	}
-	service := NewService(store, "topic", &fakeWriter{})
+	service := NewService(store, "topic", &fakeWriter{}, nil)
 
 	problems, err := service.ListProblems(context.Background())
 	if err != nil {
@@ -192,22 +288,134 @@ func TestCreateTestCase(t *testing.T) {
 			if testCase.ProblemID != "problem-4" {
 				t.Fatalf("unexpected problem id: %s", testCase.ProblemID)
 			}
-			if testCase.Input != "1 2" || testCase.Output != "3" {
-				t.Fatalf("unexpected test case data")
+			if testCase.Input != "" || testCase.InputSize != 3 || testCase.OutputSize != 1 {
+				t.Fatalf("expected test data to move to blob storage: %+v", testCase)
+			}
+			if testCase.Points != DefaultTestPoints {
+				t.Fatalf("unexpected points: %d", testCase.Points)
 			}
 			testCase.ID = "tc-1"
 			return testCase, nil
 		},
 	}
-	service := NewService(store, "topic", &fakeWriter{})
+	writer := &fakeWriter{}
+	blobs := newTestBlobs(t)
+	service := NewService(store, "topic", writer, blobs)
 
-	created, err := service.CreateTestCase(context.Background(), "problem-4", "1 2", "3")
+	created, err := service.CreateTestCase(context.Background(), &types.TestCase{ProblemID: "problem-4", Input: "1 2", Output: "3"})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 	if created.ID != "tc-1" {
 		t.Fatalf("unexpected test case id: %s", created.ID)
 	}
+	input, err := service.OpenTestFile(context.Background(), created.InputSHA256)
+	if err != nil {
+		t.Fatalf("failed to open input: %v", err)
+	}
+	defer input.Close()
+	if data, _ := io.ReadAll(input); string(data) != "1 2" {
+		t.Fatalf("unexpected input: %q", data)
+	}
+
+	if len(writer.messages) != 1 {
+		t.Fatalf("expected 1 kafka message, got %d", len(writer.messages))
+	}
+	var event types.ProblemEvent
+	if err := json.Unmarshal(writer.messages[0].Value, &event); err != nil {
+		t.Fatalf("failed to unmarshal event: %v", err)
+	}
+	if event.EventType != types.ProblemEventTestsUpdated || event.ProblemID != "problem-4" {
+		t.Fatalf("unexpected event: %+v", event)
+	}
+}
+
+func TestCreateTestCase_UploadedFiles(t *testing.T) {
+	store := &fakeStore{
+		createTestCaseFn: func(testCase *types.TestCase) (*types.TestCase, error) {
+			return testCase, nil
+		},
+	}
+	blobs := newTestBlobs(t)
+	service := NewService(store, "topic", &fakeWriter{}, blobs)
+
+	uploaded, err := service.UploadTestFile(context.Background(), strings.NewReader("1 2"))
+	if err != nil {
+		t.Fatalf("failed to upload: %v", err)
+	}
+
+	created, err := service.CreateTestCase(context.Background(), &types.TestCase{ProblemID: "problem-4", InputSHA256: uploaded.SHA256, Output: "3"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created.InputSHA256 != uploaded.SHA256 || created.InputSize != 3 {
+		t.Fatalf("unexpected input: %+v", created)
+	}
+
+	_, err = service.CreateTestCase(context.Background(), &types.TestCase{ProblemID: "problem-4", InputSHA256: strings.Repeat("a", 64)})
+	if !errors.Is(err, ErrUnknownTestFile) {
+		t.Fatalf("expected ErrUnknownTestFile, got %v", err)
+	}
+}
+
+func TestCreateTestCase_UnknownGroup(t *testing.T) {
+	store := &fakeStore{
+		getTestGroupsFn: func(problemID string) ([]*types.TestGroup, error) {
+			return []*types.TestGroup{{ID: "g1", ProblemID: problemID}}, nil
+		},
+	}
+	service := NewService(store, "topic", &fakeWriter{}, nil)
+
+	_, err := service.CreateTestCase(context.Background(), &types.TestCase{ProblemID: "problem-4", GroupID: "g2"})
+	if !errors.Is(err, ErrUnknownTestGroup) {
+		t.Fatalf("expected ErrUnknownTestGroup, got %v", err)
+	}
+}
+
+func TestCreateTestGroup(t *testing.T) {
+	store := &fakeStore{
+		getTestGroupsFn: func(problemID string) ([]*types.TestGroup, error) {
+			return []*types.TestGroup{{ID: "g1", ProblemID: problemID}}, nil
+		},
+		createTestGroupFn: func(group *types.TestGroup) (*types.TestGroup, error) {
+			if group.Aggregation != types.GroupAggregationAllOrNothing {
+				t.Fatalf("unexpected aggregation: %s", group.Aggregation)
+			}
+			if len(group.DependsOn) != 1 || group.DependsOn[0] != "g1" {
+				t.Fatalf("unexpected dependencies: %v", group.DependsOn)
+			}
+			group.ID = "g2"
+			return group, nil
+		},
+	}
+	service := NewService(store, "topic", &fakeWriter{}, nil)
+
+	created, err := service.CreateTestGroup(context.Background(), &types.TestGroup{
+		ProblemID: "problem-4",
+		Name:      "subtask 2",
+		Score:     40,
+		DependsOn: []string{"g1", "g1"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created.ID != "g2" {
+		t.Fatalf("unexpected group id: %s", created.ID)
+	}
+}
+
+func TestCreateTestGroup_UnknownDependency(t *testing.T) {
+	store := &fakeStore{
+		getTestGroupsFn: func(_ string) ([]*types.TestGroup, error) {
+			return nil, nil
+		},
+	}
+	service := NewService(store, "topic", &fakeWriter{}, nil)
+
+	_, err := service.CreateTestGroup(context.Background(), &types.TestGroup{ProblemID: "problem-4", Name: "g", DependsOn: []string{"g1"}})
+	if !errors.Is(err, ErrUnknownTestGroup) {
+		t.Fatalf("expected ErrUnknownTestGroup, got %v", err)
+	}
 }
 
 func TestGetTestCases(t *testing.T) {
@@ -219,7 +427,7 @@ func TestGetTestCases(t *testing.T) {
 			return []*types.TestCase{{ID: "tc-1"}}, nil
 		},
 	}
-	service := NewService(store, "topic", &fakeWriter{})
+	service := NewService(store, "topic", &fakeWriter{}, nil)
 
 	cases, err := service.GetTestCases(context.Background(), "problem-5")
 	if err != nil {
@@ -229,3 +437,89 @@ func TestGetTestCases(t *testing.T) {
 		t.Fatalf("unexpected test cases")
 	}
 }
+
+func TestSetChecker(t *testing.T) {
+	store := &fakeStore{
+		saveProgramFn: func(program *types.Program) (*types.Program, error) {
+			if program.ProblemID != "problem-6" || program.Kind != types.ProgramKindChecker {
+				t.Fatalf("unexpected program: %+v", program)
+			}
+			if program.Language != "go" || program.SourceCode != "package main" {
+				t.Fatalf("unexpected checker source")
+			}
+			return program, nil
+		},
+	}
+	service := NewService(store, "topic", &fakeWriter{}, nil)
+
+	checker, err := service.SetChecker(context.Background(), "problem-6", "go", "package main")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if checker.Kind != types.ProgramKindChecker {
+		t.Fatalf("unexpected kind: %s", checker.Kind)
+	}
+}
+
+func TestGetChecker(t *testing.T) {
+	store := &fakeStore{
+		getProgramFn: func(problemID, kind string) (*types.Program, error) {
+			if problemID != "problem-7" || kind != types.ProgramKindChecker {
+				t.Fatalf("unexpected lookup: %s %s", problemID, kind)
+			}
+			return &types.Program{ProblemID: problemID, Kind: kind, Language: "python"}, nil
+		},
+	}
+	service := NewService(store, "topic", &fakeWriter{}, nil)
+
+	checker, err := service.GetChecker(context.Background(), "problem-7")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if checker.Language != "python" {
+		t.Fatalf("unexpected language: %s", checker.Language)
+	}
+}
+
+func TestGetChecker_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantNotSet bool
+	}{
+		{name: "not set", err: fmt.Errorf("checker %w", store.ErrNotFound), wantNotSet: true},
+		{name: "database", err: errors.New("connection refused")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &fakeStore{
+				getProgramFn: func(problemID, kind string) (*types.Program, error) {
+					return nil, tt.err
+				},
+			}
+			service := NewService(store, "topic", &fakeWriter{}, nil)
+
+			_, err := service.GetChecker(context.Background(), "problem-7")
+			if err == nil || errors.Is(err, ErrProgramNotSet) != tt.wantNotSet {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestSetInteractor(t *testing.T) {
+	store := &fakeStore{
+		saveProgramFn: func(program *types.Program) (*types.Program, error) {
+			if program.Kind != types.ProgramKindInteractor {
+				t.Fatalf("unexpected kind: %s", program.Kind)
+			}
+			return program, nil
+		},
+	}
+	service := NewService(store, "topic", &fakeWriter{}, nil)
+
+	if _, err := service.SetInteractor(context.Background(), "problem-8", "python", "print()"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
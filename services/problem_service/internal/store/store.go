@@ -2,19 +2,27 @@ package store
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 
 	"github.com/DeadlyParkour777/code-checker/services/problem_service/internal/types"
 	"github.com/google/uuid"
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 )
 
+// ErrNotFound is returned when the requested row does not exist.
+var ErrNotFound = errors.New("not found")
+
 type Store interface {
 	CreateProblem(problem *types.Problem) (*types.Problem, error)
 	GetProblem(id string) (*types.Problem, error)
 	ListProblems() ([]*types.Problem, error)
 	CreateTestCase(testCase *types.TestCase) (*types.TestCase, error)
 	GetTestCasesByProblemID(problemID string) ([]*types.TestCase, error)
+	CreateTestGroup(group *types.TestGroup) (*types.TestGroup, error)
+	GetTestGroupsByProblemID(problemID string) ([]*types.TestGroup, error)
+	SaveProgram(program *types.Program) (*types.Program, error)
+	GetProgram(problemID, kind string) (*types.Program, error)
 }
 
 type store struct {
@@ -27,10 +35,28 @@ func NewStore(db *sql.DB) Store {
 
 func (s *store) CreateProblem(problem *types.Problem) (*types.Problem, error) {
 	problem.ID = uuid.New().String()
+	if problem.GoModules == nil {
+		problem.GoModules = []string{}
+	}
 
-	query := `INSERT INTO problems (id, title, description) VALUES ($1, $2, $3) RETURNING created_at`
+	query := `INSERT INTO problems (id, title, description, time_limit_ms, memory_limit_mb, output_limit_kb,
+	                              comparator, float_abs_eps, float_rel_eps, type, scoring_mode, go_modules)
+	          VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING created_at`
 
-	err := s.db.QueryRow(query, problem.ID, problem.Title, problem.Description).Scan(&problem.CreatedAt)
+	err := s.db.QueryRow(query,
+		problem.ID,
+		problem.Title,
+		problem.Description,
+		problem.TimeLimitMs,
+		problem.MemoryLimitMB,
+		problem.OutputLimitKB,
+		problem.Comparator,
+		problem.FloatAbsEps,
+		problem.FloatRelEps,
+		problem.Type,
+		problem.ScoringMode,
+		pq.Array(problem.GoModules),
+	).Scan(&problem.CreatedAt)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create problem: %w", err)
 	}
@@ -40,9 +66,26 @@ func (s *store) CreateProblem(problem *types.Problem) (*types.Problem, error) {
 
 func (s *store) GetProblem(id string) (*types.Problem, error) {
 	problem := &types.Problem{}
-	query := `SELECT id, title, description, created_at FROM problems WHERE id = $1`
+	query := `SELECT id, title, description, time_limit_ms, memory_limit_mb, output_limit_kb,
+	                 comparator, float_abs_eps, float_rel_eps, type, scoring_mode, tests_version, go_modules, created_at
+	          FROM problems WHERE id = $1`
 
-	err := s.db.QueryRow(query, id).Scan(&problem.ID, &problem.Title, &problem.Description, &problem.CreatedAt)
+	err := s.db.QueryRow(query, id).Scan(
+		&problem.ID,
+		&problem.Title,
+		&problem.Description,
+		&problem.TimeLimitMs,
+		&problem.MemoryLimitMB,
+		&problem.OutputLimitKB,
+		&problem.Comparator,
+		&problem.FloatAbsEps,
+		&problem.FloatRelEps,
+		&problem.Type,
+		&problem.ScoringMode,
+		&problem.TestsVersion,
+		pq.Array(&problem.GoModules),
+		&problem.CreatedAt,
+	)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, fmt.Errorf("problem not found")
@@ -54,7 +97,8 @@ func (s *store) GetProblem(id string) (*types.Problem, error) {
 
 func (s *store) ListProblems() ([]*types.Problem, error) {
 	var problems []*types.Problem
-	query := `SELECT id, title, description, created_at FROM problems`
+	query := `SELECT id, title, description, time_limit_ms, memory_limit_mb, output_limit_kb,
+	                 comparator, float_abs_eps, float_rel_eps, type, scoring_mode, tests_version, go_modules, created_at FROM problems`
 	rows, err := s.db.Query(query)
 
 	if err != nil {
@@ -64,7 +108,22 @@ func (s *store) ListProblems() ([]*types.Problem, error) {
 
 	for rows.Next() {
 		problem := &types.Problem{}
-		if err := rows.Scan(&problem.ID, &problem.Title, &problem.Description, &problem.CreatedAt); err != nil {
+		if err := rows.Scan(
+			&problem.ID,
+			&problem.Title,
+			&problem.Description,
+			&problem.TimeLimitMs,
+			&problem.MemoryLimitMB,
+			&problem.OutputLimitKB,
+			&problem.Comparator,
+			&problem.FloatAbsEps,
+			&problem.FloatRelEps,
+			&problem.Type,
+			&problem.ScoringMode,
+			&problem.TestsVersion,
+			pq.Array(&problem.GoModules),
+			&problem.CreatedAt,
+		); err != nil {
 			return nil, fmt.Errorf("failed to scan problem: %w", err)
 		}
 		problems = append(problems, problem)
@@ -79,12 +138,29 @@ func (s *store) ListProblems() ([]*types.Problem, error) {
 
 func (s *store) CreateTestCase(testCase *types.TestCase) (*types.TestCase, error) {
 	testCase.ID = uuid.New().String()
-	query := `INSERT INTO test_cases (id, problem_id, input_data, output_data) VALUES ($1, $2, $3, $4)`
+	query := `INSERT INTO test_cases (id, problem_id, input_data, output_data, points, group_id,
+	                                input_sha256, input_size, output_sha256, output_size)
+	          VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
 
-	_, err := s.db.Exec(query, testCase.ID, testCase.ProblemID, testCase.Input, testCase.Output)
+	groupID := sql.NullString{String: testCase.GroupID, Valid: testCase.GroupID != ""}
+	_, err = tx.Exec(query, testCase.ID, testCase.ProblemID, testCase.Input, testCase.Output, testCase.Points, groupID,
+		testCase.InputSHA256, testCase.InputSize, testCase.OutputSHA256, testCase.OutputSize)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create test case: %w", err)
 	}
+	if err := bumpTestsVersion(tx, testCase.ProblemID); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit test case: %w", err)
+	}
 
 	return testCase, nil
 }
@@ -92,7 +168,9 @@ func (s *store) CreateTestCase(testCase *types.TestCase) (*types.TestCase, error
 func (s *store) GetTestCasesByProblemID(problemID string) ([]*types.TestCase, error) {
 	var testCases []*types.TestCase
 
-	query := `SELECT id, problem_id, input_data, output_data FROM test_cases WHERE problem_id = $1`
+	query := `SELECT id, problem_id, input_data, output_data, points, group_id,
+	                 input_sha256, input_size, output_sha256, output_size
+	          FROM test_cases WHERE problem_id = $1 ORDER BY created_at, id`
 	rows, err := s.db.Query(query, problemID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get test cases: %w", err)
@@ -101,9 +179,12 @@ func (s *store) GetTestCasesByProblemID(problemID string) ([]*types.TestCase, er
 
 	for rows.Next() {
 		tc := &types.TestCase{}
-		if err := rows.Scan(&tc.ID, &tc.ProblemID, &tc.Input, &tc.Output); err != nil {
+		var groupID sql.NullString
+		if err := rows.Scan(&tc.ID, &tc.ProblemID, &tc.Input, &tc.Output, &tc.Points, &groupID,
+			&tc.InputSHA256, &tc.InputSize, &tc.OutputSHA256, &tc.OutputSize); err != nil {
 			return nil, fmt.Errorf("failed to scan test case: %w", err)
 		}
+		tc.GroupID = groupID.String
 		testCases = append(testCases, tc)
 	}
 
@@ -113,3 +194,133 @@ func (s *store) GetTestCasesByProblemID(problemID string) ([]*types.TestCase, er
 
 	return testCases, nil
 }
+
+func (s *store) CreateTestGroup(group *types.TestGroup) (*types.TestGroup, error) {
+	group.ID = uuid.New().String()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO test_groups (id, problem_id, name, score, aggregation)
+	          VALUES ($1, $2, $3, $4, $5) RETURNING created_at`
+	err = tx.QueryRow(query, group.ID, group.ProblemID, group.Name, group.Score, group.Aggregation).Scan(&group.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create test group: %w", err)
+	}
+
+	for _, dependsOn := range group.DependsOn {
+		_, err := tx.Exec(`INSERT INTO test_group_dependencies (group_id, depends_on_id) VALUES ($1, $2)`, group.ID, dependsOn)
+		if err != nil {
+			return nil, fmt.Errorf("failed to add test group dependency: %w", err)
+		}
+	}
+
+	if err := bumpTestsVersion(tx, group.ProblemID); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit test group: %w", err)
+	}
+
+	return group, nil
+}
+
+// bumpTestsVersion marks the test set of a problem as changed, so judges
+// holding a cached copy of an older version fetch it again.
+func bumpTestsVersion(tx *sql.Tx, problemID string) error {
+	if _, err := tx.Exec(`UPDATE problems SET tests_version = tests_version + 1 WHERE id = $1`, problemID); err != nil {
+		return fmt.Errorf("failed to bump tests version: %w", err)
+	}
+	return nil
+}
+
+func (s *store) GetTestGroupsByProblemID(problemID string) ([]*types.TestGroup, error) {
+	var groups []*types.TestGroup
+
+	query := `SELECT id, problem_id, name, score, aggregation, created_at
+	          FROM test_groups WHERE problem_id = $1 ORDER BY created_at, id`
+	rows, err := s.db.Query(query, problemID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get test groups: %w", err)
+	}
+	defer rows.Close()
+
+	byID := make(map[string]*types.TestGroup)
+	for rows.Next() {
+		group := &types.TestGroup{}
+		if err := rows.Scan(&group.ID, &group.ProblemID, &group.Name, &group.Score, &group.Aggregation, &group.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan test group: %w", err)
+		}
+		groups = append(groups, group)
+		byID[group.ID] = group
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over test group rows: %w", err)
+	}
+
+	if len(groups) == 0 {
+		return groups, nil
+	}
+
+	depQuery := `SELECT d.group_id, d.depends_on_id
+	             FROM test_group_dependencies d
+	             JOIN test_groups g ON g.id = d.group_id
+	             WHERE g.problem_id = $1 ORDER BY d.group_id, d.depends_on_id`
+	depRows, err := s.db.Query(depQuery, problemID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get test group dependencies: %w", err)
+	}
+	defer depRows.Close()
+
+	for depRows.Next() {
+		var groupID, dependsOn string
+		if err := depRows.Scan(&groupID, &dependsOn); err != nil {
+			return nil, fmt.Errorf("failed to scan test group dependency: %w", err)
+		}
+		if group, ok := byID[groupID]; ok {
+			group.DependsOn = append(group.DependsOn, dependsOn)
+		}
+	}
+
+	return groups, depRows.Err()
+}
+
+func (s *store) SaveProgram(program *types.Program) (*types.Program, error) {
+	query := `INSERT INTO problem_programs (problem_id, kind, language, source_code)
+	          VALUES ($1, $2, $3, $4)
+	          ON CONFLICT (problem_id, kind)
+	          DO UPDATE SET language = EXCLUDED.language, source_code = EXCLUDED.source_code, updated_at = CURRENT_TIMESTAMP
+	          RETURNING updated_at`
+
+	err := s.db.QueryRow(query, program.ProblemID, program.Kind, program.Language, program.SourceCode).Scan(&program.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save %s: %w", program.Kind, err)
+	}
+
+	return program, nil
+}
+
+func (s *store) GetProgram(problemID, kind string) (*types.Program, error) {
+	program := &types.Program{}
+	query := `SELECT problem_id, kind, language, source_code, updated_at
+	          FROM problem_programs WHERE problem_id = $1 AND kind = $2`
+
+	err := s.db.QueryRow(query, problemID, kind).Scan(
+		&program.ProblemID,
+		&program.Kind,
+		&program.Language,
+		&program.SourceCode,
+		&program.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%s %w", kind, ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to get %s: %w", kind, err)
+	}
+	return program, nil
+}
@@ -3,6 +3,7 @@ package store
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"io"
 	"net"
@@ -131,6 +132,24 @@ func createSchema(db *sql.DB) error {
 			id UUID PRIMARY KEY,
 			title VARCHAR(255) NOT NULL,
 			description TEXT,
+			time_limit_ms INTEGER NOT NULL DEFAULT 2000,
+			memory_limit_mb INTEGER NOT NULL DEFAULT 128,
+			output_limit_kb INTEGER NOT NULL DEFAULT 16384,
+			comparator VARCHAR(32) NOT NULL DEFAULT 'trim',
+			float_abs_eps DOUBLE PRECISION NOT NULL DEFAULT 0,
+			float_rel_eps DOUBLE PRECISION NOT NULL DEFAULT 0,
+			type VARCHAR(20) NOT NULL DEFAULT 'standard',
+			scoring_mode VARCHAR(20) NOT NULL DEFAULT 'icpc',
+			tests_version BIGINT NOT NULL DEFAULT 0,
+			go_modules TEXT[] NOT NULL DEFAULT '{}',
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+		);`,
+		`CREATE TABLE IF NOT EXISTS test_groups (
+			id UUID PRIMARY KEY,
+			problem_id UUID NOT NULL REFERENCES problems(id) ON DELETE CASCADE,
+			name VARCHAR(255) NOT NULL,
+			score INTEGER NOT NULL DEFAULT 0,
+			aggregation VARCHAR(20) NOT NULL DEFAULT 'all_or_nothing',
 			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
 		);`,
 		`CREATE TABLE IF NOT EXISTS test_cases (
@@ -138,8 +157,27 @@ func createSchema(db *sql.DB) error {
 			problem_id UUID NOT NULL REFERENCES problems(id) ON DELETE CASCADE,
 			input_data TEXT NOT NULL,
 			output_data TEXT NOT NULL,
+			points INTEGER NOT NULL DEFAULT 1,
+			group_id UUID REFERENCES test_groups(id) ON DELETE SET NULL,
+			input_sha256 VARCHAR(64) NOT NULL DEFAULT '',
+			input_size BIGINT NOT NULL DEFAULT 0,
+			output_sha256 VARCHAR(64) NOT NULL DEFAULT '',
+			output_size BIGINT NOT NULL DEFAULT 0,
 			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
 		);`,
+		`CREATE TABLE IF NOT EXISTS test_group_dependencies (
+			group_id UUID NOT NULL REFERENCES test_groups(id) ON DELETE CASCADE,
+			depends_on_id UUID NOT NULL REFERENCES test_groups(id) ON DELETE CASCADE,
+			PRIMARY KEY (group_id, depends_on_id)
+		);`,
+		`CREATE TABLE IF NOT EXISTS problem_programs (
+			problem_id UUID NOT NULL REFERENCES problems(id) ON DELETE CASCADE,
+			kind VARCHAR(20) NOT NULL,
+			language VARCHAR(50) NOT NULL,
+			source_code TEXT NOT NULL,
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (problem_id, kind)
+		);`,
 	}
 
 	for _, stmt := range statements {
@@ -155,7 +193,7 @@ func createSchema(db *sql.DB) error {
 
 func resetDB(t *testing.T) {
 	t.Helper()
-	if _, err := testDB.Exec(`TRUNCATE TABLE test_cases, problems RESTART IDENTITY CASCADE`); err != nil {
+	if _, err := testDB.Exec(`TRUNCATE TABLE problem_programs, test_group_dependencies, test_cases, test_groups, problems RESTART IDENTITY CASCADE`); err != nil {
 		t.Fatalf("failed to reset db: %v", err)
 	}
 }
@@ -165,7 +203,7 @@ func TestStore_CreateAndGetProblem(t *testing.T) {
 
 	s := NewStore(testDB)
 
-	created, err := s.CreateProblem(&types.Problem{Title: "Two Sum", Description: "Find indices"})
+	created, err := s.CreateProblem(&types.Problem{Title: "Two Sum", Description: "Find indices", TimeLimitMs: 1500, MemoryLimitMB: 256, Comparator: "float", FloatAbsEps: 1e-9, ScoringMode: "points", GoModules: []string{"gonum.org/v1/gonum"}})
 	if err != nil {
 		t.Fatalf("create problem: %v", err)
 	}
@@ -183,6 +221,18 @@ func TestStore_CreateAndGetProblem(t *testing.T) {
 	if fetched.Title != "Two Sum" {
 		t.Fatalf("unexpected title: %s", fetched.Title)
 	}
+	if fetched.TimeLimitMs != 1500 || fetched.MemoryLimitMB != 256 {
+		t.Fatalf("unexpected limits: %d ms, %d MB", fetched.TimeLimitMs, fetched.MemoryLimitMB)
+	}
+	if fetched.Comparator != "float" || fetched.FloatAbsEps != 1e-9 {
+		t.Fatalf("unexpected comparator: %s %g", fetched.Comparator, fetched.FloatAbsEps)
+	}
+	if fetched.ScoringMode != "points" {
+		t.Fatalf("unexpected scoring mode: %s", fetched.ScoringMode)
+	}
+	if len(fetched.GoModules) != 1 || fetched.GoModules[0] != "gonum.org/v1/gonum" {
+		t.Fatalf("unexpected go modules: %v", fetched.GoModules)
+	}
 }
 
 func TestStore_GetProblem_NotFound(t *testing.T) {
@@ -229,7 +279,7 @@ func TestStore_CreateAndGetTestCases(t *testing.T) {
 		t.Fatalf("create problem: %v", err)
 	}
 
-	case1, err := s.CreateTestCase(&types.TestCase{ProblemID: problem.ID, Input: "1 2", Output: "3"})
+	case1, err := s.CreateTestCase(&types.TestCase{ProblemID: problem.ID, Input: "1 2", Output: "3", Points: 7})
 	if err != nil {
 		t.Fatalf("create test case: %v", err)
 	}
@@ -237,7 +287,7 @@ func TestStore_CreateAndGetTestCases(t *testing.T) {
 		t.Fatalf("expected test case id to be set")
 	}
 
-	_, err = s.CreateTestCase(&types.TestCase{ProblemID: problem.ID, Input: "2 2", Output: "4"})
+	case2, err := s.CreateTestCase(&types.TestCase{ProblemID: problem.ID, InputSHA256: "in", InputSize: 4, OutputSHA256: "out", OutputSize: 2})
 	if err != nil {
 		t.Fatalf("create test case: %v", err)
 	}
@@ -249,6 +299,65 @@ func TestStore_CreateAndGetTestCases(t *testing.T) {
 	if len(cases) != 2 {
 		t.Fatalf("expected 2 test cases, got %d", len(cases))
 	}
+	for _, tc := range cases {
+		if tc.ID == case1.ID && tc.Points != 7 {
+			t.Fatalf("unexpected points: %d", tc.Points)
+		}
+		if tc.ID == case2.ID && (tc.InputSHA256 != "in" || tc.InputSize != 4 || tc.OutputSHA256 != "out" || tc.OutputSize != 2) {
+			t.Fatalf("unexpected test files: %+v", tc)
+		}
+	}
+
+	got, err := s.GetProblem(problem.ID)
+	if err != nil {
+		t.Fatalf("get problem: %v", err)
+	}
+	if got.TestsVersion != 2 {
+		t.Fatalf("expected tests version 2, got %d", got.TestsVersion)
+	}
+}
+
+func TestStore_TestGroups(t *testing.T) {
+	resetDB(t)
+
+	s := NewStore(testDB)
+
+	problem, err := s.CreateProblem(&types.Problem{Title: "A", Description: "B"})
+	if err != nil {
+		t.Fatalf("create problem: %v", err)
+	}
+
+	first, err := s.CreateTestGroup(&types.TestGroup{ProblemID: problem.ID, Name: "samples", Aggregation: "all_or_nothing"})
+	if err != nil {
+		t.Fatalf("create test group: %v", err)
+	}
+	second, err := s.CreateTestGroup(&types.TestGroup{ProblemID: problem.ID, Name: "full", Score: 100, Aggregation: "min", DependsOn: []string{first.ID}})
+	if err != nil {
+		t.Fatalf("create test group: %v", err)
+	}
+
+	if _, err := s.CreateTestCase(&types.TestCase{ProblemID: problem.ID, Input: "1", Output: "1", Points: 1, GroupID: second.ID}); err != nil {
+		t.Fatalf("create test case: %v", err)
+	}
+
+	groups, err := s.GetTestGroupsByProblemID(problem.ID)
+	if err != nil {
+		t.Fatalf("get test groups: %v", err)
+	}
+	if len(groups) != 2 || groups[0].ID != first.ID || groups[1].ID != second.ID {
+		t.Fatalf("unexpected groups: %+v", groups)
+	}
+	if len(groups[1].DependsOn) != 1 || groups[1].DependsOn[0] != first.ID || groups[1].Score != 100 {
+		t.Fatalf("unexpected group: %+v", groups[1])
+	}
+
+	cases, err := s.GetTestCasesByProblemID(problem.ID)
+	if err != nil {
+		t.Fatalf("get test cases: %v", err)
+	}
+	if len(cases) != 1 || cases[0].GroupID != second.ID {
+		t.Fatalf("unexpected test cases: %+v", cases)
+	}
 }
 
 func TestStore_CreateTestCase_InvalidProblem(t *testing.T) {
@@ -284,3 +393,46 @@ func TestStore_GetTestCases_Empty(t *testing.T) {
 		t.Fatalf("expected 0 test cases, got %d", len(cases))
 	}
 }
+
+func TestStore_SaveAndGetProgram(t *testing.T) {
+	resetDB(t)
+
+	s := NewStore(testDB)
+
+	problem, err := s.CreateProblem(&types.Problem{Title: "A", Description: "B"})
+	if err != nil {
+		t.Fatalf("create problem: %v", err)
+	}
+
+	_, err = s.SaveProgram(&types.Program{ProblemID: problem.ID, Kind: types.ProgramKindChecker, Language: "go", SourceCode: "v1"})
+	if err != nil {
+		t.Fatalf("save program: %v", err)
+	}
+	_, err = s.SaveProgram(&types.Program{ProblemID: problem.ID, Kind: types.ProgramKindChecker, Language: "python", SourceCode: "v2"})
+	if err != nil {
+		t.Fatalf("update program: %v", err)
+	}
+
+	program, err := s.GetProgram(problem.ID, types.ProgramKindChecker)
+	if err != nil {
+		t.Fatalf("get program: %v", err)
+	}
+	if program.Language != "python" || program.SourceCode != "v2" {
+		t.Fatalf("expected updated program, got %s %q", program.Language, program.SourceCode)
+	}
+}
+
+func TestStore_GetProgram_NotFound(t *testing.T) {
+	resetDB(t)
+
+	s := NewStore(testDB)
+
+	problem, err := s.CreateProblem(&types.Problem{Title: "A", Description: "B"})
+	if err != nil {
+		t.Fatalf("create problem: %v", err)
+	}
+
+	if _, err := s.GetProgram(problem.ID, types.ProgramKindChecker); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+}
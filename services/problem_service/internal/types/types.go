@@ -1,21 +1,116 @@
 package types
 
-import "time"
+import (
+	"regexp"
+	"time"
+)
 
 type Problem struct {
+	ID            string    `json:"id"`
+	Title         string    `json:"title"`
+	Description   string    `json:"description"`
+	TimeLimitMs   int       `json:"time_limit_ms"`
+	MemoryLimitMB int       `json:"memory_limit_mb"`
+	OutputLimitKB int       `json:"output_limit_kb"`
+	Comparator    string    `json:"comparator"`
+	FloatAbsEps   float64   `json:"float_abs_eps"`
+	FloatRelEps   float64   `json:"float_rel_eps"`
+	Type          string    `json:"type"`
+	ScoringMode   string    `json:"scoring_mode"`
+	TestsVersion  int64     `json:"tests_version"`
+	GoModules     []string  `json:"go_modules"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+const (
+	ComparatorTrim            = "trim"
+	ComparatorExact           = "exact"
+	ComparatorTokens          = "tokens"
+	ComparatorFloat           = "float"
+	ComparatorCaseInsensitive = "case_insensitive"
+	ComparatorUnorderedLines  = "unordered_lines"
+)
+
+func IsValidComparator(name string) bool {
+	switch name {
+	case ComparatorTrim, ComparatorExact, ComparatorTokens, ComparatorFloat, ComparatorCaseInsensitive, ComparatorUnorderedLines:
+		return true
+	}
+	return false
+}
+
+// goModulePattern matches module paths without a version, e.g.
+// gonum.org/v1/gonum; the first element must look like a domain.
+var goModulePattern = regexp.MustCompile(`^[a-z0-9-]+(\.[a-z0-9-]+)+(/[A-Za-z0-9._~+-]+)*$`)
+
+func IsValidGoModule(path string) bool {
+	return goModulePattern.MatchString(path)
+}
+
+// TestCase files are kept in blob storage under their checksums. Input and
+// Output are only set for test cases created before that.
+type TestCase struct {
+	ID           string `json:"id"`
+	ProblemID    string `json:"problem_id"`
+	Input        string `json:"input"`
+	Output       string `json:"output"`
+	Points       int    `json:"points"`
+	GroupID      string `json:"group_id,omitempty"`
+	InputSHA256  string `json:"input_sha256,omitempty"`
+	InputSize    int64  `json:"input_size"`
+	OutputSHA256 string `json:"output_sha256,omitempty"`
+	OutputSize   int64  `json:"output_size"`
+}
+
+// A TestGroup is an IOI-style subtask. It is only judged once every group it
+// depends on has been passed in full.
+type TestGroup struct {
 	ID          string    `json:"id"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
+	ProblemID   string    `json:"problem_id"`
+	Name        string    `json:"name"`
+	Score       int       `json:"score"`
+	Aggregation string    `json:"aggregation"`
+	DependsOn   []string  `json:"depends_on"`
 	CreatedAt   time.Time `json:"created_at"`
 }
 
-type TestCase struct {
-	ID        string `json:"id"`
-	ProblemID string `json:"problem_id"`
-	Input     string `json:"input"`
-	Output    string `json:"output"`
+const (
+	GroupAggregationAllOrNothing = "all_or_nothing"
+	GroupAggregationMin          = "min"
+)
+
+const (
+	ProblemTypeStandard    = "standard"
+	ProblemTypeInteractive = "interactive"
+)
+
+// ICPC scoring stops at the first failed test; points scoring runs every
+// test and sums the points of the passed ones.
+const (
+	ScoringModeICPC   = "icpc"
+	ScoringModePoints = "points"
+)
+
+const (
+	ProgramKindChecker    = "checker"
+	ProgramKindInteractor = "interactor"
+)
+
+type Program struct {
+	ProblemID  string    `json:"problem_id"`
+	Kind       string    `json:"kind"`
+	Language   string    `json:"language"`
+	SourceCode string    `json:"source_code"`
+	UpdatedAt  time.Time `json:"updated_at"`
 }
 
+const (
+	ProblemEventCreated      = "created"
+	ProblemEventTestsUpdated = "tests_updated"
+)
+
+// ProblemEvent is published to the problem events topic. tests_updated means
+// the test cases or groups of ProblemID changed and cached copies are stale.
 type ProblemEvent struct {
 	EventType string   `json:"event_type"`
 	Problem   *Problem `json:"problem,omitempty"`
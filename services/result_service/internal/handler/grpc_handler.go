@@ -2,10 +2,13 @@ package handler
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	resultpb "github.com/DeadlyParkour777/code-checker/pkg/result"
 	"github.com/DeadlyParkour777/code-checker/services/result_service/internal/service"
+	"github.com/DeadlyParkour777/code-checker/services/result_service/internal/store"
+	"github.com/DeadlyParkour777/code-checker/services/result_service/internal/types"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -27,16 +30,50 @@ func (h *GrpcHandler) GetUserSubmissions(ctx context.Context, req *resultpb.GetU
 
 	pbSubmissions := make([]*resultpb.Submission, len(submissions))
 	for i, sub := range submissions {
-		pbSubmissions[i] = &resultpb.Submission{
-			Id:        sub.ID,
-			ProblemId: sub.ProblemID,
-			UserId:    sub.UserID,
-			Language:  sub.Language,
-			Status:    sub.Status,
-			CreatedAt: sub.CreatedAt.Format(time.RFC3339),
-			UpdatedAt: sub.UpdatedAt.Format(time.RFC3339),
-		}
+		pbSubmissions[i] = toSubmissionPB(sub)
 	}
 
 	return &resultpb.GetUserSubmissionsResponse{Submissions: pbSubmissions}, nil
 }
+
+func (h *GrpcHandler) GetSubmission(ctx context.Context, req *resultpb.GetSubmissionRequest) (*resultpb.Submission, error) {
+	sub, err := h.service.GetSubmission(ctx, req.GetId())
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get submission: %v", err)
+	}
+	return toSubmissionPB(sub), nil
+}
+
+func toSubmissionPB(sub *types.Submission) *resultpb.Submission {
+	pbTests := make([]*resultpb.TestResult, len(sub.Tests))
+	for j, tr := range sub.Tests {
+		pbTests[j] = &resultpb.TestResult{
+			Index:    int32(tr.Index),
+			Status:   tr.Status,
+			TimeMs:   tr.TimeMs,
+			MemoryKb: tr.MemoryKb,
+			ExitCode: int32(tr.ExitCode),
+			Stderr:   tr.Stderr,
+		}
+	}
+
+	return &resultpb.Submission{
+		Id:          sub.ID,
+		ProblemId:   sub.ProblemID,
+		UserId:      sub.UserID,
+		Language:    sub.Language,
+		Status:      sub.Status,
+		CreatedAt:   sub.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:   sub.UpdatedAt.Format(time.RFC3339),
+		Tests:       pbTests,
+		MaxMemoryKb: sub.MaxMemoryKb,
+		Score:       sub.Score,
+		MaxScore:    sub.MaxScore,
+		Stage:       sub.Stage,
+		CurrentTest: int32(sub.CurrentTest),
+		TotalTests:  int32(sub.TotalTests),
+	}
+}
@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	resultpb "github.com/DeadlyParkour777/code-checker/pkg/result"
+	"github.com/DeadlyParkour777/code-checker/services/result_service/internal/service"
+	"github.com/DeadlyParkour777/code-checker/services/result_service/internal/store"
+	"github.com/DeadlyParkour777/code-checker/services/result_service/internal/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakeService struct {
+	service.Service
+	submission *types.Submission
+	err        error
+}
+
+func (f *fakeService) GetSubmission(ctx context.Context, id string) (*types.Submission, error) {
+	return f.submission, f.err
+}
+
+func TestGetSubmissionTestResults(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	handler := NewGrpcHandler(&fakeService{submission: &types.Submission{
+		ID:        "s1",
+		UserID:    "u1",
+		Status:    "RE",
+		CreatedAt: now,
+		UpdatedAt: now,
+		Tests: []types.TestResult{
+			{Index: 1, Status: "AC", TimeMs: 12, MemoryKb: 1024},
+			{Index: 2, Status: "RE", TimeMs: 30, MemoryKb: 2048, ExitCode: 1, Stderr: "ошибка"},
+		},
+	}})
+
+	resp, err := handler.GetSubmission(context.Background(), &resultpb.GetSubmissionRequest{Id: "s1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.GetStatus() != "RE" || resp.GetCreatedAt() != "2026-01-02T03:04:05Z" || len(resp.GetTests()) != 2 {
+		t.Fatalf("unexpected submission: %v", resp)
+	}
+	failed := resp.GetTests()[1]
+	if failed.GetIndex() != 2 || failed.GetStatus() != "RE" || failed.GetTimeMs() != 30 || failed.GetMemoryKb() != 2048 ||
+		failed.GetExitCode() != 1 || failed.GetStderr() != "ошибка" {
+		t.Fatalf("unexpected test result: %v", failed)
+	}
+}
+
+func TestGetSubmissionNotFound(t *testing.T) {
+	handler := NewGrpcHandler(&fakeService{err: fmt.Errorf("%w: s1", store.ErrNotFound)})
+
+	_, err := handler.GetSubmission(context.Background(), &resultpb.GetSubmissionRequest{Id: "s1"})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("expected not found, got %v", status.Code(err))
+	}
+}
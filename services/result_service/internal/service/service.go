@@ -11,6 +11,7 @@ import (
 type Service interface {
 	ProcessResult(ctx context.Context, result *types.ResultEvent)
 	GetUserSubmissions(ctx context.Context, userID string) ([]*types.Submission, error)
+	GetSubmission(ctx context.Context, id string) (*types.Submission, error)
 }
 
 type service struct {
@@ -23,7 +24,11 @@ func NewService(store store.Store) Service {
 
 func (s *service) ProcessResult(ctx context.Context, result *types.ResultEvent) {
 	go func() {
-		err := s.store.UpdateSubmissionStatus(context.Background(), result.SubmissionID, result.Status)
+		save := s.store.SaveResult
+		if result.Status == types.StatusJudging {
+			save = s.store.SaveProgress
+		}
+		err := save(context.Background(), result)
 		if err != nil {
 			log.Printf("Error processing result for submission %s: %v", result.SubmissionID, err)
 		}
@@ -33,3 +38,7 @@ func (s *service) ProcessResult(ctx context.Context, result *types.ResultEvent)
 func (s *service) GetUserSubmissions(ctx context.Context, userID string) ([]*types.Submission, error) {
 	return s.store.GetUserSubmissions(ctx, userID)
 }
+
+func (s *service) GetSubmission(ctx context.Context, id string) (*types.Submission, error) {
+	return s.store.GetSubmission(ctx, id)
+}
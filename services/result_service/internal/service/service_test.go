@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/DeadlyParkour777/code-checker/services/result_service/internal/store"
+	"github.com/DeadlyParkour777/code-checker/services/result_service/internal/types"
+)
+
+// fakeStore passes on what ProcessResult saves, which happens in the
+// background.
+type fakeStore struct {
+	store.Store
+	results  chan *types.ResultEvent
+	progress chan *types.ResultEvent
+}
+
+func newFakeStore() *fakeStore {
+	return &fakeStore{results: make(chan *types.ResultEvent, 1), progress: make(chan *types.ResultEvent, 1)}
+}
+
+func (f *fakeStore) SaveResult(ctx context.Context, result *types.ResultEvent) error {
+	f.results <- result
+	return nil
+}
+
+func (f *fakeStore) SaveProgress(ctx context.Context, progress *types.ResultEvent) error {
+	f.progress <- progress
+	return nil
+}
+
+func received(t *testing.T, events chan *types.ResultEvent) *types.ResultEvent {
+	t.Helper()
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(time.Second):
+		t.Fatalf("the event was not saved")
+		return nil
+	}
+}
+
+func TestProcessResultSavesTestResults(t *testing.T) {
+	// A verdict as the judge publishes it.
+	data := `{"submission_id": "s1", "status": "RE", "message": "Runtime Error (Exit Code: 1)", "max_memory_kb": 2048,
+		"tests": [
+			{"index": 1, "status": "AC", "time_ms": 12, "memory_kb": 1024, "exit_code": 0},
+			{"index": 2, "status": "RE", "time_ms": 30, "memory_kb": 2048, "exit_code": 1, "stderr": "ошибка\n... (truncated)"}
+		]}`
+	var event types.ResultEvent
+	if err := json.Unmarshal([]byte(data), &event); err != nil {
+		t.Fatalf("failed to decode the event: %v", err)
+	}
+	store := newFakeStore()
+	NewService(store).ProcessResult(context.Background(), &event)
+
+	saved := received(t, store.results)
+	if saved.Status != "RE" || len(saved.Tests) != 2 {
+		t.Fatalf("unexpected result: %+v", saved)
+	}
+	want := []types.TestResult{
+		{Index: 1, Status: "AC", TimeMs: 12, MemoryKb: 1024},
+		{Index: 2, Status: "RE", TimeMs: 30, MemoryKb: 2048, ExitCode: 1, Stderr: "ошибка\n... (truncated)"},
+	}
+	for i, tr := range saved.Tests {
+		if tr != want[i] {
+			t.Fatalf("unexpected test %d: %+v", i+1, tr)
+		}
+	}
+}
+
+func TestProcessResultSavesProgress(t *testing.T) {
+	store := newFakeStore()
+	NewService(store).ProcessResult(context.Background(), &types.ResultEvent{SubmissionID: "s1", Status: types.StatusJudging, CurrentTest: 1, TotalTests: 2})
+
+	saved := received(t, store.progress)
+	if saved.CurrentTest != 1 || saved.TotalTests != 2 || len(store.results) != 0 {
+		t.Fatalf("unexpected progress: %+v", saved)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"time"
@@ -13,9 +14,13 @@ import (
 	"github.com/redis/go-redis/v9"
 )
 
+var ErrNotFound = errors.New("submission not found")
+
 type Store interface {
-	UpdateSubmissionStatus(ctx context.Context, submissionID, status string) error
+	SaveResult(ctx context.Context, result *types.ResultEvent) error
+	SaveProgress(ctx context.Context, progress *types.ResultEvent) error
 	GetUserSubmissions(ctx context.Context, userID string) ([]*types.Submission, error)
+	GetSubmission(ctx context.Context, id string) (*types.Submission, error)
 }
 
 type store struct {
@@ -27,21 +32,84 @@ func NewStore(db *sql.DB, cache *redis.Client) Store {
 	return &store{db: db, cache: cache}
 }
 
-func (s *store) UpdateSubmissionStatus(ctx context.Context, submissionID, status string) error {
-	query := `UPDATE submissions SET status = $1, updated_at = $2 WHERE id = $3 RETURNING user_id`
+func (s *store) SaveResult(ctx context.Context, result *types.ResultEvent) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE submissions SET status = $1, max_memory_kb = $2, score = $3, max_score = $4, updated_at = $5,
+	          stage = '', current_test = 0
+	          WHERE id = $6 RETURNING user_id`
 	var userID string
-	err := s.db.QueryRowContext(ctx, query, status, time.Now(), submissionID).Scan(&userID)
+	err = tx.QueryRowContext(ctx, query, result.Status, result.MaxMemoryKb, result.Score, result.MaxScore, time.Now(), result.SubmissionID).Scan(&userID)
 	if err != nil {
 		return fmt.Errorf("failed to update submission in db: %w", err)
 	}
 
+	if _, err := tx.ExecContext(ctx, `DELETE FROM submission_test_results WHERE submission_id = $1`, result.SubmissionID); err != nil {
+		return fmt.Errorf("failed to clear test results: %w", err)
+	}
+
+	insertQuery := `INSERT INTO submission_test_results (submission_id, test_index, status, time_ms, memory_kb, exit_code, stderr)
+	                VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	for _, tr := range result.Tests {
+		_, err := tx.ExecContext(ctx, insertQuery, result.SubmissionID, tr.Index, tr.Status, tr.TimeMs, tr.MemoryKb, tr.ExitCode, tr.Stderr)
+		if err != nil {
+			return fmt.Errorf("failed to insert test result %d: %w", tr.Index, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit result: %w", err)
+	}
+
+	s.invalidate(ctx, userID)
+	s.publish(ctx, result)
+
+	log.Printf("Updated submission %s to status %s (%d tests) and invalidated cache for user %s", result.SubmissionID, result.Status, len(result.Tests), userID)
+	return nil
+}
+
+// SaveProgress moves a submission to the Judging state. Events are processed
+// concurrently, so one that arrives after the verdict is dropped instead of
+// reopening the submission.
+func (s *store) SaveProgress(ctx context.Context, progress *types.ResultEvent) error {
+	query := `UPDATE submissions SET status = $1, stage = $2, current_test = $3, total_tests = $4, updated_at = $5
+	          WHERE id = $6 AND status IN ('Pending', $1) RETURNING user_id`
+	var userID string
+	err := s.db.QueryRowContext(ctx, query, types.StatusJudging, progress.Stage, progress.CurrentTest, progress.TotalTests, time.Now(), progress.SubmissionID).Scan(&userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update submission progress in db: %w", err)
+	}
+
+	s.invalidate(ctx, userID)
+	s.publish(ctx, progress)
+	return nil
+}
+
+// publish tells subscribers of the submission, such as the gateway's event
+// stream, that its state changed.
+func (s *store) publish(ctx context.Context, result *types.ResultEvent) {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return
+	}
+	channel := fmt.Sprintf("submission-events:%s", result.SubmissionID)
+	if err := s.cache.Publish(ctx, channel, data).Err(); err != nil {
+		log.Printf("Failed to publish event for submission %s: %v", result.SubmissionID, err)
+	}
+}
+
+func (s *store) invalidate(ctx context.Context, userID string) {
 	cacheKey := fmt.Sprintf("submissions:%s", userID)
 	if err := s.cache.Del(ctx, cacheKey).Err(); err != nil {
 		log.Printf("Failed to invalidate cache for user %s: %v", userID, err)
 	}
-
-	log.Printf("Updated submission %s to status %s and invalidated cache for user %s", submissionID, status, userID)
-	return nil
 }
 
 func (s *store) GetUserSubmissions(ctx context.Context, userID string) ([]*types.Submission, error) {
@@ -59,7 +127,8 @@ func (s *store) GetUserSubmissions(ctx context.Context, userID string) ([]*types
 	log.Printf("Cache MISS for user %s", userID)
 
 	var submissions []*types.Submission
-	query := `SELECT id, problem_id, user_id, language, status, created_at, updated_at 
+	query := `SELECT id, problem_id, user_id, language, status, max_memory_kb, score, max_score, stage, current_test, total_tests,
+	          created_at, updated_at
 	          FROM submissions WHERE user_id = $1 ORDER BY created_at DESC`
 	rows, err := s.db.QueryContext(ctx, query, userID)
 	if err != nil {
@@ -67,12 +136,18 @@ func (s *store) GetUserSubmissions(ctx context.Context, userID string) ([]*types
 	}
 	defer rows.Close()
 
+	byID := make(map[string]*types.Submission)
 	for rows.Next() {
 		sub := &types.Submission{}
-		if err := rows.Scan(&sub.ID, &sub.ProblemID, &sub.UserID, &sub.Language, &sub.Status, &sub.CreatedAt, &sub.UpdatedAt); err != nil {
+		if err := rows.Scan(&sub.ID, &sub.ProblemID, &sub.UserID, &sub.Language, &sub.Status, &sub.MaxMemoryKb, &sub.Score, &sub.MaxScore, &sub.Stage, &sub.CurrentTest, &sub.TotalTests, &sub.CreatedAt, &sub.UpdatedAt); err != nil {
 			return nil, fmt.Errorf("failed to scan submission: %w", err)
 		}
 		submissions = append(submissions, sub)
+		byID[sub.ID] = sub
+	}
+
+	if err := s.loadTestResults(ctx, userID, byID); err != nil {
+		return nil, err
 	}
 
 	jsonData, err := json.Marshal(submissions)
@@ -82,3 +157,64 @@ func (s *store) GetUserSubmissions(ctx context.Context, userID string) ([]*types
 
 	return submissions, nil
 }
+
+// GetSubmission bypasses the history cache: it is used to follow a
+// submission that may have been created a moment ago.
+func (s *store) GetSubmission(ctx context.Context, id string) (*types.Submission, error) {
+	query := `SELECT id, problem_id, user_id, language, status, max_memory_kb, score, max_score, stage, current_test, total_tests,
+	          created_at, updated_at
+	          FROM submissions WHERE id = $1`
+	sub := &types.Submission{}
+	err := s.db.QueryRowContext(ctx, query, id).Scan(&sub.ID, &sub.ProblemID, &sub.UserID, &sub.Language, &sub.Status, &sub.MaxMemoryKb, &sub.Score, &sub.MaxScore, &sub.Stage, &sub.CurrentTest, &sub.TotalTests, &sub.CreatedAt, &sub.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get submission from db: %w", err)
+	}
+
+	rows, err := s.db.QueryContext(ctx, `SELECT test_index, status, time_ms, memory_kb, exit_code, stderr
+	          FROM submission_test_results WHERE submission_id = $1 ORDER BY test_index`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get test results from db: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tr types.TestResult
+		if err := rows.Scan(&tr.Index, &tr.Status, &tr.TimeMs, &tr.MemoryKb, &tr.ExitCode, &tr.Stderr); err != nil {
+			return nil, fmt.Errorf("failed to scan test result: %w", err)
+		}
+		sub.Tests = append(sub.Tests, tr)
+	}
+	return sub, rows.Err()
+}
+
+func (s *store) loadTestResults(ctx context.Context, userID string, byID map[string]*types.Submission) error {
+	if len(byID) == 0 {
+		return nil
+	}
+
+	query := `SELECT r.submission_id, r.test_index, r.status, r.time_ms, r.memory_kb, r.exit_code, r.stderr
+	          FROM submission_test_results r
+	          JOIN submissions s ON s.id = r.submission_id
+	          WHERE s.user_id = $1 ORDER BY r.submission_id, r.test_index`
+	rows, err := s.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get test results from db: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var submissionID string
+		var tr types.TestResult
+		if err := rows.Scan(&submissionID, &tr.Index, &tr.Status, &tr.TimeMs, &tr.MemoryKb, &tr.ExitCode, &tr.Stderr); err != nil {
+			return fmt.Errorf("failed to scan test result: %w", err)
+		}
+		if sub, ok := byID[submissionID]; ok {
+			sub.Tests = append(sub.Tests, tr)
+		}
+	}
+
+	return rows.Err()
+}
@@ -4,18 +4,44 @@ import (
 	"time"
 )
 
+type TestResult struct {
+	Index    int    `json:"index"`
+	Status   string `json:"status"`
+	TimeMs   int64  `json:"time_ms"`
+	MemoryKb int64  `json:"memory_kb"`
+	ExitCode int    `json:"exit_code"`
+	Stderr   string `json:"stderr,omitempty"`
+}
+
+// StatusJudging is reported by the judge while a submission is in progress.
+const StatusJudging = "Judging"
+
 type ResultEvent struct {
-	SubmissionID string `json:"submission_id"`
-	Status       string `json:"status"`
-	Message      string `json:"message,omitempty"`
+	SubmissionID string       `json:"submission_id"`
+	Status       string       `json:"status"`
+	Message      string       `json:"message,omitempty"`
+	MaxMemoryKb  int64        `json:"max_memory_kb,omitempty"`
+	Score        float64      `json:"score,omitempty"`
+	MaxScore     float64      `json:"max_score,omitempty"`
+	Tests        []TestResult `json:"tests,omitempty"`
+	Stage        string       `json:"stage,omitempty"`
+	CurrentTest  int          `json:"current_test,omitempty"`
+	TotalTests   int          `json:"total_tests,omitempty"`
 }
 
 type Submission struct {
-	ID        string
-	ProblemID string
-	UserID    string
-	Language  string
-	Status    string
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID          string
+	ProblemID   string
+	UserID      string
+	Language    string
+	Status      string
+	MaxMemoryKb int64
+	Score       float64
+	MaxScore    float64
+	Tests       []TestResult
+	Stage       string
+	CurrentTest int
+	TotalTests  int
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
 }
@@ -1,16 +1,20 @@
 package app
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"log"
 	"net"
 
 	submission_service "github.com/DeadlyParkour777/code-checker/pkg/submission"
+	"github.com/DeadlyParkour777/code-checker/services/submission_service/internal/archive"
 	"github.com/DeadlyParkour777/code-checker/services/submission_service/internal/config"
+	"github.com/DeadlyParkour777/code-checker/services/submission_service/internal/events"
 	"github.com/DeadlyParkour777/code-checker/services/submission_service/internal/handler"
 	"github.com/DeadlyParkour777/code-checker/services/submission_service/internal/service"
 	"github.com/DeadlyParkour777/code-checker/services/submission_service/internal/store"
+	"github.com/redis/go-redis/v9"
 	"github.com/segmentio/kafka-go"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
@@ -22,6 +26,7 @@ type App struct {
 	grpcServer  *grpc.Server
 	db          *sql.DB
 	kafkaWriter *kafka.Writer
+	redisClient *redis.Client
 }
 
 func New(cfg config.Config) (*App, error) {
@@ -44,16 +49,30 @@ func New(cfg config.Config) (*App, error) {
 	})
 	log.Println("Kafka producer initialized")
 
+	redisClient := redis.NewClient(&redis.Options{
+		Addr:     cfg.RedisAddr,
+		Password: cfg.RedisPassword,
+		DB:       cfg.RedisDB,
+	})
+	if err := redisClient.Ping(context.Background()).Err(); err != nil {
+		return nil, fmt.Errorf("failed to connect to redis: %w", err)
+	}
+	log.Println("Successfully connected to Redis")
+
 	appStore := store.NewStore(db)
 	appService, err := service.NewService(
 		appStore,
 		cfg.ProblemServiceAddr,
 		kafkaProducer,
+		events.NewRedisSubmissionEvents(redisClient),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create service: %w", err)
 	}
-	grpcHandler := handler.NewGrpcHandler(appService)
+	grpcHandler := handler.NewGrpcHandler(appService, archive.Limits{
+		MaxFiles: cfg.MaxSubmissionFiles,
+		MaxBytes: cfg.MaxSubmissionBytes,
+	})
 
 	grpcServer := grpc.NewServer()
 	submission_service.RegisterSubmissionServiceServer(grpcServer, grpcHandler)
@@ -64,12 +83,14 @@ func New(cfg config.Config) (*App, error) {
 		grpcServer:  grpcServer,
 		db:          db,
 		kafkaWriter: kafkaProducer,
+		redisClient: redisClient,
 	}, nil
 }
 
 func (a *App) Run() error {
 	defer a.db.Close()
 	defer a.kafkaWriter.Close()
+	defer a.redisClient.Close()
 
 	listenAddr := fmt.Sprintf(":%s", a.cfg.GRPCPort)
 
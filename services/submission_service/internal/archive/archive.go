@@ -0,0 +1,218 @@
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/DeadlyParkour777/code-checker/services/submission_service/internal/types"
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("unsupported archive format, expected zip, tar or tar.gz")
+	ErrInvalidPath       = errors.New("invalid file path")
+	ErrTooManyFiles      = errors.New("too many files")
+	ErrTooLarge          = errors.New("files are too large")
+)
+
+// Limits bound the unpacked file tree of a submission. MaxBytes counts the
+// uncompressed content, so a small archive cannot expand past it.
+type Limits struct {
+	MaxFiles int
+	MaxBytes int64
+}
+
+// Extract unpacks a zip, tar or tar.gz archive into a file tree. Directories
+// are implied by file paths; links and other special entries are rejected. If
+// every file sits under the same top-level directory, it is stripped.
+func Extract(data []byte, limits Limits) ([]types.File, error) {
+	t := newTree(limits)
+	var err error
+	switch {
+	case bytes.HasPrefix(data, []byte("PK\x03\x04")):
+		err = t.addZip(data)
+	case bytes.HasPrefix(data, []byte{0x1f, 0x8b}):
+		var gz *gzip.Reader
+		if gz, err = gzip.NewReader(bytes.NewReader(data)); err == nil {
+			err = t.addTar(gz)
+		}
+	case len(data) > 262 && string(data[257:262]) == "ustar":
+		err = t.addTar(bytes.NewReader(data))
+	default:
+		err = ErrUnsupportedFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(t.files) == 0 {
+		return nil, errors.New("archive has no files")
+	}
+	return stripCommonDir(t.sorted()), nil
+}
+
+// Check validates the files of a multi-file submission against the same rules
+// and returns them with clean paths.
+func Check(files []types.File, limits Limits) ([]types.File, error) {
+	t := newTree(limits)
+	for _, f := range files {
+		if err := t.add(f.Path, strings.NewReader(f.Content)); err != nil {
+			return nil, err
+		}
+	}
+	return t.sorted(), nil
+}
+
+// CleanPath normalizes a relative slash-separated path and rejects absolute
+// paths and paths that climb out of the submission directory.
+func CleanPath(name string) (string, error) {
+	name = strings.ReplaceAll(name, `\`, "/")
+	if name == "" || path.IsAbs(name) || strings.Contains(name, ":") {
+		return "", fmt.Errorf("%w: %q", ErrInvalidPath, name)
+	}
+	for _, elem := range strings.Split(name, "/") {
+		if elem == ".." {
+			return "", fmt.Errorf("%w: %q", ErrInvalidPath, name)
+		}
+	}
+	clean := path.Clean(name)
+	if clean == "." {
+		return "", fmt.Errorf("%w: %q", ErrInvalidPath, name)
+	}
+	return clean, nil
+}
+
+type tree struct {
+	limits Limits
+	size   int64
+	files  map[string]string
+	// dirs holds the directories implied by the file paths, so a path cannot
+	// be both a file and a directory.
+	dirs map[string]bool
+}
+
+func newTree(limits Limits) *tree {
+	return &tree{limits: limits, files: make(map[string]string), dirs: make(map[string]bool)}
+}
+
+func (t *tree) add(name string, r io.Reader) error {
+	clean, err := CleanPath(name)
+	if err != nil {
+		return err
+	}
+	if _, ok := t.files[clean]; ok {
+		return fmt.Errorf("duplicate file %q", clean)
+	}
+	if t.dirs[clean] {
+		return fmt.Errorf("file %q is also a directory", clean)
+	}
+	for dir := path.Dir(clean); dir != "."; dir = path.Dir(dir) {
+		if _, ok := t.files[dir]; ok {
+			return fmt.Errorf("directory of %q is also a file %q", clean, dir)
+		}
+	}
+	if len(t.files) >= t.limits.MaxFiles {
+		return fmt.Errorf("%w: at most %d are allowed", ErrTooManyFiles, t.limits.MaxFiles)
+	}
+
+	remaining := t.limits.MaxBytes - t.size
+	content, err := io.ReadAll(io.LimitReader(r, remaining+1))
+	if err != nil {
+		return fmt.Errorf("failed to read %q: %w", clean, err)
+	}
+	if int64(len(content)) > remaining {
+		return fmt.Errorf("%w: at most %d bytes are allowed", ErrTooLarge, t.limits.MaxBytes)
+	}
+	if !utf8.Valid(content) {
+		return fmt.Errorf("%q is not a UTF-8 text file", clean)
+	}
+	t.size += int64(len(content))
+	t.files[clean] = string(content)
+	for dir := path.Dir(clean); dir != "."; dir = path.Dir(dir) {
+		t.dirs[dir] = true
+	}
+	return nil
+}
+
+func (t *tree) addZip(data []byte) error {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return fmt.Errorf("failed to read zip archive: %w", err)
+	}
+	for _, f := range zr.File {
+		mode := f.Mode()
+		if mode.IsDir() {
+			continue
+		}
+		if !mode.IsRegular() {
+			return fmt.Errorf("%q is not a regular file", f.Name)
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return fmt.Errorf("failed to open %q: %w", f.Name, err)
+		}
+		err = t.add(f.Name, rc)
+		rc.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *tree) addTar(r io.Reader) error {
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read tar archive: %w", err)
+		}
+		switch hdr.Typeflag {
+		case tar.TypeDir, tar.TypeXGlobalHeader:
+			continue
+		case tar.TypeReg:
+			if err := t.add(hdr.Name, tr); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("%q is not a regular file", hdr.Name)
+		}
+	}
+}
+
+func (t *tree) sorted() []types.File {
+	files := make([]types.File, 0, len(t.files))
+	for name, content := range t.files {
+		files = append(files, types.File{Path: name, Content: content})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	return files
+}
+
+// stripCommonDir removes the directory an archive was packed from, e.g. the
+// "project/" of "project/main.go".
+func stripCommonDir(files []types.File) []types.File {
+	dir, _, ok := strings.Cut(files[0].Path, "/")
+	if !ok {
+		return files
+	}
+	for _, f := range files {
+		if !strings.HasPrefix(f.Path, dir+"/") {
+			return files
+		}
+	}
+	for i := range files {
+		files[i].Path = strings.TrimPrefix(files[i].Path, dir+"/")
+	}
+	return files
+}
@@ -0,0 +1,136 @@
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/DeadlyParkour777/code-checker/services/submission_service/internal/types"
+)
+
+var testLimits = Limits{MaxFiles: 3, MaxBytes: 64}
+
+func zipArchive(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatalf("zip create: %v", err)
+		}
+		w.Write([]byte(content))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("zip close: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func tarArchive(t *testing.T, headers ...*tar.Header) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, hdr := range headers {
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatalf("tar header: %v", err)
+		}
+		tw.Write(bytes.Repeat([]byte("x"), int(hdr.Size)))
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("tar close: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestExtractZip(t *testing.T) {
+	files, err := Extract(zipArchive(t, map[string]string{
+		"project/main.go":          "package main",
+		"project/internal/calc.go": "package calc",
+	}), testLimits)
+	if err != nil {
+		t.Fatalf("extract: %v", err)
+	}
+	want := []types.File{
+		{Path: "internal/calc.go", Content: "package calc"},
+		{Path: "main.go", Content: "package main"},
+	}
+	if len(files) != len(want) || files[0] != want[0] || files[1] != want[1] {
+		t.Fatalf("unexpected files: %+v", files)
+	}
+}
+
+func TestExtractTarGz(t *testing.T) {
+	data := tarArchive(t,
+		&tar.Header{Name: "pkg/", Typeflag: tar.TypeDir, Mode: 0o755},
+		&tar.Header{Name: "pkg/__init__.py", Typeflag: tar.TypeReg, Mode: 0o644, Size: 1},
+		&tar.Header{Name: "main.py", Typeflag: tar.TypeReg, Mode: 0o644, Size: 2},
+	)
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	gz.Write(data)
+	gz.Close()
+
+	files, err := Extract(buf.Bytes(), testLimits)
+	if err != nil {
+		t.Fatalf("extract: %v", err)
+	}
+	if len(files) != 2 || files[0].Path != "main.py" || files[1].Path != "pkg/__init__.py" {
+		t.Fatalf("unexpected files: %+v", files)
+	}
+}
+
+func TestExtractRejects(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want error
+	}{
+		{"traversal", zipArchive(t, map[string]string{"../evil.go": "x"}), ErrInvalidPath},
+		{"absolute", tarArchive(t, &tar.Header{Name: "/etc/cron.d/x", Typeflag: tar.TypeReg, Size: 1}), ErrInvalidPath},
+		{"too many files", zipArchive(t, map[string]string{"a": "", "b": "", "c": "", "d": ""}), ErrTooManyFiles},
+		{"too large", zipArchive(t, map[string]string{"main.go": strings.Repeat("x", 65)}), ErrTooLarge},
+		{"plain text", []byte("package main"), ErrUnsupportedFormat},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Extract(tt.data, testLimits); !errors.Is(err, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+
+	symlink := tarArchive(t, &tar.Header{Name: "main.go", Typeflag: tar.TypeSymlink, Linkname: "/etc/passwd"})
+	if _, err := Extract(symlink, testLimits); err == nil {
+		t.Fatalf("expected a symlink to be rejected")
+	}
+}
+
+func TestCheck(t *testing.T) {
+	files, err := Check([]types.File{{Path: "./main.py", Content: "import util"}, {Path: "util.py"}}, testLimits)
+	if err != nil {
+		t.Fatalf("check: %v", err)
+	}
+	if files[0].Path != "main.py" || files[1].Path != "util.py" {
+		t.Fatalf("unexpected files: %+v", files)
+	}
+
+	if _, err := Check([]types.File{{Path: "a/../../b"}}, testLimits); !errors.Is(err, ErrInvalidPath) {
+		t.Fatalf("expected an invalid path, got %v", err)
+	}
+	if _, err := Check([]types.File{{Path: "main.py"}, {Path: "main.py"}}, testLimits); err == nil {
+		t.Fatalf("expected duplicate files to be rejected")
+	}
+	for _, paths := range [][]string{{"a", "a/b.go"}, {"a/b.go", "a"}, {"a/b", "a/b/c/d.go"}, {"a/b/c/d.go", "a/b"}} {
+		if _, err := Check([]types.File{{Path: paths[0]}, {Path: paths[1]}}, testLimits); err == nil {
+			t.Fatalf("expected %q and %q to clash", paths[0], paths[1])
+		}
+	}
+	if _, err := Check([]types.File{{Path: "a/b.go"}, {Path: "a/c/d.go"}, {Path: "ab.go"}}, testLimits); err != nil {
+		t.Fatalf("check: %v", err)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strconv"
 	"strings"
 
 	"github.com/joho/godotenv"
@@ -15,6 +16,9 @@ type Config struct {
 	KafkaBrokers     []string
 	SubmissionsTopic string
 
+	MaxSubmissionFiles int
+	MaxSubmissionBytes int64
+
 	DBHost     string
 	DBPort     string
 	DBUser     string
@@ -23,6 +27,10 @@ type Config struct {
 
 	AuthServiceAddr    string
 	ProblemServiceAddr string
+
+	RedisAddr     string
+	RedisPassword string
+	RedisDB       int
 }
 
 func ConfigInit() Config {
@@ -30,10 +38,16 @@ func ConfigInit() Config {
 		log.Println("No .env file found, relying on environment variables")
 	}
 
+	maxFiles, _ := strconv.Atoi(getEnv("SUBMISSION_MAX_FILES", "100"))
+	maxSizeKB, _ := strconv.Atoi(getEnv("SUBMISSION_MAX_SIZE_KB", "512"))
+	redisDB, _ := strconv.Atoi(getEnv("REDIS_DB", "0"))
+
 	return Config{
 		GRPCPort:           getEnv("GRPC_PORT", "8004"),
 		KafkaBrokers:       strings.Split(getEnv("KAFKA_BROKERS", "kafka:9092"), ","),
 		SubmissionsTopic:   getEnv("SUBMISSIONS_TOPIC", "submissions"),
+		MaxSubmissionFiles: maxFiles,
+		MaxSubmissionBytes: int64(maxSizeKB) * 1024,
 		DBHost:             getEnv("DB_HOST", "localhost"),
 		DBPort:             getEnv("DB_PORT", "5432"),
 		DBUser:             getEnv("DB_USER", "postgres"),
@@ -41,6 +55,9 @@ func ConfigInit() Config {
 		DBName:             getEnv("DB_NAME", "authdb"),
 		AuthServiceAddr:    getEnv("AUTH_SERVICE_ADDR", "auth-service:8001"),
 		ProblemServiceAddr: getEnv("PROBLEM_SERVICE_ADDR", "problem-service:8002"),
+		RedisAddr:          getEnv("REDIS_ADDR", "redis:6379"),
+		RedisPassword:      getEnv("REDIS_PASSWORD", ""),
+		RedisDB:            redisDB,
 	}
 }
 
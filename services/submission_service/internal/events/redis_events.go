@@ -0,0 +1,53 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/DeadlyParkour777/code-checker/services/submission_service/internal/types"
+	"github.com/redis/go-redis/v9"
+)
+
+// SubmissionEvents announces submissions that went back to Pending without
+// result_service, which otherwise keeps their readers up to date: it drops
+// the cached submission history of their users and publishes the new state to
+// their event streams. Both are best effort, like in result_service.
+type SubmissionEvents interface {
+	Reset(ctx context.Context, submissions []*types.Submission)
+}
+
+type redisSubmissionEvents struct {
+	client *redis.Client
+}
+
+func NewRedisSubmissionEvents(client *redis.Client) SubmissionEvents {
+	return &redisSubmissionEvents{client: client}
+}
+
+// statusEvent is the part of result_service's events a reset changes.
+type statusEvent struct {
+	SubmissionID string `json:"submission_id"`
+	Status       string `json:"status"`
+}
+
+func (e *redisSubmissionEvents) Reset(ctx context.Context, submissions []*types.Submission) {
+	invalidated := make(map[string]bool)
+	for _, sub := range submissions {
+		if !invalidated[sub.UserID] {
+			invalidated[sub.UserID] = true
+			if err := e.client.Del(ctx, fmt.Sprintf("submissions:%s", sub.UserID)).Err(); err != nil {
+				log.Printf("Failed to invalidate cache for user %s: %v", sub.UserID, err)
+			}
+		}
+
+		data, err := json.Marshal(statusEvent{SubmissionID: sub.ID, Status: sub.Status})
+		if err != nil {
+			continue
+		}
+		if err := e.client.Publish(ctx, fmt.Sprintf("submission-events:%s", sub.ID), data).Err(); err != nil {
+			log.Printf("Failed to publish event for submission %s: %v", sub.ID, err)
+		}
+	}
+}
@@ -2,23 +2,32 @@ package handler
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"io"
 	"log"
 	"time"
 
 	submission_service "github.com/DeadlyParkour777/code-checker/pkg/submission"
+	"github.com/DeadlyParkour777/code-checker/services/submission_service/internal/archive"
 	"github.com/DeadlyParkour777/code-checker/services/submission_service/internal/service"
+	"github.com/DeadlyParkour777/code-checker/services/submission_service/internal/store"
+	"github.com/DeadlyParkour777/code-checker/services/submission_service/internal/types"
+	"github.com/google/uuid"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
+const archiveEntryOverhead = 2048
+
 type GrpcHandler struct {
 	submission_service.UnimplementedSubmissionServiceServer
 	service service.Service
+	limits  archive.Limits
 }
 
-func NewGrpcHandler(service service.Service) *GrpcHandler {
-	return &GrpcHandler{service: service}
+func NewGrpcHandler(service service.Service, limits archive.Limits) *GrpcHandler {
+	return &GrpcHandler{service: service, limits: limits}
 }
 
 func (h *GrpcHandler) CreateSubmission(stream submission_service.SubmissionService_CreateSubmissionServer) error {
@@ -35,6 +44,8 @@ func (h *GrpcHandler) CreateSubmission(stream submission_service.SubmissionServi
 	log.Printf("Received submission info for user %s, problem %s", info.GetUserId(), info.GetProblemId())
 
 	var codeData bytes.Buffer
+	var files []types.File
+	received := 0
 	for {
 		req, err := stream.Recv()
 		if err == io.EOF {
@@ -44,20 +55,53 @@ func (h *GrpcHandler) CreateSubmission(stream submission_service.SubmissionServi
 			return status.Errorf(codes.Internal, "failed to receive code chunk: %v", err)
 		}
 
+		if filePath := req.GetFilePath(); filePath != "" {
+			if len(files) > 0 {
+				files[len(files)-1].Content = codeData.String()
+			} else if codeData.Len() > 0 {
+				return status.Errorf(codes.InvalidArgument, "code chunks must follow a file path in a multi-file submission")
+			}
+			files = append(files, types.File{Path: filePath})
+			codeData.Reset()
+			continue
+		}
+
 		chunk := req.GetChunkData()
+		received += len(chunk)
+		if int64(received) > h.maxReceivedBytes() {
+			return status.Errorf(codes.InvalidArgument, "submission is larger than %d bytes", h.limits.MaxBytes)
+		}
 		if _, err := codeData.Write(chunk); err != nil {
 			return status.Errorf(codes.Internal, "failed to write code chunk to buffer: %v", err)
 		}
 	}
 
-	log.Printf("Received %d bytes of code", codeData.Len())
+	log.Printf("Received %d bytes of code", received)
+
+	code := codeData.String()
+	switch {
+	case info.GetArchive():
+		if len(files) > 0 {
+			return status.Errorf(codes.InvalidArgument, "an archive submission cannot have file paths")
+		}
+		files, err = archive.Extract(codeData.Bytes(), h.limits)
+		code = ""
+	case len(files) > 0:
+		files[len(files)-1].Content = code
+		files, err = archive.Check(files, h.limits)
+		code = ""
+	}
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid submission files: %v", err)
+	}
 
 	submission, err := h.service.CreateSubmission(
 		stream.Context(),
 		info.GetUserId(),
 		info.GetProblemId(),
-		codeData.String(),
+		code,
 		info.GetLanguage(),
+		files,
 	)
 	if err != nil {
 		return status.Errorf(codes.Internal, "failed to create submission: %v", err)
@@ -73,6 +117,84 @@ func (h *GrpcHandler) CreateSubmission(stream submission_service.SubmissionServi
 		CreatedAt: submission.CreatedAt.Format(time.RFC3339),
 		UpdatedAt: submission.UpdatedAt.Format(time.RFC3339),
 	}
+	for _, f := range submission.Files {
+		resp.Files = append(resp.Files, &submission_service.SourceFile{Path: f.Path, Content: f.Content})
+	}
 
 	return stream.SendAndClose(resp)
 }
+
+// maxReceivedBytes leaves room for archive headers on top of the content
+// limit; the unpacked content is checked against the limit itself.
+func (h *GrpcHandler) maxReceivedBytes() int64 {
+	return h.limits.MaxBytes + int64(h.limits.MaxFiles)*archiveEntryOverhead
+}
+
+func (h *GrpcHandler) Rejudge(ctx context.Context, req *submission_service.RejudgeRequest) (*submission_service.RejudgeReport, error) {
+	filter := types.RejudgeFilter{
+		SubmissionID: req.GetSubmissionId(),
+		ProblemID:    req.GetProblemId(),
+		Statuses:     req.GetStatuses(),
+	}
+	var err error
+	if req.GetCreatedAfter() != "" {
+		if filter.CreatedAfter, err = time.Parse(time.RFC3339, req.GetCreatedAfter()); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid created_after: %v", err)
+		}
+	}
+	if req.GetCreatedBefore() != "" {
+		if filter.CreatedBefore, err = time.Parse(time.RFC3339, req.GetCreatedBefore()); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid created_before: %v", err)
+		}
+	}
+	if filter.SubmissionID == "" && filter.ProblemID == "" && len(filter.Statuses) == 0 &&
+		filter.CreatedAfter.IsZero() && filter.CreatedBefore.IsZero() {
+		return nil, status.Errorf(codes.InvalidArgument, "at least one filter is required")
+	}
+
+	rejudge, err := h.service.Rejudge(ctx, req.GetRequestedBy(), filter)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to rejudge: %v", err)
+	}
+	return toRejudgePB(rejudge), nil
+}
+
+func (h *GrpcHandler) GetRejudge(ctx context.Context, req *submission_service.GetRejudgeRequest) (*submission_service.RejudgeReport, error) {
+	if _, err := uuid.Parse(req.GetId()); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid rejudge id: %v", err)
+	}
+	rejudge, err := h.service.GetRejudge(ctx, req.GetId())
+	if errors.Is(err, store.ErrRejudgeNotFound) {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get rejudge: %v", err)
+	}
+	return toRejudgePB(rejudge), nil
+}
+
+func toRejudgePB(rejudge *types.Rejudge) *submission_service.RejudgeReport {
+	report := &submission_service.RejudgeReport{
+		Id:          rejudge.ID,
+		RequestedBy: rejudge.RequestedBy,
+		CreatedAt:   rejudge.CreatedAt.Format(time.RFC3339),
+		Total:       int32(len(rejudge.Entries)),
+	}
+	for _, entry := range rejudge.Entries {
+		if entry.Pending() {
+			report.Pending++
+		}
+		if entry.Changed() {
+			report.Changed++
+		}
+		report.Submissions = append(report.Submissions, &submission_service.RejudgeEntry{
+			SubmissionId:   entry.SubmissionID,
+			PreviousStatus: entry.PreviousStatus,
+			PreviousScore:  entry.PreviousScore,
+			Status:         entry.Status,
+			Score:          entry.Score,
+			Changed:        entry.Changed(),
+		})
+	}
+	return report
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	problem_service "github.com/DeadlyParkour777/code-checker/pkg/problem"
+	"github.com/DeadlyParkour777/code-checker/services/submission_service/internal/events"
 	"github.com/DeadlyParkour777/code-checker/services/submission_service/internal/store"
 	"github.com/DeadlyParkour777/code-checker/services/submission_service/internal/types"
 	"github.com/segmentio/kafka-go"
@@ -16,7 +17,9 @@ import (
 )
 
 type Service interface {
-	CreateSubmission(ctx context.Context, userID, problemID, code, language string) (*types.Submission, error)
+	CreateSubmission(ctx context.Context, userID, problemID, code, language string, files []types.File) (*types.Submission, error)
+	Rejudge(ctx context.Context, requestedBy string, filter types.RejudgeFilter) (*types.Rejudge, error)
+	GetRejudge(ctx context.Context, id string) (*types.Rejudge, error)
 	// GetSubmission(...)
 }
 
@@ -24,9 +27,10 @@ type service struct {
 	store                store.Store
 	kafkaProducer        *kafka.Writer
 	problemServiceClient problem_service.ProblemServiceClient
+	submissionEvents     events.SubmissionEvents
 }
 
-func NewService(store store.Store, problemServiceAddr string, kafkaProducer *kafka.Writer) (Service, error) {
+func NewService(store store.Store, problemServiceAddr string, kafkaProducer *kafka.Writer, submissionEvents events.SubmissionEvents) (Service, error) {
 	conn, err := grpc.NewClient(problemServiceAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to problem service: %w", err)
@@ -36,14 +40,16 @@ func NewService(store store.Store, problemServiceAddr string, kafkaProducer *kaf
 		store:                store,
 		kafkaProducer:        kafkaProducer,
 		problemServiceClient: problemServiceClient,
+		submissionEvents:     submissionEvents,
 	}, nil
 }
 
-func (s *service) CreateSubmission(ctx context.Context, userID, problemID, code, language string) (*types.Submission, error) {
+func (s *service) CreateSubmission(ctx context.Context, userID, problemID, code, language string, files []types.File) (*types.Submission, error) {
 	submission := &types.Submission{
 		ProblemID: problemID,
 		UserID:    userID,
 		Code:      code,
+		Files:     files,
 		Language:  language,
 	}
 
@@ -51,27 +57,76 @@ func (s *service) CreateSubmission(ctx context.Context, userID, problemID, code,
 	if err != nil {
 		return nil, fmt.Errorf("failed to create submission in store: %w", err)
 	}
+	message, err := submissionMessage(createdSubmission)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.kafkaProducer.WriteMessages(ctx, message)
+
+	if err != nil {
+		log.Printf("Failed to write submission event: %v", err)
+		return nil, err
+	}
+
+	return createdSubmission, nil
+}
+
+func submissionMessage(submission *types.Submission) (kafka.Message, error) {
 	event := types.SubmissionEvent{
-		SubmissionID: createdSubmission.ID,
-		ProblemID:    createdSubmission.ProblemID,
-		Code:         createdSubmission.Code,
-		Language:     createdSubmission.Language,
+		SubmissionID: submission.ID,
+		ProblemID:    submission.ProblemID,
+		Code:         submission.Code,
+		Files:        submission.Files,
+		Language:     submission.Language,
 	}
 	message, err := json.Marshal(event)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal submission event: %w", err)
+		return kafka.Message{}, fmt.Errorf("failed to marshal submission event: %w", err)
 	}
 
-	err = s.kafkaProducer.WriteMessages(ctx, kafka.Message{
+	return kafka.Message{
 		// Topic: "submissions",
+		Key:   []byte(submission.ID),
 		Value: message,
 		Time:  time.Now(),
-	})
+	}, nil
+}
+
+// Rejudge queues the matching submissions again. Their previous verdicts are
+// kept with the rejudge, so GetRejudge can tell which ones changed.
+func (s *service) Rejudge(ctx context.Context, requestedBy string, filter types.RejudgeFilter) (*types.Rejudge, error) {
+	queue := func(submissions []*types.Submission) error {
+		if len(submissions) == 0 {
+			return nil
+		}
+		messages := make([]kafka.Message, 0, len(submissions))
+		for _, submission := range submissions {
+			message, err := submissionMessage(submission)
+			if err != nil {
+				return err
+			}
+			messages = append(messages, message)
+		}
+		if err := s.kafkaProducer.WriteMessages(ctx, messages...); err != nil {
+			log.Printf("Failed to queue rejudge: %v", err)
+			return fmt.Errorf("failed to queue submissions: %w", err)
+		}
+		return nil
+	}
 
+	rejudge, submissions, err := s.store.CreateRejudge(requestedBy, filter, queue)
 	if err != nil {
-		log.Printf("Failed to write submission event: %v", err)
-		return nil, err
+		return nil, fmt.Errorf("failed to create rejudge in store: %w", err)
 	}
+	log.Printf("Rejudge %s queued %d submissions", rejudge.ID, len(submissions))
+	// The verdicts were reset behind result_service's back, so its readers
+	// would keep showing them until the judge reports progress.
+	s.submissionEvents.Reset(ctx, submissions)
 
-	return createdSubmission, nil
+	return s.store.GetRejudge(rejudge.ID)
+}
+
+func (s *service) GetRejudge(ctx context.Context, id string) (*types.Rejudge, error) {
+	return s.store.GetRejudge(id)
 }
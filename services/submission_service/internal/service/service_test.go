@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DeadlyParkour777/code-checker/services/submission_service/internal/store"
+	"github.com/DeadlyParkour777/code-checker/services/submission_service/internal/types"
+)
+
+// fakeStore resets submissions without queueing them, so no Kafka writer is
+// needed.
+type fakeStore struct {
+	store.Store
+	reset []*types.Submission
+	err   error
+}
+
+func (f *fakeStore) CreateRejudge(requestedBy string, filter types.RejudgeFilter, queue func([]*types.Submission) error) (*types.Rejudge, []*types.Submission, error) {
+	if f.err != nil {
+		return nil, nil, f.err
+	}
+	return &types.Rejudge{ID: "r1"}, f.reset, nil
+}
+
+func (f *fakeStore) GetRejudge(id string) (*types.Rejudge, error) {
+	return &types.Rejudge{ID: id}, nil
+}
+
+type fakeSubmissionEvents struct {
+	resets [][]*types.Submission
+}
+
+func (e *fakeSubmissionEvents) Reset(ctx context.Context, submissions []*types.Submission) {
+	e.resets = append(e.resets, submissions)
+}
+
+func TestRejudgeAnnouncesReset(t *testing.T) {
+	reset := []*types.Submission{
+		{ID: "s1", UserID: "u1", Status: "Pending"},
+		{ID: "s2", UserID: "u2", Status: "Pending"},
+	}
+	events := &fakeSubmissionEvents{}
+	s := &service{store: &fakeStore{reset: reset}, submissionEvents: events}
+
+	if _, err := s.Rejudge(context.Background(), "admin", types.RejudgeFilter{ProblemID: "p1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events.resets) != 1 || len(events.resets[0]) != 2 || events.resets[0][1].ID != "s2" {
+		t.Fatalf("expected the reset submissions to be announced, got %v", events.resets)
+	}
+}
+
+func TestRejudgeFailureAnnouncesNothing(t *testing.T) {
+	events := &fakeSubmissionEvents{}
+	s := &service{store: &fakeStore{err: errors.New("failed to queue submissions")}, submissionEvents: events}
+
+	if _, err := s.Rejudge(context.Background(), "admin", types.RejudgeFilter{ProblemID: "p1"}); err == nil {
+		t.Fatalf("expected the store error")
+	}
+	if len(events.resets) != 0 {
+		t.Fatalf("expected nothing to be announced, got %v", events.resets)
+	}
+}
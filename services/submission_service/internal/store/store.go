@@ -2,16 +2,23 @@ package store
 
 import (
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/DeadlyParkour777/code-checker/services/submission_service/internal/types"
 	"github.com/google/uuid"
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 )
 
+var ErrRejudgeNotFound = errors.New("rejudge not found")
+
 type Store interface {
 	CreateSubmission(submission *types.Submission) (*types.Submission, error)
 	GetSubmission(id string) (*types.Submission, error)
+	CreateRejudge(requestedBy string, filter types.RejudgeFilter, queue func([]*types.Submission) error) (*types.Rejudge, []*types.Submission, error)
+	GetRejudge(id string) (*types.Rejudge, error)
 }
 
 type store struct {
@@ -26,15 +33,24 @@ func (s *store) CreateSubmission(submission *types.Submission) (*types.Submissio
 	submission.ID = uuid.New().String()
 	submission.Status = "Pending"
 
-	query := `INSERT INTO submissions (id, problem_id, user_id, code, language, status, created_at, updated_at)
-              VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW())
+	files, err := json.Marshal(submission.Files)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal submission files: %w", err)
+	}
+	if submission.Files == nil {
+		files = []byte("[]")
+	}
+
+	query := `INSERT INTO submissions (id, problem_id, user_id, code, files, language, status, created_at, updated_at)
+              VALUES ($1, $2, $3, $4, $5, $6, $7, NOW(), NOW())
 			  RETURNING created_at, updated_at`
 
-	err := s.db.QueryRow(query,
+	err = s.db.QueryRow(query,
 		submission.ID,
 		submission.ProblemID,
 		submission.UserID,
 		submission.Code,
+		files,
 		submission.Language,
 		submission.Status,
 	).Scan(&submission.CreatedAt, &submission.UpdatedAt)
@@ -48,7 +64,8 @@ func (s *store) CreateSubmission(submission *types.Submission) (*types.Submissio
 
 func (s *store) GetSubmission(id string) (*types.Submission, error) {
 	submission := &types.Submission{}
-	query := `SELECT id, problem_id, user_id, code, language, status, created_at, updated_at
+	var files []byte
+	query := `SELECT id, problem_id, user_id, code, files, language, status, created_at, updated_at
 			  FROM submissions WHERE id = $1`
 
 	err := s.db.QueryRow(query, id).Scan(
@@ -56,6 +73,7 @@ func (s *store) GetSubmission(id string) (*types.Submission, error) {
 		&submission.ProblemID,
 		&submission.UserID,
 		&submission.Code,
+		&files,
 		&submission.Language,
 		&submission.Status,
 		&submission.CreatedAt,
@@ -68,6 +86,121 @@ func (s *store) GetSubmission(id string) (*types.Submission, error) {
 		}
 		return nil, fmt.Errorf("failed to get submission: %w", err)
 	}
+	if err := json.Unmarshal(files, &submission.Files); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal submission files: %w", err)
+	}
 
 	return submission, nil
 }
+
+// CreateRejudge records the current verdicts of the matching finished
+// submissions and moves them back to Pending. queue gets the submissions
+// before the transaction commits, so if it fails to queue them again they
+// keep their verdicts instead of staying Pending.
+func (s *store) CreateRejudge(requestedBy string, filter types.RejudgeFilter, queue func([]*types.Submission) error) (*types.Rejudge, []*types.Submission, error) {
+	conditions := []string{`status NOT IN ('Pending', 'Judging')`}
+	args := []any{}
+	add := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)+1))
+	}
+	if filter.SubmissionID != "" {
+		add("id = $%d", filter.SubmissionID)
+	}
+	if filter.ProblemID != "" {
+		add("problem_id = $%d", filter.ProblemID)
+	}
+	if len(filter.Statuses) > 0 {
+		add("status = ANY($%d)", pq.Array(filter.Statuses))
+	}
+	if !filter.CreatedAfter.IsZero() {
+		add("created_at >= $%d", filter.CreatedAfter)
+	}
+	if !filter.CreatedBefore.IsZero() {
+		add("created_at < $%d", filter.CreatedBefore)
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	rejudge := &types.Rejudge{ID: uuid.New().String(), RequestedBy: requestedBy}
+	err = tx.QueryRow(`INSERT INTO rejudges (id, requested_by) VALUES ($1, $2) RETURNING created_at`,
+		rejudge.ID, requestedBy).Scan(&rejudge.CreatedAt)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create rejudge: %w", err)
+	}
+
+	// $1 is the rejudge id, the filter arguments follow it.
+	query := `INSERT INTO rejudge_submissions (rejudge_id, submission_id, previous_status, previous_score, previous_max_memory_kb)
+	          SELECT $1, id, status, score, max_memory_kb FROM submissions
+	          WHERE ` + strings.Join(conditions, " AND ") + `
+	          FOR UPDATE`
+	if _, err := tx.Exec(query, append([]any{rejudge.ID}, args...)...); err != nil {
+		return nil, nil, fmt.Errorf("failed to record previous verdicts: %w", err)
+	}
+
+	rows, err := tx.Query(`UPDATE submissions SET status = 'Pending', stage = '', current_test = 0, updated_at = NOW()
+	          WHERE id IN (SELECT submission_id FROM rejudge_submissions WHERE rejudge_id = $1)
+	          RETURNING id, problem_id, user_id, code, files, language, status, created_at, updated_at`, rejudge.ID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to reset submissions: %w", err)
+	}
+	defer rows.Close()
+
+	var submissions []*types.Submission
+	for rows.Next() {
+		sub := &types.Submission{}
+		var files []byte
+		if err := rows.Scan(&sub.ID, &sub.ProblemID, &sub.UserID, &sub.Code, &files, &sub.Language, &sub.Status, &sub.CreatedAt, &sub.UpdatedAt); err != nil {
+			return nil, nil, fmt.Errorf("failed to scan submission: %w", err)
+		}
+		if err := json.Unmarshal(files, &sub.Files); err != nil {
+			return nil, nil, fmt.Errorf("failed to unmarshal submission files: %w", err)
+		}
+		submissions = append(submissions, sub)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("failed to reset submissions: %w", err)
+	}
+
+	if err := queue(submissions); err != nil {
+		return nil, nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, nil, fmt.Errorf("failed to commit rejudge: %w", err)
+	}
+
+	return rejudge, submissions, nil
+}
+
+func (s *store) GetRejudge(id string) (*types.Rejudge, error) {
+	rejudge := &types.Rejudge{ID: id}
+	err := s.db.QueryRow(`SELECT requested_by, created_at FROM rejudges WHERE id = $1`, id).Scan(&rejudge.RequestedBy, &rejudge.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrRejudgeNotFound
+		}
+		return nil, fmt.Errorf("failed to get rejudge: %w", err)
+	}
+
+	rows, err := s.db.Query(`SELECT r.submission_id, r.previous_status, r.previous_score, s.status, s.score
+	          FROM rejudge_submissions r
+	          JOIN submissions s ON s.id = r.submission_id
+	          WHERE r.rejudge_id = $1 ORDER BY s.created_at`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rejudged submissions: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var entry types.RejudgeEntry
+		if err := rows.Scan(&entry.SubmissionID, &entry.PreviousStatus, &entry.PreviousScore, &entry.Status, &entry.Score); err != nil {
+			return nil, fmt.Errorf("failed to scan rejudged submission: %w", err)
+		}
+		rejudge.Entries = append(rejudge.Entries, entry)
+	}
+	return rejudge, rows.Err()
+}
@@ -0,0 +1,340 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/DeadlyParkour777/code-checker/services/submission_service/internal/types"
+	_ "github.com/lib/pq"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+)
+
+var testDB *sql.DB
+var testContainer *postgres.PostgresContainer
+
+func TestMain(m *testing.M) {
+	ctx := context.Background()
+	container, err := postgres.Run(
+		ctx,
+		"postgres:17-alpine",
+		postgres.WithDatabase("testdb"),
+		postgres.WithUsername("postgres"),
+		postgres.WithPassword("postgres"),
+		postgres.BasicWaitStrategies(),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	metaCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	port, err := container.MappedPort(metaCtx, "5432/tcp")
+	if err != nil {
+		_ = container.Terminate(ctx)
+		panic(err)
+	}
+
+	host := "127.0.0.1"
+	connStr := fmt.Sprintf("postgres://postgres:postgres@%s:%s/testdb?sslmode=disable&connect_timeout=5", host, port.Port())
+
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		_ = container.Terminate(ctx)
+		panic(err)
+	}
+
+	db.SetMaxOpenConns(1)
+	db.SetConnMaxLifetime(1 * time.Minute)
+	db.SetConnMaxIdleTime(5 * time.Second)
+
+	if err := waitForTCP(host, port.Port(), 20*time.Second); err != nil {
+		_ = db.Close()
+		_ = container.Terminate(ctx)
+		panic(err)
+	}
+
+	if err := waitForDB(db, 30*time.Second); err != nil {
+		dumpContainerLogs(ctx, container)
+		_ = db.Close()
+		_ = container.Terminate(ctx)
+		panic(err)
+	}
+
+	if err := createSchema(db); err != nil {
+		dumpContainerLogs(ctx, container)
+		_ = db.Close()
+		_ = container.Terminate(ctx)
+		panic(err)
+	}
+
+	testDB = db
+	testContainer = container
+
+	code := m.Run()
+
+	_ = testDB.Close()
+	_ = testContainer.Terminate(ctx)
+
+	os.Exit(code)
+}
+
+func dumpContainerLogs(ctx context.Context, container *postgres.PostgresContainer) {
+	logs, err := container.Logs(ctx)
+	if err != nil {
+		return
+	}
+	defer logs.Close()
+	_, _ = io.ReadAll(logs)
+}
+
+func waitForDB(db *sql.DB, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	var lastErr error
+	for time.Now().Before(deadline) {
+		pingCtx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+		err := db.PingContext(pingCtx)
+		cancel()
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+		time.Sleep(200 * time.Millisecond)
+	}
+	return lastErr
+}
+
+func waitForTCP(host, port string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	var lastErr error
+	for time.Now().Before(deadline) {
+		conn, err := net.DialTimeout("tcp", net.JoinHostPort(host, port), 1*time.Second)
+		if err == nil {
+			_ = conn.Close()
+			return nil
+		}
+		lastErr = err
+		time.Sleep(300 * time.Millisecond)
+	}
+	return lastErr
+}
+
+func createSchema(db *sql.DB) error {
+	statements := []string{
+		`CREATE TABLE IF NOT EXISTS submissions (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			problem_id UUID NOT NULL,
+			user_id UUID NOT NULL,
+			code TEXT NOT NULL,
+			files JSONB NOT NULL DEFAULT '[]',
+			language VARCHAR(50) NOT NULL,
+			status VARCHAR(50) NOT NULL,
+			max_memory_kb BIGINT NOT NULL DEFAULT 0,
+			score DOUBLE PRECISION NOT NULL DEFAULT 0,
+			max_score DOUBLE PRECISION NOT NULL DEFAULT 0,
+			stage VARCHAR(20) NOT NULL DEFAULT '',
+			current_test INTEGER NOT NULL DEFAULT 0,
+			total_tests INTEGER NOT NULL DEFAULT 0,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+		);`,
+		`CREATE TABLE IF NOT EXISTS rejudges (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			requested_by UUID NOT NULL,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+		);`,
+		`CREATE TABLE IF NOT EXISTS rejudge_submissions (
+			rejudge_id UUID NOT NULL REFERENCES rejudges(id) ON DELETE CASCADE,
+			submission_id UUID NOT NULL REFERENCES submissions(id) ON DELETE CASCADE,
+			previous_status VARCHAR(50) NOT NULL,
+			previous_score DOUBLE PRECISION NOT NULL DEFAULT 0,
+			previous_max_memory_kb BIGINT NOT NULL DEFAULT 0,
+			PRIMARY KEY (rejudge_id, submission_id)
+		);`,
+	}
+
+	for _, stmt := range statements {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		_, err := db.ExecContext(ctx, stmt)
+		cancel()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func resetDB(t *testing.T) {
+	t.Helper()
+	if _, err := testDB.Exec(`TRUNCATE TABLE rejudge_submissions, rejudges, submissions RESTART IDENTITY CASCADE`); err != nil {
+		t.Fatalf("failed to reset db: %v", err)
+	}
+}
+
+const (
+	testProblemA = "00000000-0000-0000-0000-00000000000a"
+	testProblemB = "00000000-0000-0000-0000-00000000000b"
+	testUser     = "00000000-0000-0000-0000-000000000001"
+)
+
+// createJudged adds a submission that has already got the verdict status.
+func createJudged(t *testing.T, s Store, problemID, status string, createdAt time.Time) string {
+	t.Helper()
+	submission, err := s.CreateSubmission(&types.Submission{ProblemID: problemID, UserID: testUser, Code: "code", Language: "go"})
+	if err != nil {
+		t.Fatalf("create submission: %v", err)
+	}
+	if _, err := testDB.Exec(`UPDATE submissions SET status = $2, score = 1, created_at = $3 WHERE id = $1`, submission.ID, status, createdAt); err != nil {
+		t.Fatalf("judge submission: %v", err)
+	}
+	return submission.ID
+}
+
+func TestStore_CreateRejudgeFilters(t *testing.T) {
+	day := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		filter func(ids map[string]string) types.RejudgeFilter
+		want   []string
+	}{
+		{
+			name:   "no filter matches every finished submission",
+			filter: func(map[string]string) types.RejudgeFilter { return types.RejudgeFilter{} },
+			want:   []string{"old", "wa", "ac", "other"},
+		},
+		{
+			name:   "submission id",
+			filter: func(ids map[string]string) types.RejudgeFilter { return types.RejudgeFilter{SubmissionID: ids["wa"]} },
+			want:   []string{"wa"},
+		},
+		{
+			name:   "problem id",
+			filter: func(map[string]string) types.RejudgeFilter { return types.RejudgeFilter{ProblemID: testProblemB} },
+			want:   []string{"other"},
+		},
+		{
+			name: "statuses",
+			filter: func(map[string]string) types.RejudgeFilter {
+				return types.RejudgeFilter{Statuses: []string{"WA", "TLE"}}
+			},
+			want: []string{"wa"},
+		},
+		{
+			name:   "created after",
+			filter: func(map[string]string) types.RejudgeFilter { return types.RejudgeFilter{CreatedAfter: day} },
+			want:   []string{"wa", "ac", "other"},
+		},
+		{
+			name:   "created before",
+			filter: func(map[string]string) types.RejudgeFilter { return types.RejudgeFilter{CreatedBefore: day} },
+			want:   []string{"old"},
+		},
+		{
+			name: "all fields together",
+			filter: func(ids map[string]string) types.RejudgeFilter {
+				return types.RejudgeFilter{
+					SubmissionID:  ids["ac"],
+					ProblemID:     testProblemA,
+					Statuses:      []string{"AC"},
+					CreatedAfter:  day,
+					CreatedBefore: day.Add(24 * time.Hour),
+				}
+			},
+			want: []string{"ac"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetDB(t)
+			s := NewStore(testDB)
+
+			ids := map[string]string{
+				"old":     createJudged(t, s, testProblemA, "AC", day.Add(-time.Hour)),
+				"wa":      createJudged(t, s, testProblemA, "WA", day),
+				"ac":      createJudged(t, s, testProblemA, "AC", day.Add(time.Hour)),
+				"other":   createJudged(t, s, testProblemB, "AC", day.Add(time.Hour)),
+				"pending": createJudged(t, s, testProblemA, "Pending", day.Add(time.Hour)),
+			}
+			names := make(map[string]string, len(ids))
+			for name, id := range ids {
+				names[id] = name
+			}
+
+			var queued []string
+			rejudge, submissions, err := s.CreateRejudge(testUser, tt.filter(ids), func(submissions []*types.Submission) error {
+				for _, submission := range submissions {
+					queued = append(queued, names[submission.ID])
+				}
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("create rejudge: %v", err)
+			}
+			slices.Sort(queued)
+			want := slices.Sorted(slices.Values(tt.want))
+			if !slices.Equal(queued, want) || len(submissions) != len(want) {
+				t.Fatalf("expected %v to be queued, got %v", want, queued)
+			}
+
+			fetched, err := s.GetRejudge(rejudge.ID)
+			if err != nil {
+				t.Fatalf("get rejudge: %v", err)
+			}
+			if len(fetched.Entries) != len(want) {
+				t.Fatalf("unexpected entries: %+v", fetched.Entries)
+			}
+			for _, entry := range fetched.Entries {
+				if entry.Status != "Pending" || entry.PreviousScore != 1 {
+					t.Fatalf("unexpected entry: %+v", entry)
+				}
+			}
+		})
+	}
+}
+
+func TestStore_CreateRejudgeQueueFailure(t *testing.T) {
+	resetDB(t)
+	s := NewStore(testDB)
+	id := createJudged(t, s, testProblemA, "WA", time.Now())
+
+	errQueue := errors.New("kafka is down")
+	_, _, err := s.CreateRejudge(testUser, types.RejudgeFilter{}, func([]*types.Submission) error {
+		return errQueue
+	})
+	if !errors.Is(err, errQueue) {
+		t.Fatalf("expected the queue error, got %v", err)
+	}
+
+	// Nothing was queued, so the submission keeps its verdict.
+	submission, err := s.GetSubmission(id)
+	if err != nil {
+		t.Fatalf("get submission: %v", err)
+	}
+	if submission.Status != "WA" {
+		t.Fatalf("expected the verdict to be kept, got %s", submission.Status)
+	}
+	var rejudges int
+	if err := testDB.QueryRow(`SELECT COUNT(*) FROM rejudges`).Scan(&rejudges); err != nil {
+		t.Fatalf("count rejudges: %v", err)
+	}
+	if rejudges != 0 {
+		t.Fatalf("expected the rejudge to be rolled back, got %d", rejudges)
+	}
+
+	if _, _, err := s.CreateRejudge(testUser, types.RejudgeFilter{}, func([]*types.Submission) error { return nil }); err != nil {
+		t.Fatalf("create rejudge: %v", err)
+	}
+	if submission, _ := s.GetSubmission(id); submission.Status != "Pending" {
+		t.Fatalf("expected the submission to be pending, got %s", submission.Status)
+	}
+}
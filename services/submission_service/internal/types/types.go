@@ -9,19 +9,62 @@ type Submission struct {
 	ProblemID string    `json:"problem_id"`
 	UserID    string    `json:"user_id"`
 	Code      string    `json:"code"`
+	Files     []File    `json:"files,omitempty"`
 	Language  string    `json:"language"`
 	Status    string    `json:"status"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+// File is a source file of a multi-file submission; Path is relative to the
+// submission directory and uses forward slashes.
+type File struct {
+	Path    string `json:"path"`
+	Content string `json:"content"`
+}
+
 type SubmissionEvent struct {
 	SubmissionID string `json:"submission_id"`
 	ProblemID    string `json:"problem_id"`
 	Code         string `json:"code"`
+	Files        []File `json:"files,omitempty"`
 	Language     string `json:"language"`
 }
 
+// RejudgeFilter selects finished submissions; zero fields match everything.
+type RejudgeFilter struct {
+	SubmissionID  string
+	ProblemID     string
+	Statuses      []string
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+}
+
+// RejudgeEntry compares a submission's verdict before a rejudge with the
+// current one.
+type RejudgeEntry struct {
+	SubmissionID   string
+	PreviousStatus string
+	PreviousScore  float64
+	Status         string
+	Score          float64
+}
+
+func (e RejudgeEntry) Pending() bool {
+	return e.Status == "Pending" || e.Status == "Judging"
+}
+
+func (e RejudgeEntry) Changed() bool {
+	return !e.Pending() && (e.Status != e.PreviousStatus || e.Score != e.PreviousScore)
+}
+
+type Rejudge struct {
+	ID          string
+	RequestedBy string
+	CreatedAt   time.Time
+	Entries     []RejudgeEntry
+}
+
 type UserLoginPayload struct {
 	Username string
 	Password string